@@ -0,0 +1,663 @@
+// Package auth0test runs a fake of the parts of the Auth0 Management API that SyncSpace uses, so
+// tests can create users, roles and permissions without a real tenant. It keeps everything in
+// memory and answers with the same shapes and status codes as Auth0.
+//
+//	fake := auth0test.NewServer()
+//	defer fake.Close()
+//	fake.Configure(cfg)
+//	fake.AddUser(models.User{UserID: "auth0|alice", Email: "alice@example.com"})
+package auth0test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const (
+	// ManagementToken is the only token the fake accepts for the management API
+	ManagementToken  = "auth0test-management-token"
+	ResourceServerId = "auth0test-resource-server"
+	Audience         = "auth0test-api"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	users      map[string]models.User
+	roles      map[string]*fakeRole
+	scopes     []scope
+	nextRoleId int
+	failures   []*failure
+}
+
+// failure makes requests fail until it has failed remaining of them
+type failure struct {
+	method    string
+	path      string
+	remaining int
+}
+
+type fakeRole struct {
+	auth.Role
+	permissions []string
+	users       map[string]bool
+}
+
+type scope struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+type permissionRef struct {
+	ResourceServerIdentifier string `json:"resource_server_identifier"`
+	PermissionName           string `json:"permission_name"`
+}
+
+func NewServer() *Server {
+	s := &Server{
+		users: make(map[string]models.User),
+		roles: make(map[string]*fakeRole),
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/oauth/token", s.token).Methods("POST")
+	api := router.PathPrefix("/api/v2").Subrouter()
+	api.Use(s.requireManagementToken)
+	api.HandleFunc("/users", s.getUsers).Methods("GET")
+	api.HandleFunc("/users/{userId}", s.getUser).Methods("GET")
+	api.HandleFunc("/users/{userId}", s.updateUser).Methods("PATCH")
+	api.HandleFunc("/users/{userId}", s.deleteUser).Methods("DELETE")
+	api.HandleFunc("/users/{userId}/permissions", s.getUserPermissions).Methods("GET")
+	api.HandleFunc("/users/{userId}/roles", s.getUserRoles).Methods("GET")
+	api.HandleFunc("/users/{userId}/roles", s.addUserRoles).Methods("POST")
+	api.HandleFunc("/users/{userId}/roles", s.removeUserRoles).Methods("DELETE")
+	api.HandleFunc("/roles", s.getRoles).Methods("GET")
+	api.HandleFunc("/roles", s.createRole).Methods("POST")
+	api.HandleFunc("/roles/{roleId}", s.getRole).Methods("GET")
+	api.HandleFunc("/roles/{roleId}", s.updateRole).Methods("PATCH")
+	api.HandleFunc("/roles/{roleId}", s.deleteRole).Methods("DELETE")
+	api.HandleFunc("/roles/{roleId}/permissions", s.getRolePermissions).Methods("GET")
+	api.HandleFunc("/roles/{roleId}/permissions", s.addRolePermissions).Methods("POST")
+	api.HandleFunc("/roles/{roleId}/permissions", s.removeRolePermissions).Methods("DELETE")
+	api.HandleFunc("/roles/{roleId}/users", s.getRoleUsers).Methods("GET")
+	api.HandleFunc("/resource-servers/{id}", s.getResourceServer).Methods("GET")
+	api.HandleFunc("/resource-servers/{id}", s.updateResourceServer).Methods("PATCH")
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("auth0test does not fake %s %s", r.Method, r.URL.Path))
+	})
+
+	s.Server = httptest.NewServer(router)
+	return s
+}
+
+// Configure points cfg at the fake. Since the auth package reads the shared config, pass it the
+// *config.Config returned by config.Get.
+func (s *Server) Configure(cfg *config.Config) {
+	cfg.Auth0.Domain = s.URL + "/"
+	cfg.Auth0.Server.Id = ResourceServerId
+	cfg.Auth0.Server.Audience = Audience
+	cfg.Auth0.Server.ClientId = "auth0test-client"
+	cfg.Auth0.Server.ClientSecret = "auth0test-secret"
+}
+
+// AddUser creates or replaces a user
+func (s *Server) AddUser(user models.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.UserID] = user
+}
+
+// UserPermissions is what Auth0 would put in an access token for the user right now: the
+// permissions of all of their roles
+func (s *Server) UserPermissions(userId string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userPermissions(userId)
+}
+
+// Roles returns every role, sorted by name
+func (s *Server) Roles() []auth.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	roles := make([]auth.Role, 0, len(s.roles))
+	for _, role := range s.sortedRoles() {
+		roles = append(roles, role.Role)
+	}
+	return roles
+}
+
+// Permissions returns the names of all permissions defined on the API
+func (s *Server) Permissions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.scopes))
+	for i, scope := range s.scopes {
+		names[i] = scope.Value
+	}
+	return names
+}
+
+// FailRequests makes the next times management API requests with method and path fail with a 503,
+// like Auth0 does now and then. path is everything after /api/v2, like /roles, and a trailing *
+// matches anything starting with the rest of it.
+func (s *Server) FailRequests(method string, path string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{method: method, path: path, remaining: times})
+}
+
+// fail reports whether the request should fail because of FailRequests, counting it if so
+func (s *Server) fail(r *http.Request) bool {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v2")
+	for _, failure := range s.failures {
+		if failure.remaining == 0 || failure.method != r.Method {
+			continue
+		}
+		prefix, isPrefix := strings.CutSuffix(failure.path, "*")
+		if path == failure.path || (isPrefix && strings.HasPrefix(path, prefix)) {
+			failure.remaining--
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) userPermissions(userId string) []string {
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, role := range s.sortedRoles() {
+		if !role.users[userId] {
+			continue
+		}
+		for _, permission := range role.permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+func (s *Server) sortedRoles() []*fakeRole {
+	roles := make([]*fakeRole, 0, len(s.roles))
+	for _, role := range s.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles
+}
+
+func (s *Server) scopeDescription(name string) (string, bool) {
+	for _, scope := range s.scopes {
+		if scope.Value == name {
+			return scope.Description, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) requireManagementToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "bearer") || token != ManagementToken {
+			writeError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fail(r) {
+			writeError(w, http.StatusServiceUnavailable, "auth0test was told to fail this request")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		GrantType string `json:"grant_type"`
+		ClientId  string `json:"client_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.GrantType != "client_credentials" || body.ClientId == "" {
+		writeError(w, http.StatusForbidden, "Unauthorized")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": ManagementToken,
+		"token_type":   "Bearer",
+		"expires_in":   86400,
+	})
+}
+
+func (s *Server) getUsers(w http.ResponseWriter, r *http.Request) {
+	users := make([]models.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.users[mux.Vars(r)["userId"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The user does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.users[mux.Vars(r)["userId"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The user does not exist.")
+		return
+	}
+	var body struct {
+		Email    *string `json:"email"`
+		Username *string `json:"username"`
+		Picture  *string `json:"picture"`
+		Name     *string `json:"name"`
+		Nickname *string `json:"nickname"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Auth0 refuses to change the email along with the username
+	if body.Email != nil && body.Username != nil {
+		writeError(w, http.StatusBadRequest, "Cannot update username and email simultaneously")
+		return
+	}
+	if body.Email != nil {
+		user.Email = *body.Email
+	}
+	if body.Username != nil {
+		user.Username = *body.Username
+	}
+	if body.Picture != nil {
+		user.Picture = *body.Picture
+	}
+	if body.Name != nil {
+		user.Name = *body.Name
+	}
+	if body.Nickname != nil {
+		user.Nickname = *body.Nickname
+	}
+	s.users[user.UserID] = user
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	userId := mux.Vars(r)["userId"]
+	delete(s.users, userId)
+	for _, role := range s.roles {
+		delete(role.users, userId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getUserPermissions(w http.ResponseWriter, r *http.Request) {
+	userId := mux.Vars(r)["userId"]
+	if _, ok := s.users[userId]; !ok {
+		writeError(w, http.StatusNotFound, "The user does not exist.")
+		return
+	}
+	permissions := make([]map[string]string, 0)
+	for _, name := range s.userPermissions(userId) {
+		description, _ := s.scopeDescription(name)
+		permissions = append(permissions, map[string]string{
+			"permission_name":            name,
+			"description":                description,
+			"resource_server_identifier": Audience,
+		})
+	}
+	writeJSON(w, http.StatusOK, permissions)
+}
+
+func (s *Server) getUserRoles(w http.ResponseWriter, r *http.Request) {
+	userId := mux.Vars(r)["userId"]
+	roles := make([]auth.Role, 0)
+	for _, role := range s.sortedRoles() {
+		if role.users[userId] {
+			roles = append(roles, role.Role)
+		}
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *Server) addUserRoles(w http.ResponseWriter, r *http.Request) {
+	userId := mux.Vars(r)["userId"]
+	roles, ok := s.readRoleIds(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.users[userId]; !ok {
+		writeError(w, http.StatusNotFound, "The user does not exist.")
+		return
+	}
+	for _, role := range roles {
+		role.users[userId] = true
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeUserRoles(w http.ResponseWriter, r *http.Request) {
+	userId := mux.Vars(r)["userId"]
+	roles, ok := s.readRoleIds(w, r)
+	if !ok {
+		return
+	}
+	for _, role := range roles {
+		delete(role.users, userId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) readRoleIds(w http.ResponseWriter, r *http.Request) ([]*fakeRole, bool) {
+	var body struct {
+		Roles []string `json:"roles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if len(body.Roles) == 0 {
+		writeError(w, http.StatusBadRequest, "Payload validation error: 'Too few items (0), minimum 1' on property roles.")
+		return nil, false
+	}
+	roles := make([]*fakeRole, len(body.Roles))
+	for i, roleId := range body.Roles {
+		role, ok := s.roles[roleId]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("The role %s does not exist.", roleId))
+			return nil, false
+		}
+		roles[i] = role
+	}
+	return roles, true
+}
+
+// getRoles filters like Auth0's name_filter, which matches any role whose name contains the
+// filter, ignoring case
+func (s *Server) getRoles(w http.ResponseWriter, r *http.Request) {
+	filter := strings.ToLower(r.URL.Query().Get("name_filter"))
+	roles := make([]auth.Role, 0)
+	for _, role := range s.sortedRoles() {
+		if strings.Contains(strings.ToLower(role.Name), filter) {
+			roles = append(roles, role.Role)
+		}
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "Payload validation error: 'Missing required property: name'.")
+		return
+	}
+	for _, existing := range s.roles {
+		if existing.Name == body.Name {
+			writeError(w, http.StatusConflict, "Role name already exists")
+			return
+		}
+	}
+	s.nextRoleId++
+	created := &fakeRole{
+		Role:  auth.Role{Id: fmt.Sprintf("rol_%d", s.nextRoleId), Name: body.Name, Description: body.Description},
+		users: make(map[string]bool),
+	}
+	s.roles[created.Id] = created
+	writeJSON(w, http.StatusOK, created.Role)
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, found.Role)
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Name != nil {
+		found.Name = *body.Name
+	}
+	if body.Description != nil {
+		found.Description = *body.Description
+	}
+	writeJSON(w, http.StatusOK, found.Role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	delete(s.roles, found.Id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getRolePermissions(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	permissions := make([]map[string]string, 0, len(found.permissions))
+	for _, name := range found.permissions {
+		description, _ := s.scopeDescription(name)
+		permissions = append(permissions, map[string]string{
+			"permission_name":            name,
+			"description":                description,
+			"resource_server_identifier": Audience,
+		})
+	}
+	writeJSON(w, http.StatusOK, permissions)
+}
+
+func (s *Server) addRolePermissions(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	permissions, ok := s.readPermissionRefs(w, r)
+	if !ok {
+		return
+	}
+	for _, permission := range permissions {
+		if _, exists := s.scopeDescription(permission); !exists {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Permission %s does not exist on the resource server", permission))
+			return
+		}
+	}
+	for _, permission := range permissions {
+		if !contains(found.permissions, permission) {
+			found.permissions = append(found.permissions, permission)
+		}
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) removeRolePermissions(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	permissions, ok := s.readPermissionRefs(w, r)
+	if !ok {
+		return
+	}
+	kept := make([]string, 0, len(found.permissions))
+	for _, permission := range found.permissions {
+		if !contains(permissions, permission) {
+			kept = append(kept, permission)
+		}
+	}
+	found.permissions = kept
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) readPermissionRefs(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	var body struct {
+		Permissions []permissionRef `json:"permissions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if len(body.Permissions) == 0 {
+		writeError(w, http.StatusBadRequest, "Payload validation error: 'Too few items (0), minimum 1' on property permissions.")
+		return nil, false
+	}
+	names := make([]string, len(body.Permissions))
+	for i, permission := range body.Permissions {
+		if permission.ResourceServerIdentifier != Audience {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Resource server %s does not exist", permission.ResourceServerIdentifier))
+			return nil, false
+		}
+		names[i] = permission.PermissionName
+	}
+	return names, true
+}
+
+func (s *Server) getRoleUsers(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.findRole(w, r)
+	if !ok {
+		return
+	}
+	userIds := make([]string, 0, len(found.users))
+	for userId := range found.users {
+		userIds = append(userIds, userId)
+	}
+	sort.Strings(userIds)
+	users := make([]map[string]string, len(userIds))
+	for i, userId := range userIds {
+		user := s.users[userId]
+		users[i] = map[string]string{
+			"user_id": userId,
+			"email":   user.Email,
+			"picture": user.Picture,
+			"name":    user.Name,
+		}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) getResourceServer(w http.ResponseWriter, r *http.Request) {
+	if mux.Vars(r)["id"] != ResourceServerId {
+		writeError(w, http.StatusNotFound, "The resource server does not exist")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         ResourceServerId,
+		"identifier": Audience,
+		"scopes":     s.scopes,
+	})
+}
+
+// updateResourceServer replaces all of the API's permissions, like Auth0 does when scopes is sent
+func (s *Server) updateResourceServer(w http.ResponseWriter, r *http.Request) {
+	if mux.Vars(r)["id"] != ResourceServerId {
+		writeError(w, http.StatusNotFound, "The resource server does not exist")
+		return
+	}
+	var body struct {
+		Scopes *[]scope `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Scopes != nil {
+		s.scopes = *body.Scopes
+		// permissions that no longer exist are taken away from roles too
+		for _, role := range s.roles {
+			kept := make([]string, 0, len(role.permissions))
+			for _, permission := range role.permissions {
+				if _, exists := s.scopeDescription(permission); exists {
+					kept = append(kept, permission)
+				}
+			}
+			role.permissions = kept
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         ResourceServerId,
+		"identifier": Audience,
+		"scopes":     s.scopes,
+	})
+}
+
+func (s *Server) findRole(w http.ResponseWriter, r *http.Request) (*fakeRole, bool) {
+	roleId := mux.Vars(r)["roleId"]
+	found, ok := s.roles[roleId]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The role %s does not exist.", roleId))
+	}
+	return found, ok
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError answers in Auth0's error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"statusCode": status,
+		"error":      http.StatusText(status),
+		"message":    message,
+	})
+}
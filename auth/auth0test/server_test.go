@@ -0,0 +1,180 @@
+package auth0test_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+func newFake(t *testing.T) *auth0test.Server {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	return fake
+}
+
+func TestRolesAndPermissions(t *testing.T) {
+	fake := newFake(t)
+	fake.AddUser(models.User{UserID: "auth0|alice", Email: "alice@example.com"})
+
+	err := auth.CreatePermissions([]auth.Permission{{Name: "orgA:read"}, {Name: "orgA:update"}})
+	if err != nil {
+		t.Fatalf("failed to create permissions: %v", err)
+	}
+	member, err := auth.CreateRole("orgA:member", "Member")
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	if _, err := auth.CreateRole("orgA:owner", "Owner"); err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	if err := auth.AddPermissionsToRole(member.Id, []string{"orgA:read"}); err != nil {
+		t.Fatalf("failed to add permissions: %v", err)
+	}
+	if err := auth.AddPermissionsToRole(member.Id, []string{"orgB:read"}); err == nil {
+		t.Error("expected adding a permission that doesn't exist to fail")
+	}
+	if err := auth.AddUserToRole("auth0|alice", member.Id); err != nil {
+		t.Fatalf("failed to add user to role: %v", err)
+	}
+
+	permissions, err := auth.GetUserPermissions("auth0|alice")
+	if err != nil {
+		t.Fatalf("failed to get user permissions: %v", err)
+	}
+	if len(*permissions) != 1 || (*permissions)[0].Name != "orgA:read" {
+		t.Errorf("expected orgA:read, got %+v", *permissions)
+	}
+	filter := "orgA:"
+	roles, err := auth.GetRoles(&filter)
+	if err != nil || len(*roles) != 2 {
+		t.Errorf("expected both orgA roles, got %+v, %v", roles, err)
+	}
+	members, err := user.GetUsersWithRole(member.Id)
+	if err != nil || len(*members) != 1 || (*members)[0].Email != "alice@example.com" {
+		t.Errorf("expected alice to have the member role, got %+v, %v", members, err)
+	}
+
+	if err := auth.DeletePermissions([]auth.Permission{{Name: "orgA:read"}}); err != nil {
+		t.Fatalf("failed to delete permissions: %v", err)
+	}
+	if got := fake.UserPermissions("auth0|alice"); len(got) != 0 {
+		t.Errorf("expected deleted permissions to be taken from roles, got %v", got)
+	}
+	if got := fake.Permissions(); !reflect.DeepEqual(got, []string{"orgA:update"}) {
+		t.Errorf("expected only orgA:update to be left, got %v", got)
+	}
+	if err := auth.RemoveUserFromRoles("auth0|alice", []string{member.Id}); err != nil {
+		t.Fatalf("failed to remove user from roles: %v", err)
+	}
+	if err := auth.DeleteRole(member.Id); err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+	if _, err := auth.GetRoleById(member.Id); err == nil {
+		t.Error("expected the deleted role to be gone")
+	}
+}
+
+func TestConcurrentPermissionChangesKeepEachOthersPermissions(t *testing.T) {
+	newFake(t)
+	err := auth.CreatePermissions([]auth.Permission{{Name: "org0:read"}})
+	if err != nil {
+		t.Fatalf("failed to create permissions: %v", err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- auth.CreatePermissions([]auth.Permission{{Name: fmt.Sprintf("org%d:read", i)}})
+		}(i)
+		go func() {
+			defer wg.Done()
+			errs <- auth.DeletePermissions([]auth.Permission{{Name: "org0:read"}})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("failed to change permissions: %v", err)
+		}
+	}
+	permissions, err := auth.GetPermissions()
+	if err != nil {
+		t.Fatalf("failed to get permissions: %v", err)
+	}
+	if len(*permissions) != 10 {
+		t.Fatalf("expected every created permission to be kept, got %v", *permissions)
+	}
+}
+
+func TestRejectsDuplicateRoleNames(t *testing.T) {
+	newFake(t)
+	if _, err := auth.CreateRole("org1:member", ""); err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	if _, err := auth.CreateRole("org1:member", ""); err == nil {
+		t.Error("expected a second role with the same name to fail")
+	}
+}
+
+func TestFailRequests(t *testing.T) {
+	fake := newFake(t)
+	fake.FailRequests("POST", "/roles*", 1)
+	if _, err := auth.CreateRole("org1:member", ""); err == nil {
+		t.Fatal("expected the first role to fail")
+	}
+	if _, err := auth.CreateRole("org1:member", ""); err != nil {
+		t.Fatalf("expected the second role to be created, got %v", err)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	fake := newFake(t)
+	fake.AddUser(models.User{UserID: "auth0|bob", Username: "bob"})
+	cfg, _ := config.Get()
+	controller := user.NewController(cfg, store.NewMemory())
+
+	if err := controller.UpdateUserById("auth0|bob", "bob@example.com", "bobby", "", nil); err != nil {
+		t.Fatalf("failed to update user: %v", err)
+	}
+	bob, err := controller.GetUserById("auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	if bob.Username != "bobby" || bob.Email != "bob@example.com" {
+		t.Errorf("expected bobby with bob@example.com, got %s with %s", bob.Username, bob.Email)
+	}
+	users, err := controller.GetUsers()
+	if err != nil || len(*users) != 1 {
+		t.Errorf("expected 1 user, got %+v, %v", users, err)
+	}
+}
+
+func TestRequiresManagementToken(t *testing.T) {
+	fake := newFake(t)
+	response, err := fake.Client().Get(fake.URL + "/api/v2/roles")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", response.StatusCode)
+	}
+}
@@ -2,9 +2,11 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Sync-Space-49/syncspace-server/config"
@@ -20,39 +22,41 @@ func (c CustomClaims) Validate(ctx context.Context) error {
 	return nil
 }
 
+// ErrorHandler writes the response when a request's token is missing or invalid. The routers
+// replace it so these responses use the same error format as the rest of the API.
+var ErrorHandler jwtmiddleware.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"message":"Failed to validate JWT."}`))
+}
+
+const (
+	PersonalAccessTokenPrefix = "ssp_"
+	APIKeyPrefix              = "ssk_"
+)
+
+// TokenAuthenticator checks personal access tokens and API keys, which are stored in the database
+// rather than signed by Auth0. The routers set it up; until then only JWTs are accepted.
+var TokenAuthenticator func(ctx context.Context, token string) (*validator.ValidatedClaims, error)
+
+func IsStoredToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix) || strings.HasPrefix(token, APIKeyPrefix)
+}
+
 // EnsureValidToken is a middleware that will check the validity of our JWT.
 func EnsureValidToken() func(next http.Handler) http.Handler {
 	cfg, err := config.Get()
 	if err != nil {
 		log.Fatalf("failed to get config: %v", err)
 	}
-	issuerURL, err := url.Parse(cfg.Auth0.Domain)
-	if err != nil {
-		log.Fatalf("Failed to parse the issuer url: %v", err)
-	}
-
-	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
-	jwtSeverValidator, err := validator.New(
-		provider.KeyFunc,
-		validator.RS256,
-		issuerURL.String(),
-		[]string{cfg.Auth0.Server.Audience},
-		validator.WithCustomClaims(
-			func() validator.CustomClaims {
-				return &CustomClaims{}
-			},
-		),
-		validator.WithAllowedClockSkew(time.Minute),
-	)
+	jwtSeverValidator, err := newValidator(cfg)
 	if err != nil {
-		log.Fatalf("Failed to set up the jwt validator")
+		log.Fatalf("Failed to set up the jwt validator: %v", err)
 	}
 
 	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
 		log.Printf("Encountered error while validating JWT: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"message":"Failed to validate JWT."}`))
+		ErrorHandler(w, r, err)
 	}
 
 	middleware := jwtmiddleware.New(
@@ -61,6 +65,49 @@ func EnsureValidToken() func(next http.Handler) http.Handler {
 	)
 
 	return func(next http.Handler) http.Handler {
-		return middleware.CheckJWT(next)
+		checkJWT := middleware.CheckJWT(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := jwtmiddleware.AuthHeaderTokenExtractor(r)
+			if err != nil || TokenAuthenticator == nil || !IsStoredToken(token) {
+				checkJWT.ServeHTTP(w, r)
+				return
+			}
+			claims, err := TokenAuthenticator(r.Context(), token)
+			if err != nil {
+				log.Printf("Encountered error while validating token: %v", err)
+				ErrorHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), jwtmiddleware.ContextKey{}, claims)))
+		})
+	}
+}
+
+// newValidator checks tokens from Auth0, or tokens signed with JWT_SECRET in local auth mode
+func newValidator(cfg *config.Config) (*validator.Validator, error) {
+	customClaims := validator.WithCustomClaims(
+		func() validator.CustomClaims {
+			return &CustomClaims{}
+		},
+	)
+	clockSkew := validator.WithAllowedClockSkew(time.Minute)
+
+	switch cfg.AuthMode {
+	case config.AUTH_MODE_AUTH0:
+		issuerURL, err := url.Parse(cfg.Auth0.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the issuer url: %w", err)
+		}
+		provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
+		return validator.New(provider.KeyFunc, validator.RS256, issuerURL.String(), []string{cfg.Auth0.Server.Audience}, customClaims, clockSkew)
+	case config.AUTH_MODE_LOCAL:
+		if err := checkLocalMode(cfg); err != nil {
+			return nil, err
+		}
+		keyFunc := func(ctx context.Context) (interface{}, error) {
+			return []byte(cfg.JWTSecret), nil
+		}
+		return validator.New(keyFunc, validator.HS256, LocalIssuer, []string{cfg.Auth0.Server.Audience}, customClaims, clockSkew)
 	}
+	return nil, fmt.Errorf("unknown auth mode %q", cfg.AuthMode)
 }
@@ -4,6 +4,80 @@ import (
 	"strings"
 )
 
+// orgPermissionImplications lists permissions that stand for more than their name, keyed by what
+// comes after org<id>:. Having one of them means having everything it implies in the same
+// organization, so handlers only need to check for the specific permission.
+var orgPermissionImplications = map[string][]string{
+	"boards_admin": {"create_boards", "board*:*"},
+}
+
+// PermissionSet decides whether a list of granted permissions allows a permission. Permissions are
+// split into : separated segments and granted ones can use wildcards, either inside a segment
+// (org<id>:board*:read reads every board) or as the last segment to cover everything under it
+// (org<id>:* is everything in the organization).
+type PermissionSet []string
+
+func NewPermissionSet(granted []string) PermissionSet {
+	set := make(PermissionSet, 0, len(granted))
+	for _, permission := range granted {
+		set = append(set, permission)
+		org, rest, found := strings.Cut(permission, ":")
+		if !found || !strings.HasPrefix(org, "org") {
+			continue
+		}
+		for _, implied := range orgPermissionImplications[rest] {
+			set = append(set, org+":"+implied)
+		}
+	}
+	return set
+}
+
+func (s PermissionSet) Allows(permissionName string) bool {
+	for _, pattern := range s {
+		if MatchPermission(pattern, permissionName) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPermission reports whether the granted pattern covers permissionName. Wildcards in
+// permissionName are taken literally, so a pattern only covers a wildcard permission that is at
+// most as broad as itself.
+func MatchPermission(pattern string, permissionName string) bool {
+	patternSegments := strings.Split(pattern, ":")
+	segments := strings.Split(permissionName, ":")
+	for i, patternSegment := range patternSegments {
+		if patternSegment == "*" && i == len(patternSegments)-1 {
+			return len(segments) > i
+		}
+		if i >= len(segments) || !matchSegment(patternSegment, segments[i]) {
+			return false
+		}
+	}
+	return len(segments) == len(patternSegments)
+}
+
+// matchSegment matches a single segment, where * in the pattern stands for any run of characters
+func matchSegment(pattern string, segment string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == segment
+	}
+	if !strings.HasPrefix(segment, parts[0]) {
+		return false
+	}
+	segment = segment[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(segment, part)
+		if index < 0 {
+			return false
+		}
+		segment = segment[index+len(part):]
+	}
+	return strings.HasSuffix(segment, parts[len(parts)-1])
+}
+
 func (c CustomClaims) HasScope(expectedScope string) bool {
 	result := strings.Split(c.Scope, " ")
 	for i := range result {
@@ -15,17 +89,13 @@ func (c CustomClaims) HasScope(expectedScope string) bool {
 }
 
 func (c CustomClaims) HasPermission(permissionName string) bool {
-	for _, permission := range c.Permissions {
-		if permission == permissionName {
-			return true
-		}
-	}
-	return false
+	return NewPermissionSet(c.Permissions).Allows(permissionName)
 }
 
 func (c CustomClaims) HasAnyPermissions(permissionNames ...string) bool {
+	permissions := NewPermissionSet(c.Permissions)
 	for _, permissionName := range permissionNames {
-		if c.HasPermission(permissionName) {
+		if permissions.Allows(permissionName) {
 			return true
 		}
 	}
@@ -33,11 +103,11 @@ func (c CustomClaims) HasAnyPermissions(permissionNames ...string) bool {
 }
 
 func (c CustomClaims) HasAllPermissions(permissionNames ...string) bool {
-	foundPermissions := make([]string, 0)
+	permissions := NewPermissionSet(c.Permissions)
 	for _, permissionName := range permissionNames {
-		if c.HasPermission(permissionName) {
-			foundPermissions = append(foundPermissions, permissionName)
+		if !permissions.Allows(permissionName) {
+			return false
 		}
 	}
-	return len(foundPermissions) == len(permissionNames)
+	return true
 }
@@ -0,0 +1,42 @@
+package auth
+
+import "testing"
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		pattern        string
+		permissionName string
+		want           bool
+	}{
+		{"org1:read", "org1:read", true},
+		{"org1:read", "org2:read", false},
+		{"org1:*", "org1:read", true},
+		{"org1:*", "org1:board5:update_card", true},
+		{"org1:*", "org1", false},
+		{"org1:*", "org10:read", false},
+		{"org1:board*:read", "org1:board5:read", true},
+		{"org1:board*:read", "org1:board5:delete", false},
+		{"org1:board*:read", "org1:board5:panel2:read", false},
+		{"org*:read", "org1:read", true},
+		{"org1:*:read", "org1:board5:read", true},
+		{"org1:board5:read", "org1:board*:read", false},
+		{"org1:board*:*", "org1:board*:read", true},
+		{"*", "org1:board5:read", true},
+	}
+	for _, test := range tests {
+		got := MatchPermission(test.pattern, test.permissionName)
+		if got != test.want {
+			t.Errorf("MatchPermission(%q, %q) = %v, want %v", test.pattern, test.permissionName, got, test.want)
+		}
+	}
+}
+
+func TestBoardsAdminImpliesBoardPermissions(t *testing.T) {
+	claims := CustomClaims{Permissions: []string{"org1:read", "org1:boards_admin"}}
+	if !claims.HasAllPermissions("org1:board5:delete", "org1:board6:add_members", "org1:create_boards") {
+		t.Fatalf("expected boards_admin to cover every board in the organization")
+	}
+	if claims.HasAnyPermissions("org1:delete", "org2:board5:read") {
+		t.Fatalf("expected boards_admin to stay within boards of its organization")
+	}
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// How many times DeleteAccess tries before giving up, and how long it waits after the first
+// failure. The wait doubles after every failure.
+const (
+	deleteAccessAttempts   = 4
+	deleteAccessFirstDelay = 500 * time.Millisecond
+)
+
+// DeleteAccess deletes every role and permission whose name starts with prefix, like the
+// org<id>:board<id>: ones a board is set up with. The Management API fails now and then, so it
+// tries a few times before returning the last error. Roles and permissions are looked up again on
+// every try, so anything deleted by an earlier one is skipped.
+func DeleteAccess(ctx context.Context, prefix string) error {
+	delay := deleteAccessFirstDelay
+	var err error
+	for attempt := 1; attempt <= deleteAccessAttempts; attempt++ {
+		err = deleteAccess(prefix)
+		if err == nil {
+			return nil
+		}
+		if attempt == deleteAccessAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("failed to delete roles and permissions starting with %s after %d attempts: %w", prefix, deleteAccessAttempts, err)
+}
+
+func deleteAccess(prefix string) error {
+	roles, err := GetRoles(&prefix)
+	if err != nil {
+		return err
+	}
+	// The name filter isn't anchored, so only delete the roles it found that really start with prefix
+	for _, role := range *roles {
+		if !strings.HasPrefix(role.Name, prefix) {
+			continue
+		}
+		err = DeleteRole(role.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	permissions, err := GetPermissions()
+	if err != nil {
+		return err
+	}
+	matching := make([]Permission, 0)
+	for _, permission := range *permissions {
+		if strings.HasPrefix(permission.Name, prefix) {
+			matching = append(matching, permission)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	return DeletePermissions(matching)
+}
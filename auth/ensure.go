@@ -0,0 +1,35 @@
+package auth
+
+// EnsureRole returns the role named roleName, creating it if there isn't one yet, so setting up a
+// board or organization can be retried after failing part way through
+func EnsureRole(roleName string, roleDescription string) (*Role, error) {
+	roles, err := GetRoles(&roleName)
+	if err != nil {
+		return nil, err
+	}
+	// The name filter isn't exact, so look for the role with exactly this name
+	for _, role := range *roles {
+		if role.Name == roleName {
+			return &role, nil
+		}
+	}
+	return CreateRole(roleName, roleDescription)
+}
+
+// DeleteRoleNamed deletes the role named roleName if there is one
+func DeleteRoleNamed(roleName string) error {
+	roles, err := GetRoles(&roleName)
+	if err != nil {
+		return err
+	}
+	for _, role := range *roles {
+		if role.Name != roleName {
+			continue
+		}
+		err = DeleteRole(role.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+)
+
+func TestLocalModeNeedsALongSecret(t *testing.T) {
+	for _, test := range []struct {
+		environment config.ENV
+		secret      string
+		allowed     bool
+	}{
+		{config.ENV_LOCAL, "", false},
+		{config.ENV_LOCAL, "bruh", false},
+		{config.ENV_LOCAL, strings.Repeat("a", MinLocalSecretLength-1), false},
+		{config.ENV_LOCAL, strings.Repeat("a", MinLocalSecretLength), true},
+		{config.ENV_PROD, strings.Repeat("a", MinLocalSecretLength), false},
+	} {
+		cfg := &config.Config{Environment: test.environment, JWTSecret: test.secret, AuthMode: config.AUTH_MODE_LOCAL}
+		_, err := NewLocalToken(cfg, "user1", nil, time.Hour)
+		if (err == nil) != test.allowed {
+			t.Errorf("expected a %d character secret in %s to be allowed: %v, got %v", len(test.secret), test.environment, test.allowed, err)
+		}
+		_, err = newValidator(cfg)
+		if (err == nil) != test.allowed {
+			t.Errorf("expected the validator for a %d character secret in %s to be allowed: %v, got %v", len(test.secret), test.environment, test.allowed, err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package auth
 type CustomClaims struct {
 	Permissions []string `json:"permissions"`
 	Scope       string   `json:"scope"`
+	// TokenId is set when the request used a personal access token or API key instead of a JWT
+	TokenId string `json:"-"`
 }
 
 type Role struct {
@@ -6,11 +6,43 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/Sync-Space-49/syncspace-server/cache"
 	"github.com/Sync-Space-49/syncspace-server/config"
 )
 
+// Changes to the API's permissions are made one at a time. The Management API can only replace the
+// whole scope list, so each change reads it and writes it back, and two changes running at once
+// would drop each other's permissions. scopesMu covers this server, and sharedScopesLock, if set,
+// covers every server sharing the Auth0 tenant.
+var (
+	scopesMu         sync.Mutex
+	sharedScopesLock func() (unlock func(), err error)
+)
+
+// ShareScopesLock makes changes to the API's permissions also hold lock, which should be shared
+// with the other servers, like a Postgres advisory lock. It has to be called before any are made.
+func ShareScopesLock(lock func() (unlock func(), err error)) {
+	sharedScopesLock = lock
+}
+
+func lockScopes() (func(), error) {
+	scopesMu.Lock()
+	if sharedScopesLock == nil {
+		return scopesMu.Unlock, nil
+	}
+	unlockShared, err := sharedScopesLock()
+	if err != nil {
+		scopesMu.Unlock()
+		return nil, fmt.Errorf("failed to lock permissions: %w", err)
+	}
+	return func() {
+		unlockShared()
+		scopesMu.Unlock()
+	}, nil
+}
+
 func GetManagementToken() (string, error) {
 	var managementToken string
 	tokenCache := cache.Get()
@@ -142,6 +174,11 @@ func GetUserPermissions(userId string) (*[]Permission, error) {
 }
 
 func CreatePermission(permission Permission) error {
+	unlock, err := lockScopes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	cfg, err := config.Get()
 	if err != nil {
 		return err
@@ -189,7 +226,13 @@ func CreatePermission(permission Permission) error {
 	return nil
 }
 
+// CreatePermissions adds the permissions to the API that it doesn't already have
 func CreatePermissions(permissions []Permission) error {
+	unlock, err := lockScopes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	cfg, err := config.Get()
 	if err != nil {
 		return err
@@ -202,7 +245,22 @@ func CreatePermissions(permissions []Permission) error {
 	if err != nil {
 		return err
 	}
-	permissions = append(*serverPermissions, permissions...)
+	// Skip the permissions that already exist, so creating them again after a failure is safe
+	existing := make(map[string]bool, len(*serverPermissions))
+	for _, permission := range *serverPermissions {
+		existing[permission.Name] = true
+	}
+	missing := make([]Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		if !existing[permission.Name] {
+			existing[permission.Name] = true
+			missing = append(missing, permission)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	permissions = append(*serverPermissions, missing...)
 	url := fmt.Sprintf("%sapi/v2/resource-servers/%s", cfg.Auth0.Domain, cfg.Auth0.Server.Id)
 	formattedPermissions := `{ "scopes": [ `
 	for i, permission := range permissions {
@@ -238,6 +296,11 @@ func CreatePermissions(permissions []Permission) error {
 }
 
 func DeletePermissions(permissions []Permission) error {
+	unlock, err := lockScopes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	cfg, err := config.Get()
 	if err != nil {
 		return err
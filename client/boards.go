@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const boardPath = "/api/organizations/%s/boards/%s"
+
+type BoardInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type AIBoardInput struct {
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	DetailLevel        string `json:"detail_level,omitempty"`
+	StoryPointType     string `json:"story_point_type,omitempty"`
+	StoryPointExamples string `json:"story_point_examples,omitempty"`
+}
+
+type ListBoardsOptions struct {
+	IncludeArchived bool
+}
+
+func (c *Client) ListBoards(organizationId string, options *ListBoardsOptions) *Iterator[models.Board] {
+	if options == nil {
+		options = &ListBoardsOptions{}
+	}
+	return newIterator[models.Board](c, request{
+		method: http.MethodGet,
+		path:   path("/api/organizations/%s/boards", organizationId),
+		query:  listQuery(options.IncludeArchived),
+	})
+}
+
+// CreateBoard returns the board while it is still provisioning. Get it with GetBoard until its
+// provisioning_status is ready before relying on its roles.
+func (c *Client) CreateBoard(ctx context.Context, organizationId string, input BoardInput) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodPost, path: path("/api/organizations/%s/boards", organizationId), body: input})
+}
+
+// CreateBoardWithAI queues the board to be generated and returns the job generating it. Follow it
+// with GetBoardGeneration until its status is succeeded or dead.
+func (c *Client) CreateBoardWithAI(ctx context.Context, organizationId string, input AIBoardInput) (*models.Job, error) {
+	return c.jobRequest(ctx, request{method: http.MethodPost, path: path("/api/organizations/%s/boards/ai", organizationId), body: input})
+}
+
+// GetBoardGeneration gets a job queued by CreateBoardWithAI. Its progress has the id of the board
+// being generated.
+func (c *Client) GetBoardGeneration(ctx context.Context, organizationId string, jobId string) (*models.Job, error) {
+	return c.jobRequest(ctx, request{method: http.MethodGet, path: path("/api/organizations/%s/boards/ai/%s", organizationId, jobId)})
+}
+
+func (c *Client) GetBoard(ctx context.Context, organizationId string, boardId string) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodGet, path: path(boardPath, organizationId, boardId)})
+}
+
+func (c *Client) GetCompleteBoard(ctx context.Context, organizationId string, boardId string) (*models.CompleteBoard, error) {
+	var board models.CompleteBoard
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(boardPath+"/details", organizationId, boardId)}, &board)
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// UpdateBoard replaces the board's details. version is the version the caller last saw; if the
+// board changed since then the error has the code version_mismatch.
+func (c *Client) UpdateBoard(ctx context.Context, organizationId string, boardId string, version int, input BoardInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(boardPath, organizationId, boardId), body: input, version: version}, nil)
+	return err
+}
+
+func (c *Client) PatchBoard(ctx context.Context, organizationId string, boardId string, version int, patch models.BoardPatch) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodPatch, path: path(boardPath, organizationId, boardId), body: patchBody(patch), version: version})
+}
+
+func (c *Client) DeleteBoard(ctx context.Context, organizationId string, boardId string, version int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(boardPath, organizationId, boardId), version: version}, nil)
+	return err
+}
+
+func (c *Client) RestoreBoard(ctx context.Context, organizationId string, boardId string) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodPost, path: path(boardPath+"/restore", organizationId, boardId)})
+}
+
+func (c *Client) ArchiveBoard(ctx context.Context, organizationId string, boardId string) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodPost, path: path(boardPath+"/archive", organizationId, boardId)})
+}
+
+func (c *Client) UnarchiveBoard(ctx context.Context, organizationId string, boardId string) (*models.Board, error) {
+	return c.boardRequest(ctx, request{method: http.MethodPost, path: path(boardPath+"/unarchive", organizationId, boardId)})
+}
+
+// GetBoardTrash returns the deleted panels, stacks and cards of a board
+func (c *Client) GetBoardTrash(ctx context.Context, organizationId string, boardId string) (*models.BoardTrash, error) {
+	var trash models.BoardTrash
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(boardPath+"/trash", organizationId, boardId)}, &trash)
+	if err != nil {
+		return nil, err
+	}
+	return &trash, nil
+}
+
+func (c *Client) ListBoardMembers(organizationId string, boardId string) *Iterator[models.User] {
+	return newIterator[models.User](c, request{method: http.MethodGet, path: path(boardPath+"/members", organizationId, boardId)})
+}
+
+func (c *Client) AddBoardMember(ctx context.Context, organizationId string, boardId string, userId string) error {
+	body := map[string]string{"user_id": userId}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path(boardPath+"/members", organizationId, boardId), body: body}, nil)
+	return err
+}
+
+// AddBoardGuest adds a user to the board without making them a member of the organization.
+// Remove them with RemoveBoardMember
+func (c *Client) AddBoardGuest(ctx context.Context, organizationId string, boardId string, userId string) error {
+	body := map[string]string{"user_id": userId}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path(boardPath+"/guests", organizationId, boardId), body: body}, nil)
+	return err
+}
+
+func (c *Client) RemoveBoardMember(ctx context.Context, organizationId string, boardId string, memberId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(boardPath+"/members/%s", organizationId, boardId, memberId)}, nil)
+	return err
+}
+
+func (c *Client) boardRequest(ctx context.Context, req request) (*models.Board, error) {
+	var board models.Board
+	_, err := c.do(ctx, req, &board)
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (c *Client) jobRequest(ctx context.Context, req request) (*models.Job, error) {
+	var job models.Job
+	_, err := c.do(ctx, req, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
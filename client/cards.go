@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const cardPath = stackPath + "/cards/%s"
+
+type CardInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Points      string `json:"points"`
+}
+
+type CardUpdate struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Points      string `json:"points,omitempty"`
+	Position    *int   `json:"position,omitempty"`
+	// StackId moves the card to another stack on the same board
+	StackId string `json:"stack_id,omitempty"`
+}
+
+func (c *Client) ListCards(organizationId string, boardId string, panelId string, stackId string) *Iterator[models.Card] {
+	return newIterator[models.Card](c, request{method: http.MethodGet, path: path(stackPath+"/cards", organizationId, boardId, panelId, stackId)})
+}
+
+func (c *Client) CreateCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, input CardInput) (*models.Card, error) {
+	return c.cardRequest(ctx, request{method: http.MethodPost, path: path(stackPath+"/cards", organizationId, boardId, panelId, stackId), body: input})
+}
+
+// CreateCardWithAI has the AI layer write a new card for the stack
+func (c *Client) CreateCardWithAI(ctx context.Context, organizationId string, boardId string, panelId string, stackId string) (*models.Card, error) {
+	return c.cardRequest(ctx, request{method: http.MethodPost, path: path(stackPath+"/cards/ai", organizationId, boardId, panelId, stackId)})
+}
+
+func (c *Client) GetCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string) (*models.Card, error) {
+	return c.cardRequest(ctx, request{method: http.MethodGet, path: path(cardPath, organizationId, boardId, panelId, stackId, cardId)})
+}
+
+func (c *Client) GetCompleteCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string) (*models.CompleteCard, error) {
+	var card models.CompleteCard
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(cardPath+"/details", organizationId, boardId, panelId, stackId, cardId)}, &card)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (c *Client) UpdateCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string, version int, update CardUpdate) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(cardPath, organizationId, boardId, panelId, stackId, cardId), body: update, version: version}, nil)
+	return err
+}
+
+func (c *Client) PatchCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string, version int, patch models.CardPatch) (*models.Card, error) {
+	return c.cardRequest(ctx, request{method: http.MethodPatch, path: path(cardPath, organizationId, boardId, panelId, stackId, cardId), body: patchBody(patch), version: version})
+}
+
+func (c *Client) DeleteCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string, version int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(cardPath, organizationId, boardId, panelId, stackId, cardId), version: version}, nil)
+	return err
+}
+
+func (c *Client) RestoreCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string) (*models.Card, error) {
+	return c.cardRequest(ctx, request{method: http.MethodPost, path: path(cardPath+"/restore", organizationId, boardId, panelId, stackId, cardId)})
+}
+
+// ListCardAssignees lists the ids of the users assigned to a card
+func (c *Client) ListCardAssignees(organizationId string, boardId string, panelId string, stackId string, cardId string) *Iterator[string] {
+	return newIterator[string](c, request{method: http.MethodGet, path: path(cardPath+"/assigned", organizationId, boardId, panelId, stackId, cardId)})
+}
+
+func (c *Client) AssignCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string, userId string) error {
+	body := map[string]string{"user_id": userId}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path(cardPath+"/assigned", organizationId, boardId, panelId, stackId, cardId), body: body}, nil)
+	return err
+}
+
+func (c *Client) UnassignCard(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, cardId string, userId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(cardPath+"/assigned/%s", organizationId, boardId, panelId, stackId, cardId, userId)}, nil)
+	return err
+}
+
+func (c *Client) cardRequest(ctx context.Context, req request) (*models.Card, error) {
+	var card models.Card
+	_, err := c.do(ctx, req, &card)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
@@ -0,0 +1,253 @@
+// Package client is a typed Go client for the SyncSpace API. It sends and returns the same models
+// the server uses, so callers don't have to build requests or decode responses by hand.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	tokenSource TokenSource
+	retryPolicy RetryPolicy
+}
+
+type Option func(*Client)
+
+// RetryPolicy decides how often requests are retried when the server can't be reached or is
+// temporarily unavailable. Only GET, PUT and DELETE are retried since they are safe to repeat.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 200 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = tokenSource
+	}
+}
+
+func WithRetryPolicy(retryPolicy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = retryPolicy
+	}
+}
+
+// New creates a client for the API at baseURL, e.g. https://syncspace.example.com
+func New(baseURL string, options ...Option) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %s: %w", baseURL, err)
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid base url %s: must be absolute", baseURL)
+	}
+	c := &Client{
+		baseURL:     parsedURL,
+		httpClient:  http.DefaultClient,
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	// body is sent as JSON, unless it is a *multipartBody
+	body interface{}
+	// version is sent as If-Match when it isn't 0
+	version int
+}
+
+type multipartBody struct {
+	fields   map[string]string
+	fileName string
+	fileKey  string
+	file     io.Reader
+}
+
+// url joins the base url with path, which has already been escaped by the path function
+func (c *Client) url(path string, query url.Values) string {
+	requestURL := c.baseURL.String() + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	return requestURL
+}
+
+// do sends the request, retrying it if allowed, and decodes the response into out when out isn't
+// nil. Responses with an error status are returned as *Error.
+func (c *Client) do(ctx context.Context, req request, out interface{}) (*http.Response, error) {
+	body, contentType, err := encodeBody(req.body)
+	if err != nil {
+		return nil, err
+	}
+	requestURL := c.url(req.path, req.query)
+	retryable := req.method == http.MethodGet || req.method == http.MethodPut || req.method == http.MethodDelete
+
+	for attempt := 0; ; attempt++ {
+		httpRequest, err := http.NewRequestWithContext(ctx, req.method, requestURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpRequest.Header.Set("Accept", "application/json")
+		if contentType != "" {
+			httpRequest.Header.Set("Content-Type", contentType)
+		}
+		if req.version != 0 {
+			httpRequest.Header.Set("If-Match", fmt.Sprintf(`"%d"`, req.version))
+		}
+		if c.tokenSource != nil {
+			token, err := c.tokenSource.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get token: %w", err)
+			}
+			httpRequest.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		response, err := c.httpClient.Do(httpRequest)
+		if retryable && attempt < c.retryPolicy.MaxRetries && shouldRetry(response, err) {
+			wait := c.retryPolicy.backoff(attempt, response)
+			if response != nil {
+				io.Copy(io.Discard, response.Body)
+				response.Body.Close()
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode >= 400 {
+			return response, decodeError(response)
+		}
+		if out != nil && response.StatusCode != http.StatusNoContent {
+			if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+				return response, fmt.Errorf("failed to decode response from %s %s: %w", req.method, req.path, err)
+			}
+		}
+		return response, nil
+	}
+}
+
+func shouldRetry(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff doubles the wait after each attempt, but waits as long as the server asks if it sent
+// Retry-After
+func (p RetryPolicy) backoff(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	wait := p.MinBackoff << attempt
+	if wait > p.MaxBackoff || wait <= 0 {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+func encodeBody(body interface{}) ([]byte, string, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, "", nil
+	case *multipartBody:
+		var buffer bytes.Buffer
+		writer := multipart.NewWriter(&buffer)
+		for key, value := range b.fields {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+		if b.file != nil {
+			fileWriter, err := writer.CreateFormFile(b.fileKey, b.fileName)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := io.Copy(fileWriter, b.file); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		return buffer.Bytes(), writer.FormDataContentType(), nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return data, "application/json", nil
+}
+
+// patchBody turns a models patch into the fields to send, leaving out the ones that aren't set so
+// the server leaves them alone
+func patchBody(patch interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	patchValue := reflect.ValueOf(patch)
+	for i := 0; i < patchValue.NumField(); i++ {
+		field := patchValue.Field(i)
+		if !field.FieldByName("Set").Bool() {
+			continue
+		}
+		name := patchValue.Type().Field(i).Tag.Get("json")
+		if field.FieldByName("Null").Bool() {
+			fields[name] = nil
+		} else {
+			fields[name] = field.FieldByName("Value").Interface()
+		}
+	}
+	return fields
+}
+
+func path(format string, ids ...string) string {
+	escaped := make([]interface{}, len(ids))
+	for i, id := range ids {
+		escaped[i] = url.PathEscape(id)
+	}
+	return fmt.Sprintf(format, escaped...)
+}
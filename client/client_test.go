@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/routers"
+)
+
+var noBackoff = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// newAPIServer starts the real API without a database, which is enough for everything that fails
+// before a handler reaches the database
+func newAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	server := httptest.NewServer(routers.NewAPI(cfg, nil))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(t *testing.T, url string, options ...Option) *Client {
+	t.Helper()
+	c, err := New(url, options...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return c
+}
+
+func TestInvalidTokenReturnsUnauthorizedError(t *testing.T) {
+	server := newAPIServer(t)
+	c := newTestClient(t, server.URL, WithTokenSource(StaticToken("not-a-jwt")))
+
+	_, err := c.GetOrganization(context.Background(), "00000000-0000-0000-0000-000000000000")
+	var apiError *Error
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if apiError.StatusCode != http.StatusUnauthorized || apiError.Code != controllers.CodeUnauthorized {
+		t.Errorf("expected 401 unauthorized, got %d %s", apiError.StatusCode, apiError.Code)
+	}
+	if apiError.RequestId == "" {
+		t.Error("expected the error to have a request id")
+	}
+	if !errors.Is(err, controllers.NewError(controllers.CodeUnauthorized, "")) {
+		t.Error("expected errors.Is to match the server's unauthorized error")
+	}
+}
+
+func TestUnknownRouteReturnsNotFoundError(t *testing.T) {
+	server := newAPIServer(t)
+	c := newTestClient(t, server.URL+"/nothing-here")
+
+	_, err := c.GetOrganization(context.Background(), "1")
+	if !errors.Is(err, controllers.ErrNotFound) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRetriesUnavailableServer(t *testing.T) {
+	api := newAPIServer(t)
+	var attempts int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		request.URL.Scheme = "http"
+		request.URL.Host = api.Listener.Addr().String()
+		request.RequestURI = ""
+		response, err := http.DefaultTransport.RoundTrip(request)
+		if err != nil {
+			t.Errorf("failed to forward request: %v", err)
+			return
+		}
+		defer response.Body.Close()
+		for key, values := range response.Header {
+			writer.Header()[key] = values
+		}
+		writer.WriteHeader(response.StatusCode)
+		io.Copy(writer, response.Body)
+	}))
+	defer flaky.Close()
+	c := newTestClient(t, flaky.URL, WithRetryPolicy(noBackoff))
+
+	_, err := c.GetOrganization(context.Background(), "1")
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if !errors.Is(err, controllers.NewError(controllers.CodeUnauthorized, "")) {
+		t.Errorf("expected the API's unauthorized error after retrying, got %v", err)
+	}
+}
+
+func TestDoesNotRetryPost(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	c := newTestClient(t, server.URL, WithRetryPolicy(noBackoff))
+
+	_, err := c.CreateOrganization(context.Background(), OrganizationInput{Title: "Org"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestIteratorFollowsNextLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.URL.Query().Get("page") == "" {
+			writer.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, request.URL.Path))
+			json.NewEncoder(writer).Encode([]models.Board{{Title: "one"}, {Title: "two"}})
+			return
+		}
+		json.NewEncoder(writer).Encode([]models.Board{{Title: "three"}})
+	}))
+	defer server.Close()
+	c := newTestClient(t, server.URL)
+
+	boards, err := c.ListBoards("org", nil).All(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list boards: %v", err)
+	}
+	if len(boards) != 3 || boards[2].Title != "three" {
+		t.Errorf("expected boards one, two and three, got %+v", boards)
+	}
+}
+
+func TestPatchSendsVersionAndSetFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected the token to be sent, got %q", request.Header.Get("Authorization"))
+		}
+		if request.Header.Get("If-Match") != `"4"` {
+			t.Errorf("expected If-Match \"4\", got %s", request.Header.Get("If-Match"))
+		}
+		var body map[string]interface{}
+		json.NewDecoder(request.Body).Decode(&body)
+		if len(body) != 2 || body["title"] != "New title" || body["description"] != nil {
+			t.Errorf("expected only title and a null description, got %v", body)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusPreconditionFailed)
+		json.NewEncoder(writer).Encode(map[string]interface{}{
+			"code":       controllers.CodeVersionMismatch,
+			"message":    "version does not match",
+			"details":    models.Board{Title: "Their title", Version: 5},
+			"request_id": "abc",
+		})
+	}))
+	defer server.Close()
+	c := newTestClient(t, server.URL, WithTokenSource(StaticToken("token")))
+
+	patch := models.BoardPatch{
+		Title:       models.NewOptional("New title"),
+		Description: models.Optional[string]{Set: true, Null: true},
+	}
+	_, err := c.PatchBoard(context.Background(), "org", "board", 4, patch)
+	var apiError *Error
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	var current models.Board
+	if err := apiError.Current(&current); err != nil {
+		t.Fatalf("failed to decode current board: %v", err)
+	}
+	if current.Version != 5 || apiError.RequestId != "abc" {
+		t.Errorf("expected version 5 and request abc, got %d and %s", current.Version, apiError.RequestId)
+	}
+}
+
+func TestEscapesIdsInPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.EscapedPath() != "/api/users/auth0%7C123/boards" || request.URL.Query().Get("include_archived") != "true" {
+			t.Errorf("unexpected request url %s", request.URL.String())
+		}
+		json.NewEncoder(writer).Encode([]models.Board{})
+	}))
+	defer server.Close()
+	c := newTestClient(t, server.URL)
+
+	_, err := c.ListUserBoards("auth0|123", &ListBoardsOptions{IncludeArchived: true}).All(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list boards: %v", err)
+	}
+}
+
+func TestClientCredentialsReusesToken(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if request.URL.Path != "/oauth/token" {
+			t.Errorf("expected /oauth/token, got %s", request.URL.Path)
+		}
+		json.NewEncoder(writer).Encode(map[string]interface{}{"access_token": "machine", "expires_in": 3600})
+	}))
+	defer server.Close()
+	tokenSource := &ClientCredentials{Domain: server.URL + "/", ClientId: "id", ClientSecret: "secret", Audience: "api"}
+
+	for i := 0; i < 3; i++ {
+		token, err := tokenSource.Token(context.Background())
+		if err != nil || token != "machine" {
+			t.Fatalf("expected token machine, got %q, %v", token, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 token request, got %d", requests)
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// CredentialInput creates a personal access token or API key. It can only be created while signed
+// in with a JWT and only with permissions you have yourself.
+type CredentialInput struct {
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+func (c *Client) ListPersonalAccessTokens(userId string) *Iterator[models.PersonalAccessToken] {
+	return newIterator[models.PersonalAccessToken](c, request{method: http.MethodGet, path: path("/api/users/%s/tokens", userId)})
+}
+
+// CreatePersonalAccessToken returns the new token, which can't be fetched again later
+func (c *Client) CreatePersonalAccessToken(ctx context.Context, userId string, input CredentialInput) (*models.NewPersonalAccessToken, error) {
+	var token models.NewPersonalAccessToken
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path("/api/users/%s/tokens", userId), body: input}, &token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (c *Client) DeletePersonalAccessToken(ctx context.Context, userId string, tokenId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path("/api/users/%s/tokens/%s", userId, tokenId)}, nil)
+	return err
+}
+
+func (c *Client) ListAPIKeys(organizationId string) *Iterator[models.APIKey] {
+	return newIterator[models.APIKey](c, request{method: http.MethodGet, path: path("/api/organizations/%s/api-keys", organizationId)})
+}
+
+// CreateAPIKey returns the new key, which can't be fetched again later
+func (c *Client) CreateAPIKey(ctx context.Context, organizationId string, input CredentialInput) (*models.NewAPIKey, error) {
+	var key models.NewAPIKey
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path("/api/organizations/%s/api-keys", organizationId), body: input}, &key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (c *Client) DeleteAPIKey(ctx context.Context, organizationId string, keyId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path("/api/organizations/%s/api-keys/%s", organizationId, keyId)}, nil)
+	return err
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// Error is an error response from the API
+type Error struct {
+	StatusCode int
+	Code       controllers.ErrorCode
+	Message    string
+	Details    json.RawMessage
+	RequestId  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("syncspace: %d %s: %s (request %s)", e.StatusCode, e.Code, e.Message, e.RequestId)
+}
+
+// Is lets errors.Is match API errors against the server's errors by code, e.g.
+// errors.Is(err, controllers.ErrNotFound)
+func (e *Error) Is(target error) bool {
+	targetError, ok := target.(*controllers.Error)
+	return ok && targetError.Code == e.Code
+}
+
+// FieldErrors returns the fields that failed validation when Code is validation_failed
+func (e *Error) FieldErrors() models.FieldErrors {
+	if e.Code != controllers.CodeValidationFailed {
+		return nil
+	}
+	var fieldErrors models.FieldErrors
+	json.Unmarshal(e.Details, &fieldErrors)
+	return fieldErrors
+}
+
+// Current decodes the current board, panel, stack or card into v when Code is version_mismatch,
+// so the caller can retry their change against it
+func (e *Error) Current(v interface{}) error {
+	if e.Code != controllers.CodeVersionMismatch {
+		return fmt.Errorf("error has code %s, not %s", e.Code, controllers.CodeVersionMismatch)
+	}
+	return json.Unmarshal(e.Details, v)
+}
+
+func decodeError(response *http.Response) error {
+	apiError := &Error{
+		StatusCode: response.StatusCode,
+		RequestId:  response.Header.Get("X-Request-Id"),
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error response: %w", err)
+	}
+	var envelope struct {
+		Code      controllers.ErrorCode `json:"code"`
+		Message   string                `json:"message"`
+		Details   json.RawMessage       `json:"details"`
+		RequestId string                `json:"request_id"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Code == "" {
+		// not from our error handler, e.g. a proxy in front of the server
+		apiError.Code = controllers.CodeInternal
+		apiError.Message = http.StatusText(response.StatusCode)
+		return apiError
+	}
+	apiError.Code = envelope.Code
+	apiError.Message = envelope.Message
+	apiError.Details = envelope.Details
+	if envelope.RequestId != "" {
+		apiError.RequestId = envelope.RequestId
+	}
+	return apiError
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Iterator goes through the items of a list endpoint, fetching pages as they are needed. If a
+// response has a Link header with rel="next" that page is fetched after the current one runs out;
+// endpoints that return everything at once are a single page.
+//
+//	boards := c.ListBoards(organizationId, nil)
+//	for boards.Next(ctx) {
+//		board := boards.Value()
+//	}
+//	if err := boards.Err(); err != nil {
+type Iterator[T any] struct {
+	client  *Client
+	next    *request
+	page    []T
+	current T
+	err     error
+}
+
+func newIterator[T any](c *Client, req request) *Iterator[T] {
+	return &Iterator[T]{client: c, next: &req}
+}
+
+// Next moves to the next item, returning false when there are no more or a request failed
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.next == nil {
+			return false
+		}
+		var page []T
+		response, err := it.client.do(ctx, *it.next, &page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.next = it.nextPage(response)
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects the remaining items into a slice
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	items := make([]T, 0)
+	for it.Next(ctx) {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+func (it *Iterator[T]) nextPage(response *http.Response) *request {
+	for _, link := range response.Header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+			nextURL, err := response.Request.URL.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				continue
+			}
+			return &request{
+				method: http.MethodGet,
+				path:   strings.TrimPrefix(nextURL.EscapedPath(), it.client.baseURL.EscapedPath()),
+				query:  nextURL.Query(),
+			}
+		}
+	}
+	return nil
+}
+
+func listQuery(includeArchived bool) url.Values {
+	query := url.Values{}
+	if includeArchived {
+		query.Set("include_archived", "true")
+	}
+	return query
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type OrganizationInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	AiEnabled   bool   `json:"ai_enabled"`
+}
+
+// CreateOrganization returns the organization while it is still provisioning. Once it is ready it
+// is listed by ListUserOrganizations, and new tokens carry its permissions.
+func (c *Client) CreateOrganization(ctx context.Context, input OrganizationInput) (*models.Organization, error) {
+	var organization models.Organization
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/api/organizations", body: input}, &organization)
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (c *Client) GetOrganization(ctx context.Context, organizationId string) (*models.Organization, error) {
+	var organization models.Organization
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path("/api/organizations/%s", organizationId)}, &organization)
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (c *Client) UpdateOrganization(ctx context.Context, organizationId string, input OrganizationInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path("/api/organizations/%s", organizationId), body: input}, nil)
+	return err
+}
+
+func (c *Client) PatchOrganization(ctx context.Context, organizationId string, patch models.OrganizationPatch) (*models.Organization, error) {
+	var organization models.Organization
+	_, err := c.do(ctx, request{method: http.MethodPatch, path: path("/api/organizations/%s", organizationId), body: patchBody(patch)}, &organization)
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (c *Client) DeleteOrganization(ctx context.Context, organizationId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path("/api/organizations/%s", organizationId)}, nil)
+	return err
+}
+
+func (c *Client) ListOrganizationMembers(organizationId string) *Iterator[models.User] {
+	return newIterator[models.User](c, request{method: http.MethodGet, path: path("/api/organizations/%s/members", organizationId)})
+}
+
+func (c *Client) AddOrganizationMember(ctx context.Context, organizationId string, userId string) error {
+	body := map[string]string{"user_id": userId}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path("/api/organizations/%s/members", organizationId), body: body}, nil)
+	return err
+}
+
+func (c *Client) RemoveOrganizationMember(ctx context.Context, organizationId string, memberId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path("/api/organizations/%s/members/%s", organizationId, memberId)}, nil)
+	return err
+}
+
+// ListOrganizationGuests lists the users on some of the organization's boards who aren't members
+func (c *Client) ListOrganizationGuests(organizationId string) *Iterator[models.User] {
+	return newIterator[models.User](c, request{method: http.MethodGet, path: path("/api/organizations/%s/guests", organizationId)})
+}
+
+// ListOrganizationTrash lists the deleted boards in an organization that the user can restore
+func (c *Client) ListOrganizationTrash(organizationId string) *Iterator[models.Board] {
+	return newIterator[models.Board](c, request{method: http.MethodGet, path: path("/api/organizations/%s/trash", organizationId)})
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const panelPath = boardPath + "/panels/%s"
+
+type PanelInput struct {
+	Title    string `json:"title"`
+	Position *int   `json:"position,omitempty"`
+}
+
+func (c *Client) ListPanels(organizationId string, boardId string) *Iterator[models.Panel] {
+	return newIterator[models.Panel](c, request{method: http.MethodGet, path: path(boardPath+"/panels", organizationId, boardId)})
+}
+
+func (c *Client) CreatePanel(ctx context.Context, organizationId string, boardId string, title string) (*models.Panel, error) {
+	body := map[string]string{"title": title}
+	return c.panelRequest(ctx, request{method: http.MethodPost, path: path(boardPath+"/panels", organizationId, boardId), body: body})
+}
+
+func (c *Client) GetPanel(ctx context.Context, organizationId string, boardId string, panelId string) (*models.Panel, error) {
+	return c.panelRequest(ctx, request{method: http.MethodGet, path: path(panelPath, organizationId, boardId, panelId)})
+}
+
+func (c *Client) GetCompletePanel(ctx context.Context, organizationId string, boardId string, panelId string) (*models.CompletePanel, error) {
+	var panel models.CompletePanel
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(panelPath+"/details", organizationId, boardId, panelId)}, &panel)
+	if err != nil {
+		return nil, err
+	}
+	return &panel, nil
+}
+
+func (c *Client) UpdatePanel(ctx context.Context, organizationId string, boardId string, panelId string, version int, input PanelInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(panelPath, organizationId, boardId, panelId), body: input, version: version}, nil)
+	return err
+}
+
+func (c *Client) PatchPanel(ctx context.Context, organizationId string, boardId string, panelId string, version int, patch models.PanelPatch) (*models.Panel, error) {
+	return c.panelRequest(ctx, request{method: http.MethodPatch, path: path(panelPath, organizationId, boardId, panelId), body: patchBody(patch), version: version})
+}
+
+func (c *Client) DeletePanel(ctx context.Context, organizationId string, boardId string, panelId string, version int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(panelPath, organizationId, boardId, panelId), version: version}, nil)
+	return err
+}
+
+func (c *Client) RestorePanel(ctx context.Context, organizationId string, boardId string, panelId string) (*models.Panel, error) {
+	return c.panelRequest(ctx, request{method: http.MethodPost, path: path(panelPath+"/restore", organizationId, boardId, panelId)})
+}
+
+func (c *Client) panelRequest(ctx context.Context, req request) (*models.Panel, error) {
+	var panel models.Panel
+	_, err := c.do(ctx, req, &panel)
+	if err != nil {
+		return nil, err
+	}
+	return &panel, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const rolePath = "/api/organizations/%s/roles/%s"
+
+type RoleInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// PermissionNames are all the permissions the role should have. Updating a role removes any
+	// permissions that aren't listed.
+	PermissionNames []string `json:"permission_names"`
+}
+
+func (c *Client) ListRoles(organizationId string) *Iterator[auth.Role] {
+	return newIterator[auth.Role](c, request{method: http.MethodGet, path: path("/api/organizations/%s/roles", organizationId)})
+}
+
+// ListOrganizationPermissions lists the permissions that roles in the organization can have
+func (c *Client) ListOrganizationPermissions(organizationId string) *Iterator[auth.Permission] {
+	return newIterator[auth.Permission](c, request{method: http.MethodGet, path: path("/api/organizations/%s/roles/permissions", organizationId)})
+}
+
+func (c *Client) CreateRole(ctx context.Context, organizationId string, input RoleInput) (*auth.Role, error) {
+	var role auth.Role
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path("/api/organizations/%s/roles", organizationId), body: input}, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (c *Client) GetRole(ctx context.Context, organizationId string, roleId string) (*auth.Role, error) {
+	var role auth.Role
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(rolePath, organizationId, roleId)}, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (c *Client) UpdateRole(ctx context.Context, organizationId string, roleId string, input RoleInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(rolePath, organizationId, roleId), body: input}, nil)
+	return err
+}
+
+func (c *Client) DeleteRole(ctx context.Context, organizationId string, roleId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(rolePath, organizationId, roleId)}, nil)
+	return err
+}
+
+func (c *Client) ListRolePermissions(organizationId string, roleId string) *Iterator[auth.Permission] {
+	return newIterator[auth.Permission](c, request{method: http.MethodGet, path: path(rolePath+"/permissions", organizationId, roleId)})
+}
+
+func (c *Client) ListRoleMembers(organizationId string, roleId string) *Iterator[models.User] {
+	return newIterator[models.User](c, request{method: http.MethodGet, path: path(rolePath+"/members", organizationId, roleId)})
+}
+
+func (c *Client) AddRoleMember(ctx context.Context, organizationId string, roleId string, memberId string) error {
+	body := map[string]string{"member_id": memberId}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path(rolePath+"/members", organizationId, roleId), body: body}, nil)
+	return err
+}
+
+func (c *Client) RemoveRoleMember(ctx context.Context, organizationId string, roleId string, memberId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(rolePath+"/members/%s", organizationId, roleId, memberId)}, nil)
+	return err
+}
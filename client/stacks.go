@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const stackPath = panelPath + "/stacks/%s"
+
+type StackInput struct {
+	Title    string `json:"title"`
+	Position *int   `json:"position,omitempty"`
+}
+
+func (c *Client) ListStacks(organizationId string, boardId string, panelId string) *Iterator[models.Stack] {
+	return newIterator[models.Stack](c, request{method: http.MethodGet, path: path(panelPath+"/stacks", organizationId, boardId, panelId)})
+}
+
+func (c *Client) CreateStack(ctx context.Context, organizationId string, boardId string, panelId string, title string) (*models.Stack, error) {
+	body := map[string]string{"title": title}
+	return c.stackRequest(ctx, request{method: http.MethodPost, path: path(panelPath+"/stacks", organizationId, boardId, panelId), body: body})
+}
+
+func (c *Client) GetStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string) (*models.Stack, error) {
+	return c.stackRequest(ctx, request{method: http.MethodGet, path: path(stackPath, organizationId, boardId, panelId, stackId)})
+}
+
+func (c *Client) GetCompleteStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string) (*models.CompleteStack, error) {
+	var stack models.CompleteStack
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path(stackPath+"/details", organizationId, boardId, panelId, stackId)}, &stack)
+	if err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
+
+func (c *Client) UpdateStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, version int, input StackInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(stackPath, organizationId, boardId, panelId, stackId), body: input, version: version}, nil)
+	return err
+}
+
+func (c *Client) PatchStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, version int, patch models.StackPatch) (*models.Stack, error) {
+	return c.stackRequest(ctx, request{method: http.MethodPatch, path: path(stackPath, organizationId, boardId, panelId, stackId), body: patchBody(patch), version: version})
+}
+
+func (c *Client) DeleteStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string, version int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(stackPath, organizationId, boardId, panelId, stackId), version: version}, nil)
+	return err
+}
+
+func (c *Client) RestoreStack(ctx context.Context, organizationId string, boardId string, panelId string, stackId string) (*models.Stack, error) {
+	return c.stackRequest(ctx, request{method: http.MethodPost, path: path(stackPath+"/restore", organizationId, boardId, panelId, stackId)})
+}
+
+func (c *Client) stackRequest(ctx context.Context, req request) (*models.Stack, error) {
+	var stack models.Stack
+	_, err := c.do(ctx, req, &stack)
+	if err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
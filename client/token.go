@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource gives the access token to send with each request
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken always sends the same token, e.g. one copied from a signed in session
+func StaticToken(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// ClientCredentials gets tokens from Auth0 with the client credentials grant, for services like the
+// AI layer that call the API as themselves. Tokens are reused until shortly before they expire.
+type ClientCredentials struct {
+	Domain       string
+	ClientId     string
+	ClientSecret string
+	Audience     string
+	HTTPClient   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"client_id":     c.ClientId,
+		"client_secret": c.ClientSecret,
+		"audience":      c.Audience,
+		"grant_type":    "client_credentials",
+	})
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/oauth/token", strings.TrimSuffix(c.Domain, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get token: %s", res.Status)
+	}
+
+	var tokenResponse struct {
+		Token     string `json:"access_token"`
+		ExpiresIn int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+	c.token = tokenResponse.Token
+	// refresh a minute early so a token doesn't expire while a request is in flight
+	c.expiresAt = time.Now().Add(time.Duration(tokenResponse.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const userPath = "/api/users/%s"
+
+type UserUpdate struct {
+	Email    string
+	Username string
+	Password string
+	// ProfilePicture is a png or jpeg, cropped to a square by the server
+	ProfilePicture io.Reader
+}
+
+func (c *Client) ListUsers() *Iterator[models.User] {
+	return newIterator[models.User](c, request{method: http.MethodGet, path: "/api/users"})
+}
+
+// UpdateUser changes the signed in user's details. Empty fields are left as they are.
+func (c *Client) UpdateUser(ctx context.Context, userId string, update UserUpdate) error {
+	fields := make(map[string]string)
+	for key, value := range map[string]string{"email": update.Email, "username": update.Username, "password": update.Password} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	body := &multipartBody{fields: fields, fileKey: "profile_picture", fileName: "profile_picture", file: update.ProfilePicture}
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path(userPath, userId), body: body}, nil)
+	return err
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(userPath, userId)}, nil)
+	return err
+}
+
+func (c *Client) ListUserOrganizations(userId string) *Iterator[models.Organization] {
+	return newIterator[models.Organization](c, request{method: http.MethodGet, path: path(userPath+"/organizations", userId)})
+}
+
+func (c *Client) ListUserOwnedOrganizations(userId string) *Iterator[models.Organization] {
+	return newIterator[models.Organization](c, request{method: http.MethodGet, path: path(userPath+"/organizations/owned", userId)})
+}
+
+func (c *Client) ListUserBoards(userId string, options *ListBoardsOptions) *Iterator[models.Board] {
+	if options == nil {
+		options = &ListBoardsOptions{}
+	}
+	return newIterator[models.Board](c, request{method: http.MethodGet, path: path(userPath+"/boards", userId), query: listQuery(options.IncludeArchived)})
+}
+
+func (c *Client) ListUserOwnedBoards(userId string, options *ListBoardsOptions) *Iterator[models.Board] {
+	if options == nil {
+		options = &ListBoardsOptions{}
+	}
+	return newIterator[models.Board](c, request{method: http.MethodGet, path: path(userPath+"/boards/owned", userId), query: listQuery(options.IncludeArchived)})
+}
+
+func (c *Client) ListUserAssignedCards(userId string) *Iterator[models.DetailedAssignedCard] {
+	return newIterator[models.DetailedAssignedCard](c, request{method: http.MethodGet, path: path(userPath+"/assigned", userId)})
+}
+
+func (c *Client) ListFavouriteBoards(userId string) *Iterator[models.Board] {
+	return newIterator[models.Board](c, request{method: http.MethodGet, path: path(userPath+"/boards/favourite", userId)})
+}
+
+func (c *Client) AddFavouriteBoard(ctx context.Context, userId string, boardId string) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path(userPath+"/boards/favourite/%s", userId, boardId)}, nil)
+	return err
+}
+
+func (c *Client) RemoveFavouriteBoard(ctx context.Context, userId string, boardId string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path(userPath+"/boards/favourite/%s", userId, boardId)}, nil)
+	return err
+}
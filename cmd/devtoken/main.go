@@ -0,0 +1,58 @@
+// Command devtoken prints a token signed with JWT_SECRET for any user and permissions, for running
+// the API with AUTH_MODE=local. It reads the same .env as the server.
+//
+//	go run ./cmd/devtoken --sub user1 --permission org<id>:read --permission org<id>:update
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "devtoken: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("devtoken", flag.ContinueOnError)
+	subject := flags.String("sub", "", "user id the token is for")
+	expiresIn := flags.Duration("expires-in", 24*time.Hour, "how long the token is valid for")
+	var permissions []string
+	flags.Func("permission", "permission to give the token, can be repeated or comma separated", func(value string) error {
+		for _, permission := range strings.Split(value, ",") {
+			if permission = strings.TrimSpace(permission); permission != "" {
+				permissions = append(permissions, permission)
+			}
+		}
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *subject == "" {
+		return fmt.Errorf("--sub is required")
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	if cfg.AuthMode != config.AUTH_MODE_LOCAL {
+		fmt.Fprintf(os.Stderr, "devtoken: warning, AUTH_MODE is %s so the server won't accept this token\n", cfg.AuthMode)
+	}
+	token, err := auth.NewLocalToken(cfg, *subject, permissions, *expiresIn)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
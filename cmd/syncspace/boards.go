@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func runBoards(ctx context.Context, app *app, args []string) error {
+	var organizationId, title, description string
+	var includeArchived bool
+	name, flags, err := subcommand("boards", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&organizationId, "org", "", "organization id")
+		flags.StringVar(&title, "title", "", "title of the board")
+		flags.StringVar(&description, "description", "", "description of the board")
+		flags.BoolVar(&includeArchived, "archived", false, "also list archived boards")
+	})
+	if err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": organizationId}); err != nil {
+		return err
+	}
+
+	if name == "list" {
+		boards, err := app.client.ListBoards(organizationId, &client.ListBoardsOptions{IncludeArchived: includeArchived}).All(ctx)
+		if err != nil {
+			return err
+		}
+		return printBoards(app, boards)
+	}
+	if name == "create" {
+		if err := required(map[string]string{"title": title}); err != nil {
+			return err
+		}
+		board, err := app.client.CreateBoard(ctx, organizationId, client.BoardInput{Title: title, Description: description})
+		if err != nil {
+			return err
+		}
+		return printBoard(app, board)
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("boards %s needs a board id", name)
+	}
+	boardId := flags.Arg(0)
+	var board *models.Board
+	switch name {
+	case "get":
+		board, err = app.client.GetBoard(ctx, organizationId, boardId)
+	case "archive":
+		board, err = app.client.ArchiveBoard(ctx, organizationId, boardId)
+	case "unarchive":
+		board, err = app.client.UnarchiveBoard(ctx, organizationId, boardId)
+	case "delete":
+		board, err = app.client.GetBoard(ctx, organizationId, boardId)
+		if err == nil {
+			err = app.client.DeleteBoard(ctx, organizationId, boardId, board.Version)
+		}
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "Moved board %s to the trash\n", boardId)
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown subcommand boards %s", name)
+	}
+	if err != nil {
+		return err
+	}
+	return printBoard(app, board)
+}
+
+func boardRow(board models.Board) []string {
+	return []string{board.Id.String(), board.Title, yesNo(board.IsPrivate), yesNo(board.ArchivedAt != nil), board.ModifiedAt}
+}
+
+var boardHeaders = []string{"ID", "TITLE", "PRIVATE", "ARCHIVED", "MODIFIED"}
+
+func printBoards(app *app, boards []models.Board) error {
+	rows := make([][]string, len(boards))
+	for i, board := range boards {
+		rows[i] = boardRow(board)
+	}
+	return app.printer.table(boards, boardHeaders, rows)
+}
+
+func printBoard(app *app, board *models.Board) error {
+	return app.printer.table(board, boardHeaders, [][]string{boardRow(*board)})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func runCards(ctx context.Context, app *app, args []string) error {
+	var organizationId, boardId, panelId, stackId, toStackId string
+	var input client.CardInput
+	name, flags, err := subcommand("cards", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&organizationId, "org", "", "organization id")
+		flags.StringVar(&boardId, "board", "", "board id")
+		flags.StringVar(&panelId, "panel", "", "panel id")
+		flags.StringVar(&stackId, "stack", "", "stack id")
+		flags.StringVar(&toStackId, "to-stack", "", "stack to move the cards to")
+		flags.StringVar(&input.Title, "title", "", "title of the card")
+		flags.StringVar(&input.Description, "description", "", "description of the card")
+		flags.StringVar(&input.Points, "points", "", "story points of the card")
+	})
+	if err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": organizationId, "board": boardId}); err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		board, err := app.client.GetCompleteBoard(ctx, organizationId, boardId)
+		if err != nil {
+			return err
+		}
+		return printCompleteBoard(app, board)
+	case "create":
+		if err := required(map[string]string{"panel": panelId, "stack": stackId, "title": input.Title}); err != nil {
+			return err
+		}
+		card, err := app.client.CreateCard(ctx, organizationId, boardId, panelId, stackId, input)
+		if err != nil {
+			return err
+		}
+		return printCards(app, card, []models.Card{*card})
+	case "move":
+		if err := required(map[string]string{"panel": panelId, "stack": stackId, "to-stack": toStackId}); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			return fmt.Errorf("cards move needs at least one card id")
+		}
+		moved := make([]models.Card, 0, flags.NArg())
+		for _, cardId := range flags.Args() {
+			card, err := app.client.GetCard(ctx, organizationId, boardId, panelId, stackId, cardId)
+			if err != nil {
+				return fmt.Errorf("failed to get card %s: %w", cardId, err)
+			}
+			patch := models.CardPatch{StackId: models.NewOptional(toStackId)}
+			card, err = app.client.PatchCard(ctx, organizationId, boardId, panelId, stackId, cardId, card.Version, patch)
+			if err != nil {
+				return fmt.Errorf("failed to move card %s: %w", cardId, err)
+			}
+			moved = append(moved, *card)
+		}
+		return printCards(app, moved, moved)
+	}
+	return fmt.Errorf("unknown subcommand cards %s", name)
+}
+
+func printCards(app *app, value interface{}, cards []models.Card) error {
+	rows := make([][]string, len(cards))
+	for i, card := range cards {
+		rows[i] = []string{card.Id.String(), card.Title, card.Points, card.StackId.String(), strconv.Itoa(card.Position)}
+	}
+	return app.printer.table(value, []string{"ID", "TITLE", "POINTS", "STACK", "POSITION"}, rows)
+}
+
+func printCompleteBoard(app *app, board *models.CompleteBoard) error {
+	rows := make([][]string, 0)
+	for _, panel := range board.Panels {
+		for _, stack := range panel.Stacks {
+			for _, card := range stack.Cards {
+				rows = append(rows, []string{panel.Title, stack.Title, card.Id.String(), card.Title, card.Points, strconv.Itoa(len(card.Assignments))})
+			}
+		}
+	}
+	return app.printer.table(board, []string{"PANEL", "STACK", "ID", "TITLE", "POINTS", "ASSIGNEES"}, rows)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cliConfig is saved between runs so that users only have to log in once. CI can skip the file
+// and set SYNCSPACE_SERVER and SYNCSPACE_TOKEN instead.
+type cliConfig struct {
+	Server       string    `json:"server"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	UserId       string    `json:"user_id,omitempty"`
+	Auth0        struct {
+		Domain   string `json:"domain,omitempty"`
+		ClientId string `json:"client_id,omitempty"`
+		Audience string `json:"audience,omitempty"`
+	} `json:"auth0"`
+
+	path string
+}
+
+const defaultServer = "https://syncspace-server-i6acbs4ioa-ue.a.run.app"
+
+func defaultConfigPath() (string, error) {
+	if path := os.Getenv("SYNCSPACE_CONFIG"); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(configDir, "syncspace", "config.json"), nil
+}
+
+func loadConfig(path string) (*cliConfig, error) {
+	cfg := &cliConfig{Server: defaultServer, path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if server := os.Getenv("SYNCSPACE_SERVER"); server != "" {
+		cfg.Server = server
+	}
+	if token := os.Getenv("SYNCSPACE_TOKEN"); token != "" {
+		cfg.Token = token
+		cfg.RefreshToken = ""
+		cfg.ExpiresAt = time.Time{}
+	}
+	return cfg, nil
+}
+
+// save writes the config readable only by the user, since it holds their token
+func (cfg *cliConfig) save() error {
+	if err := os.MkdirAll(filepath.Dir(cfg.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cfg.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", cfg.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// runExport writes boards with all their panels, stacks and cards as JSON, for backups or for
+// loading into other tools. Without --board every board in the organization is exported.
+func runExport(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	organizationId := flags.String("org", "", "organization id")
+	boardId := flags.String("board", "", "board id, to export only one board")
+	file := flags.String("file", "", "file to write to instead of stdout")
+	includeArchived := flags.Bool("archived", true, "also export archived boards")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": *organizationId}); err != nil {
+		return err
+	}
+
+	var export interface{}
+	if *boardId != "" {
+		board, err := app.client.GetCompleteBoard(ctx, *organizationId, *boardId)
+		if err != nil {
+			return err
+		}
+		export = board
+	} else {
+		boards := app.client.ListBoards(*organizationId, &client.ListBoardsOptions{IncludeArchived: *includeArchived})
+		completeBoards := make([]models.CompleteBoard, 0)
+		for boards.Next(ctx) {
+			board, err := app.client.GetCompleteBoard(ctx, *organizationId, boards.Value().Id.String())
+			if err != nil {
+				return fmt.Errorf("failed to export board %s: %w", boards.Value().Id, err)
+			}
+			completeBoards = append(completeBoards, *board)
+		}
+		if err := boards.Err(); err != nil {
+			return err
+		}
+		export = completeBoards
+	}
+
+	out := app.printer.out
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return (&printer{out: out, format: "json"}).json(export)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func runLogin(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	server := flags.String("server", app.config.Server, "URL of the SyncSpace API")
+	token := flags.String("token", "", "personal access token to use instead of logging in through the browser")
+	userId := flags.String("user", "", "your user id, if it can't be read from the token")
+	domain := flags.String("domain", app.config.Auth0.Domain, "Auth0 domain to log in with, e.g. https://syncspace.auth0.com/")
+	clientId := flags.String("client-id", app.config.Auth0.ClientId, "Auth0 client id of the CLI application")
+	audience := flags.String("audience", app.config.Auth0.Audience, "audience of the SyncSpace API in Auth0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := app.config
+	cfg.Server = *server
+	cfg.RefreshToken = ""
+	cfg.ExpiresAt = time.Time{}
+	if *token != "" {
+		cfg.Token = *token
+	} else {
+		if *domain == "" || *clientId == "" || *audience == "" {
+			return errors.New("logging in through the browser needs --domain, --client-id and --audience, or use --token")
+		}
+		cfg.Auth0.Domain = *domain
+		cfg.Auth0.ClientId = *clientId
+		cfg.Auth0.Audience = *audience
+		tokens, err := deviceLogin(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		cfg.setTokens(tokens)
+	}
+
+	cfg.UserId = *userId
+	if cfg.UserId == "" {
+		cfg.UserId = tokenSubject(cfg.Token)
+	}
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s\n", cfg.Server)
+	return nil
+}
+
+func runLogout(ctx context.Context, app *app, args []string) error {
+	cfg := app.config
+	cfg.Token = ""
+	cfg.RefreshToken = ""
+	cfg.ExpiresAt = time.Time{}
+	cfg.UserId = ""
+	return cfg.save()
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+func (cfg *cliConfig) setTokens(tokens *tokenResponse) {
+	cfg.Token = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		cfg.RefreshToken = tokens.RefreshToken
+	}
+	cfg.ExpiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+}
+
+// deviceLogin logs in with the OAuth device code flow: the user opens a link in their browser and
+// the CLI polls Auth0 until they have approved it
+func deviceLogin(ctx context.Context, cfg *cliConfig) (*tokenResponse, error) {
+	var deviceCode struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	err := postAuth0(ctx, cfg, "oauth/device/code", url.Values{
+		"client_id": {cfg.Auth0.ClientId},
+		"audience":  {cfg.Auth0.Audience},
+		"scope":     {"openid offline_access"},
+	}, &deviceCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start login: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Open %s in your browser and check that the code is %s\n", deviceCode.VerificationURIComplete, deviceCode.UserCode)
+
+	interval := time.Duration(deviceCode.Interval) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(deviceCode.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		var tokens tokenResponse
+		err := postAuth0(ctx, cfg, "oauth/token", url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {deviceCode.DeviceCode},
+			"client_id":   {cfg.Auth0.ClientId},
+		}, &tokens)
+		if err != nil {
+			return nil, err
+		}
+		switch tokens.Error {
+		case "":
+			return &tokens, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, fmt.Errorf("login failed: %s", tokens.Description)
+		}
+	}
+	return nil, errors.New("login expired before it was approved")
+}
+
+// accessToken refreshes the access token from a device login when it is about to expire. Tokens that
+// were given with --token are used as they are.
+func (cfg *cliConfig) accessToken(ctx context.Context) (string, error) {
+	if cfg.Token == "" {
+		return "", errors.New("not logged in, run syncspace login")
+	}
+	if cfg.RefreshToken == "" || cfg.ExpiresAt.IsZero() || time.Now().Add(time.Minute).Before(cfg.ExpiresAt) {
+		return cfg.Token, nil
+	}
+	var tokens tokenResponse
+	err := postAuth0(ctx, cfg, "oauth/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {cfg.RefreshToken},
+		"client_id":     {cfg.Auth0.ClientId},
+	}, &tokens)
+	if err != nil {
+		return "", err
+	}
+	if tokens.Error != "" {
+		return "", fmt.Errorf("failed to refresh login, run syncspace login again: %s", tokens.Description)
+	}
+	cfg.setTokens(&tokens)
+	return cfg.Token, cfg.save()
+}
+
+func postAuth0(ctx context.Context, cfg *cliConfig, path string, form url.Values, out interface{}) error {
+	requestURL := strings.TrimSuffix(cfg.Auth0.Domain, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// Auth0 reports pending device logins as errors in the body, which the caller handles
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("auth0 returned %s", res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// tokenSubject reads the user id out of a JWT without verifying it; the server does that. Tokens
+// that aren't JWTs give an empty id.
+func tokenSubject(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return ""
+	}
+	return claims.Subject
+}
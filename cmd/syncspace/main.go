@@ -0,0 +1,128 @@
+// Command syncspace scripts SyncSpace from a terminal or CI job. Run syncspace help for the list of
+// commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+)
+
+type app struct {
+	config  *cliConfig
+	client  *client.Client
+	printer *printer
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = map[string]command{
+	"login":   {"login [--token TOKEN] [--server URL] [--domain D --client-id ID --audience A]", runLogin},
+	"logout":  {"logout", runLogout},
+	"orgs":    {"orgs list [--owned] | get ID | create --title T [--description D] [--ai]", runOrgs},
+	"boards":  {"boards list --org ID [--archived] | get --org ID BOARD | create --org ID --title T | archive|unarchive|delete --org ID BOARD", runBoards},
+	"panels":  {"panels list --org ID --board ID | create --org ID --board ID TITLE...", runPanels},
+	"cards":   {"cards list --org ID --board ID | create --org ID --board ID --panel ID --stack ID --title T | move --org ID --board ID --panel ID --stack ID --to-stack ID CARD...", runCards},
+	"members": {"members list|add|remove --org ID [--board ID] [USER...]", runMembers},
+	"export":  {"export --org ID [--board ID] [--file PATH]", runExport},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "syncspace: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("syncspace", flag.ContinueOnError)
+	flags.Usage = usage
+	output := flags.String("output", "table", "output format, table or json")
+	configPath := flags.String("config", "", "path to the config file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %s", *output)
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		usage()
+		return nil
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown command %s", flags.Arg(0))
+	}
+
+	if *configPath == "" {
+		path, err := defaultConfigPath()
+		if err != nil {
+			return err
+		}
+		*configPath = path
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	apiClient, err := client.New(cfg.Server, client.WithTokenSource(client.TokenSourceFunc(cfg.accessToken)))
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, &app{
+		config:  cfg,
+		client:  apiClient,
+		printer: &printer{out: os.Stdout, format: *output},
+	}, flags.Args()[1:])
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: syncspace [--output table|json] [--config PATH] COMMAND")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+// subcommand splits off the subcommand and parses its flags
+func subcommand(name string, args []string, setup func(flags *flag.FlagSet)) (string, *flag.FlagSet, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s needs a subcommand, see syncspace help", name)
+	}
+	flags := flag.NewFlagSet(name+" "+args[0], flag.ContinueOnError)
+	setup(flags)
+	return args[0], flags, flags.Parse(args[1:])
+}
+
+func required(values map[string]string) error {
+	for name, value := range values {
+		if value == "" {
+			return fmt.Errorf("--%s is required", name)
+		}
+	}
+	return nil
+}
+
+func (app *app) userId() (string, error) {
+	if app.config.UserId == "" {
+		return "", errors.New("your user id is unknown, run syncspace login with --user")
+	}
+	return app.config.UserId, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func runMembers(ctx context.Context, app *app, args []string) error {
+	var organizationId, boardId string
+	name, flags, err := subcommand("members", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&organizationId, "org", "", "organization id")
+		flags.StringVar(&boardId, "board", "", "board id, to manage a board's members instead of the organization's")
+	})
+	if err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": organizationId}); err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		members := app.client.ListOrganizationMembers(organizationId)
+		if boardId != "" {
+			members = app.client.ListBoardMembers(organizationId, boardId)
+		}
+		users, err := members.All(ctx)
+		if err != nil {
+			return err
+		}
+		return printUsers(app, users)
+	case "add", "remove":
+		if flags.NArg() == 0 {
+			return fmt.Errorf("members %s needs at least one user id", name)
+		}
+		for _, userId := range flags.Args() {
+			var err error
+			switch {
+			case name == "add" && boardId != "":
+				err = app.client.AddBoardMember(ctx, organizationId, boardId, userId)
+			case name == "add":
+				err = app.client.AddOrganizationMember(ctx, organizationId, userId)
+			case boardId != "":
+				err = app.client.RemoveBoardMember(ctx, organizationId, boardId, userId)
+			default:
+				err = app.client.RemoveOrganizationMember(ctx, organizationId, userId)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s member %s: %w", name, userId, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown subcommand members %s", name)
+}
+
+func printUsers(app *app, users []models.User) error {
+	rows := make([][]string, len(users))
+	for i, user := range users {
+		rows[i] = []string{user.UserID, user.Username, user.Email, user.Name}
+	}
+	return app.printer.table(users, []string{"ID", "USERNAME", "EMAIL", "NAME"}, rows)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func runOrgs(ctx context.Context, app *app, args []string) error {
+	var owned, aiEnabled bool
+	var title, description string
+	name, flags, err := subcommand("orgs", args, func(flags *flag.FlagSet) {
+		flags.BoolVar(&owned, "owned", false, "only list organizations you own")
+		flags.StringVar(&title, "title", "", "title of the organization")
+		flags.StringVar(&description, "description", "", "description of the organization")
+		flags.BoolVar(&aiEnabled, "ai", false, "let the organization use AI features")
+	})
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		userId, err := app.userId()
+		if err != nil {
+			return err
+		}
+		organizations := app.client.ListUserOrganizations(userId)
+		if owned {
+			organizations = app.client.ListUserOwnedOrganizations(userId)
+		}
+		all, err := organizations.All(ctx)
+		if err != nil {
+			return err
+		}
+		return printOrganizations(app, all...)
+	case "get":
+		if flags.NArg() != 1 {
+			return fmt.Errorf("orgs get needs an organization id")
+		}
+		organization, err := app.client.GetOrganization(ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return printOrganizations(app, *organization)
+	case "create":
+		if err := required(map[string]string{"title": title}); err != nil {
+			return err
+		}
+		organization, err := app.client.CreateOrganization(ctx, client.OrganizationInput{Title: title, Description: description, AiEnabled: aiEnabled})
+		if err != nil {
+			return err
+		}
+		return printOrganizations(app, *organization)
+	}
+	return fmt.Errorf("unknown subcommand orgs %s", name)
+}
+
+func printOrganizations(app *app, organizations ...models.Organization) error {
+	rows := make([][]string, len(organizations))
+	for i, organization := range organizations {
+		rows[i] = []string{organization.Id.String(), organization.Name, organization.OwnerId, yesNo(organization.AiEnabled)}
+	}
+	if len(organizations) == 1 {
+		return app.printer.table(organizations[0], []string{"ID", "NAME", "OWNER", "AI"}, rows)
+	}
+	return app.printer.table(organizations, []string{"ID", "NAME", "OWNER", "AI"}, rows)
+}
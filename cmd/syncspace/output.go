@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+// table prints rows under headers, or value as JSON when --output json was given. value is what
+// the API returned so JSON output can be piped into other tools unchanged.
+func (p *printer) table(value interface{}, headers []string, rows [][]string) error {
+	if p.format == "json" {
+		return p.json(value)
+	}
+	writer := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+func (p *printer) json(value interface{}) error {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func runPanels(ctx context.Context, app *app, args []string) error {
+	var organizationId, boardId string
+	name, flags, err := subcommand("panels", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&organizationId, "org", "", "organization id")
+		flags.StringVar(&boardId, "board", "", "board id")
+	})
+	if err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": organizationId, "board": boardId}); err != nil {
+		return err
+	}
+
+	var panels []models.Panel
+	switch name {
+	case "list":
+		panels, err = app.client.ListPanels(organizationId, boardId).All(ctx)
+		if err != nil {
+			return err
+		}
+	case "create":
+		// each title is a new panel, e.g. syncspace panels create --org O --board B "Sprint 1" "Sprint 2"
+		if flags.NArg() == 0 {
+			return fmt.Errorf("panels create needs at least one title")
+		}
+		for _, title := range flags.Args() {
+			panel, err := app.client.CreatePanel(ctx, organizationId, boardId, title)
+			if err != nil {
+				return fmt.Errorf("failed to create panel %s: %w", title, err)
+			}
+			panels = append(panels, *panel)
+		}
+	default:
+		return fmt.Errorf("unknown subcommand panels %s", name)
+	}
+
+	rows := make([][]string, len(panels))
+	for i, panel := range panels {
+		rows[i] = []string{panel.Id.String(), panel.Title, strconv.Itoa(panel.Position)}
+	}
+	return app.printer.table(panels, []string{"ID", "TITLE", "POSITION"}, rows)
+}
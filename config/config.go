@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -14,10 +15,20 @@ const (
 	ENV_PROD  ENV = "production"
 )
 
+// AuthMode decides who signs the JWTs the API accepts. Local mode checks tokens signed with
+// JWT_SECRET so the API can be used without Auth0; it is refused in production.
+type AuthMode string
+
+const (
+	AUTH_MODE_AUTH0 AuthMode = "auth0"
+	AUTH_MODE_LOCAL AuthMode = "local"
+)
+
 type Config struct {
-	Environment ENV    `default:"local" envconfig:"APP_ENV"`
-	APIHost     string `default:"http://127.0.0.1:8080" envconfig:"API_HOST"`
-	JWTSecret   string `default:"bruh" envconfig:"JWT_SECRET"`
+	Environment ENV      `default:"local" envconfig:"APP_ENV"`
+	APIHost     string   `default:"http://127.0.0.1:8080" envconfig:"API_HOST"`
+	JWTSecret   string   `default:"" envconfig:"JWT_SECRET"`
+	AuthMode    AuthMode `default:"auth0" envconfig:"AUTH_MODE"`
 	DB          struct {
 		DBUser string `default:"postgres" envconfig:"DB_USER"`
 		DBPass string `default:"postgres" envconfig:"DB_PASS"`
@@ -39,6 +50,13 @@ type Config struct {
 		Management struct {
 			Audience string `default:"syncspace.auth0.com/v2/api" envconfig:"AUTH0_MANAGEMENT_AUDIENCE"`
 		}
+		// ReconcileInterval is how often roles and permissions of organizations and boards that
+		// aren't in the database are deleted. Set it to 0 when the tenant is shared with another
+		// database, since their organizations would look deleted from here.
+		ReconcileInterval time.Duration `default:"24h" envconfig:"AUTH0_RECONCILE_INTERVAL"`
+		// ProvisioningResumeInterval is how often organizations and boards whose roles were never
+		// finished being set up, like when the server restarted part way through, are picked up
+		ProvisioningResumeInterval time.Duration `default:"5m" envconfig:"AUTH0_PROVISIONING_RESUME_INTERVAL"`
 	}
 	Wasabi struct {
 		AccessKey string `default:"" envconfig:"WASABI_ACCESS_KEY"`
@@ -52,6 +70,42 @@ type Config struct {
 	AI struct {
 		APIHost string `default:"http://127.0.0.1:3999" envconfig:"AI_API_HOST"`
 	}
+	Trash struct {
+		RetentionPeriod time.Duration `default:"720h" envconfig:"TRASH_RETENTION_PERIOD"`
+		PurgeInterval   time.Duration `default:"1h" envconfig:"TRASH_PURGE_INTERVAL"`
+	}
+	// Jobs are run by Concurrency workers in the server, which look for due jobs every PollInterval
+	// when they have nothing to do. A job is stopped after Timeout, and picked up again if it has
+	// been running for longer than that.
+	Jobs struct {
+		Concurrency  int           `default:"4" envconfig:"JOBS_CONCURRENCY"`
+		PollInterval time.Duration `default:"1s" envconfig:"JOBS_POLL_INTERVAL"`
+		Timeout      time.Duration `default:"10m" envconfig:"JOBS_TIMEOUT"`
+	}
+	// Mail picks how outgoing email is sent. "log" writes it to File, or the server log if File is
+	// empty, so invitations can be tried locally without an SMTP server.
+	Mail struct {
+		Sender string `default:"log" envconfig:"MAIL_SENDER"`
+		From   string `default:"SyncSpace <noreply@syncspace.app>" envconfig:"MAIL_FROM"`
+		File   string `default:"" envconfig:"MAIL_FILE"`
+		SMTP   struct {
+			Host     string `default:"" envconfig:"SMTP_HOST"`
+			Port     int    `default:"587" envconfig:"SMTP_PORT"`
+			Username string `default:"" envconfig:"SMTP_USERNAME"`
+			Password string `default:"" envconfig:"SMTP_PASSWORD"`
+		}
+	}
+	Shares struct {
+		// URL is the frontend page that shows a shared board, with the token appended as ?token=
+		URL string `default:"http://127.0.0.1:3000/shared" envconfig:"BOARD_SHARE_URL"`
+	}
+	Invitations struct {
+		TTL time.Duration `default:"168h" envconfig:"INVITATION_TTL"`
+		// AcceptURL is the frontend page invitees are sent to, with the token appended as ?token=
+		AcceptURL string `default:"http://127.0.0.1:3000/invitations/accept" envconfig:"INVITATION_ACCEPT_URL"`
+		// JoinURL is the frontend page invite links open, with the token appended as ?token=
+		JoinURL string `default:"http://127.0.0.1:3000/join" envconfig:"INVITATION_JOIN_URL"`
+	}
 }
 
 var (
@@ -0,0 +1,41 @@
+package board
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// ErrBoardArchived is returned when trying to change anything on a board that has been archived
+var ErrBoardArchived = controllers.NewError(controllers.CodeBoardArchived, "board is archived and can't be modified")
+
+func (c *Controller) ArchiveBoardById(ctx context.Context, boardId string) (*models.Board, error) {
+	err := c.store.Boards.Archive(ctx, boardId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBoardById(ctx, boardId)
+}
+
+func (c *Controller) UnarchiveBoardById(ctx context.Context, boardId string) (*models.Board, error) {
+	err := c.store.Boards.Unarchive(ctx, boardId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBoardById(ctx, boardId)
+}
+
+// ensureBoardNotArchived returns ErrBoardArchived if the board is read-only. Every
+// panel, stack and card mutation goes through here before touching the database.
+func (c *Controller) ensureBoardNotArchived(ctx context.Context, boardId string) error {
+	isArchived, err := c.store.Boards.IsArchived(ctx, boardId)
+	if err != nil {
+		return controllers.NotFound(err, "board", boardId)
+	}
+	if isArchived {
+		return ErrBoardArchived
+	}
+	return nil
+}
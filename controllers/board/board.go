@@ -2,27 +2,25 @@ package board
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
 	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/controllers/provisioning"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
 	"github.com/Sync-Space-49/syncspace-server/models"
 
 	"github.com/google/uuid"
 )
 
-func (c *Controller) GetViewableBoardsInOrg(ctx context.Context, tokenCustomClaims *auth.CustomClaims, orgId string, userId string) (*[]models.Board, error) {
-	orgBoards := make([]models.Board, 0)
-	err := c.db.DB.SelectContext(ctx, &orgBoards, `
-		SELECT * FROM Boards WHERE organization_id=$1;
-	`, orgId)
+func (c *Controller) GetViewableBoardsInOrg(ctx context.Context, tokenCustomClaims *auth.CustomClaims, orgId string, userId string, includeArchived bool) (*[]models.Board, error) {
+	orgBoards, err := c.store.Boards.ListByOrganization(ctx, orgId, includeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -32,8 +30,7 @@ func (c *Controller) GetViewableBoardsInOrg(ctx context.Context, tokenCustomClai
 	for _, board := range orgBoards {
 		if board.IsPrivate {
 			readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, board.Id)
-			boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-			canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+			canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 			if !canReadBoard {
 				continue
 			}
@@ -44,17 +41,28 @@ func (c *Controller) GetViewableBoardsInOrg(ctx context.Context, tokenCustomClai
 }
 
 func (c *Controller) GetBoardById(ctx context.Context, boardId string) (*models.Board, error) {
-	var board models.Board
-	err := c.db.DB.GetContext(ctx, &board, `
-		SELECT * FROM Boards WHERE id=$1;
-	`, boardId)
+	board, err := c.store.Boards.Get(ctx, boardId)
 	if err != nil {
-		return nil, err
+		return nil, controllers.NotFound(err, "board", boardId)
+	}
+	return board, nil
+}
+
+func (c *Controller) GetDeletedBoardById(ctx context.Context, orgId string, boardId string) (*models.Board, error) {
+	board, err := c.store.Boards.GetDeleted(ctx, orgId, boardId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "board", boardId)
 	}
-	return &board, nil
+	return board, nil
 }
 
 func (c *Controller) GetCompleteBoardById(ctx context.Context, boardId string) (*models.CompleteBoard, error) {
+	return c.getCompleteBoard(ctx, boardId, true)
+}
+
+// getCompleteBoard only looks up who is assigned to each card if includeAssignees is true, since
+// that takes a call to Auth0 per assignment
+func (c *Controller) getCompleteBoard(ctx context.Context, boardId string, includeAssignees bool) (*models.CompleteBoard, error) {
 	board, err := c.GetBoardById(ctx, boardId)
 	if err != nil {
 		return nil, err
@@ -84,6 +92,10 @@ func (c *Controller) GetCompleteBoardById(ctx context.Context, boardId string) (
 						for _, card := range *cards {
 							completeCard := models.CopyToCompleteCard(card)
 							completeCard.Assignments = make([]models.User, 0)
+							if !includeAssignees {
+								completeStack.Cards = append(completeStack.Cards, completeCard)
+								continue
+							}
 							assignments, err := c.GetAssignedUsersByCardId(ctx, card.Id.String())
 							if err != nil {
 								return nil, err
@@ -118,131 +130,121 @@ func (c *Controller) GetCompleteBoardById(ctx context.Context, boardId string) (
 	return &completeBoard, nil
 }
 
+// CreateBoard inserts the board as provisioning and queues a ProvisionBoard job to set up its roles
 func (c *Controller) CreateBoard(ctx context.Context, userId string, name string, description string, isPrivate bool, orgId string) (*models.Board, error) {
-	query := `INSERT INTO Boards (id, title, description, is_private, organization_id, owner_id) VALUES ($1, $2, $3, $4, $5, $6);`
-	boardId := uuid.New().String()
-	_, err := c.db.DB.ExecContext(ctx, query, boardId, name, description, isPrivate, orgId, userId)
+	board, err := c.createBoard(ctx, uuid.New(), userId, name, description, isPrivate, orgId)
 	if err != nil {
 		return nil, err
 	}
-	org, err := c.GetBoardById(ctx, boardId)
+	payload := ProvisionBoardPayload{BoardId: board.Id.String(), OwnerId: userId, OrganizationId: orgId}
+	_, err = ProvisionBoard.Enqueue(ctx, jobs.NewQueue(c.store.Jobs), payload)
 	if err != nil {
-		return nil, err
+		// ResumeProvisioning still picks the board up later
+		log.Printf("Failed to queue provisioning board %s: %v", board.Id, err)
 	}
-	return org, nil
+	return board, nil
 }
 
-func (c *Controller) InitializeBoard(ownerId string, boardId string, orgId string) error {
-	ownerRoleName := fmt.Sprintf("org%s:board%s:owner", orgId, boardId)
-	ownerRoleDescription := fmt.Sprintf("Owner of board with the id: %s", boardId)
-	ownerRole, err := auth.CreateRole(ownerRoleName, ownerRoleDescription)
+func (c *Controller) createBoard(ctx context.Context, boardId uuid.UUID, userId string, name string, description string, isPrivate bool, orgId string) (*models.Board, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, controllers.NotFound(sql.ErrNoRows, "organization", orgId)
+	}
+	err = c.store.Boards.Create(ctx, models.Board{
+		Id:             boardId,
+		Title:          name,
+		Description:    description,
+		IsPrivate:      isPrivate,
+		OrganizationId: orgUUID,
+		OwnerId:        userId,
+
+		ProvisioningStatus: models.ProvisioningInProgress,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	memberRoleName := fmt.Sprintf("org%s:board%s:member", orgId, boardId)
-	memberRoleDescription := fmt.Sprintf("Member of board with the id: %s", boardId)
-	memberRole, err := auth.CreateRole(memberRoleName, memberRoleDescription)
+	org, err := c.GetBoardById(ctx, boardId.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return org, nil
+}
 
-	readPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:read", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to read the contents of the board with id %s", boardId),
-	}
-	deletePerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:delete", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to delete the board with id %s", boardId),
-	}
-	updatePerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:update", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to update info about the board with id %s", boardId),
-	}
-	createPanelPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:create_panel", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to create a panel on the board with id %s", boardId),
-	}
-	deletePanelPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:delete_panel", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to delete a panel on the board with id %s", boardId),
-	}
-	updatePanelPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:update_panel", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to update a panel on the board with id %s", boardId),
-	}
-	createStackPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:create_stack", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to create a stack on the board with id %s", boardId),
-	}
-	updateStackPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:update_stack", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to update a stack on the board with id %s", boardId),
-	}
-	deleteStackPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:delete_stack", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to delete a stack on the board with id %s", boardId),
-	}
-	createCardPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:create_card", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to create a card on the board with id %s", boardId),
-	}
-	updateCardPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:update_card", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to update a card on the board with id %s", boardId),
-	}
-	deleteCardPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:delete_card", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to delete a card on the board with id %s", boardId),
-	}
-	addMembersPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:add_members", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to add members to the board with id %s", boardId),
-	}
-	removeMembersPerm := auth.Permission{
-		Name:        fmt.Sprintf("org%s:board%s:remove_members", orgId, boardId),
-		Description: fmt.Sprintf("Allows you to remove members from the board with id %s", boardId),
-	}
+// boardActionOrder lists the actions of boardActions in the order they are shown in
+var boardActionOrder = []string{
+	"read", "delete", "update",
+	"create_panel", "delete_panel", "update_panel",
+	"create_stack", "update_stack", "delete_stack",
+	"create_card", "update_card", "delete_card",
+	"add_members", "remove_members",
+}
 
-	boardMemberPermissions := []auth.Permission{
-		readPerm, createCardPerm, updateCardPerm, deleteCardPerm,
-	}
-	boardOwnerPermissions := append(boardMemberPermissions, deletePerm, updatePerm, createPanelPerm, deletePanelPerm, updatePanelPerm, createStackPerm, updateStackPerm, deleteStackPerm, addMembersPerm, removeMembersPerm)
+// boardActions are what every board has a permission for, named org<id>:board<id>:<action>, along
+// with a description of what they allow that the board id is formatted into
+var boardActions = map[string]string{
+	"read":           "Allows you to read the contents of the board with id %s",
+	"delete":         "Allows you to delete the board with id %s",
+	"update":         "Allows you to update info about the board with id %s",
+	"create_panel":   "Allows you to create a panel on the board with id %s",
+	"delete_panel":   "Allows you to delete a panel on the board with id %s",
+	"update_panel":   "Allows you to update a panel on the board with id %s",
+	"create_stack":   "Allows you to create a stack on the board with id %s",
+	"update_stack":   "Allows you to update a stack on the board with id %s",
+	"delete_stack":   "Allows you to delete a stack on the board with id %s",
+	"create_card":    "Allows you to create a card on the board with id %s",
+	"update_card":    "Allows you to update a card on the board with id %s",
+	"delete_card":    "Allows you to delete a card on the board with id %s",
+	"add_members":    "Allows you to add members to the board with id %s",
+	"remove_members": "Allows you to remove members from the board with id %s",
+}
 
-	// Because the owner role has all permissions, we only need to call CreatePermissions once
-	err = auth.CreatePermissions(boardOwnerPermissions)
-	if err != nil {
-		return fmt.Errorf("failed to create permissions for board: %w", err)
-	}
+// boardMemberActions are what the member role every board starts with can do. The owner role can
+// do everything.
+var boardMemberActions = []string{"read", "create_card", "update_card", "delete_card"}
 
-	boardMemberPermissionNames := make([]string, len(boardMemberPermissions))
-	for i, permission := range boardMemberPermissions {
-		boardMemberPermissionNames[i] = permission.Name
-	}
-	err = auth.AddPermissionsToRole(memberRole.Id, boardMemberPermissionNames)
-	if err != nil {
-		return fmt.Errorf("failed to add permissions to member role: %w", err)
-	}
-	boardOwnerPermissionNames := make([]string, len(boardOwnerPermissions))
-	for i, permission := range boardOwnerPermissions {
-		boardOwnerPermissionNames[i] = permission.Name
-	}
-	err = auth.AddPermissionsToRole(ownerRole.Id, boardOwnerPermissionNames)
-	if err != nil {
-		return fmt.Errorf("failed to add permissions to owner role: %w", err)
-	}
+// BoardPermissions returns every permission the board has, which is what its roles are made from
+func BoardPermissions(orgId string, boardId string) []auth.Permission {
+	permissions := make([]auth.Permission, 0, len(boardActionOrder))
+	for _, action := range boardActionOrder {
+		permissions = append(permissions, auth.Permission{
+			Name:        fmt.Sprintf("org%s:board%s:%s", orgId, boardId, action),
+			Description: fmt.Sprintf(boardActions[action], boardId),
+		})
+	}
+	return permissions
+}
 
-	err = auth.AddUserToRole(ownerId, ownerRole.Id)
-	if err != nil {
-		return fmt.Errorf("failed to add owner role to user: %w", err)
-	}
-	err = auth.AddUserToRole(ownerId, memberRole.Id)
-	if err != nil {
-		return fmt.Errorf("failed to add member role to user: %w", err)
-	}
-	return nil
+// ProvisionBoard sets up the roles and permissions of a board made by CreateBoard and marks it
+// ready. If that fails, whatever was set up is undone and the board is deleted.
+func (c *Controller) ProvisionBoard(ctx context.Context, ownerId string, boardId string, orgId string) error {
+	return c.boardProvisioning(ownerId, boardId, orgId).Run(ctx)
+}
+
+func (c *Controller) boardProvisioning(ownerId string, boardId string, orgId string) *provisioning.Saga {
+	memberPermissionNames := make([]string, len(boardMemberActions))
+	for i, action := range boardMemberActions {
+		memberPermissionNames[i] = fmt.Sprintf("org%s:board%s:%s", orgId, boardId, action)
+	}
+	access := provisioning.Access{
+		OwnerId: ownerId,
+		OwnerRole: auth.Role{
+			Name:        fmt.Sprintf("org%s:board%s:owner", orgId, boardId),
+			Description: fmt.Sprintf("Owner of board with the id: %s", boardId),
+		},
+		MemberRole: auth.Role{
+			Name:        fmt.Sprintf("org%s:board%s:member", orgId, boardId),
+			Description: fmt.Sprintf("Member of board with the id: %s", boardId),
+		},
+		Permissions:           BoardPermissions(orgId, boardId),
+		MemberPermissionNames: memberPermissionNames,
+	}
+	return provisioning.New(c.store.Provisioning, c.store.Boards, boardId, access.Steps())
 }
 
-func (c *Controller) UpdateBoardById(ctx context.Context, orgId string, boardId string, title string, description string, isPrivate bool, ownerId string, previousOwnerId string) error {
+func (c *Controller) UpdateBoardById(ctx context.Context, orgId string, boardId string, version int, title string, description string, isPrivate bool, ownerId string, previousOwnerId string) error {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
 	board, err := c.GetBoardById(ctx, boardId)
 	if err != nil {
 		return err
@@ -257,10 +259,15 @@ func (c *Controller) UpdateBoardById(ctx context.Context, orgId string, boardId
 		ownerId = board.OwnerId
 		// fmt.Printf("2 ownerId: %s", ownerId)
 	}
-	modified_at := time.Now().UTC()
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Boards SET title=$1, description=$2, is_private=$3, owner_id=$4, modified_at=$5 WHERE id=$6;
-	`, title, description, isPrivate, ownerId, modified_at, boardId)
+	err = claimVersion(ctx, c.store.Boards, boardId, version)
+	if err != nil {
+		return err
+	}
+	board.Title = title
+	board.Description = description
+	board.IsPrivate = isPrivate
+	board.OwnerId = ownerId
+	err = c.store.Boards.Update(ctx, *board, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -292,14 +299,82 @@ func (c *Controller) UpdateBoardById(ctx context.Context, orgId string, boardId
 	return nil
 }
 
-func (c *Controller) DeleteBoardById(ctx context.Context, boardId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		DELETE FROM Boards WHERE id=$1;
-	`, boardId)
+// PatchBoardById only changes the fields that are set on the patch. When the owner changes, the
+// board's owner role moves from the old owner to the new one.
+func (c *Controller) PatchBoardById(ctx context.Context, orgId string, boardId string, version int, patch models.BoardPatch) (*models.Board, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	board, err := c.GetBoardById(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	title := patch.Title.Or(board.Title)
+	description := patch.Description.Or(board.Description)
+	isPrivate := patch.IsPrivate.Or(board.IsPrivate)
+	ownerId := patch.OwnerId.Or(board.OwnerId)
+
+	err = claimVersion(ctx, c.store.Boards, boardId, version)
+	if err != nil {
+		return nil, err
+	}
+	previousOwnerId := board.OwnerId
+	board.Title = title
+	board.Description = description
+	board.IsPrivate = isPrivate
+	board.OwnerId = ownerId
+	err = c.store.Boards.Update(ctx, *board, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerId != previousOwnerId {
+		boardOwnerRoleName := fmt.Sprintf("org%s:board%s:owner", orgId, boardId)
+		boardOwnerRoles, err := auth.GetRoles(&boardOwnerRoleName)
+		if err != nil {
+			return nil, err
+		}
+		if len(*boardOwnerRoles) == 0 {
+			return nil, fmt.Errorf("no roles found for board %s", boardId)
+		}
+		err = auth.RemoveUserFromRole(previousOwnerId, (*boardOwnerRoles)[0].Id)
+		if err != nil {
+			return nil, err
+		}
+		err = auth.AddUserToRole(ownerId, (*boardOwnerRoles)[0].Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.GetBoardById(ctx, boardId)
+}
+
+// DeleteBoardById moves the board to the trash. Its panels, stacks and cards are left untouched
+// so that they come back with it if the board is restored before it is purged, and so are its roles
+// and permissions until PurgeTrash deletes it for good.
+func (c *Controller) DeleteBoardById(ctx context.Context, boardId string, version int) error {
+	_, err := c.GetBoardById(ctx, boardId)
 	if err != nil {
 		return err
 	}
-	return nil
+	err = claimVersion(ctx, c.store.Boards, boardId, version)
+	if err != nil {
+		return err
+	}
+	return c.store.Boards.SoftDelete(ctx, boardId, time.Now().UTC())
+}
+
+func (c *Controller) RestoreBoardById(ctx context.Context, orgId string, boardId string) (*models.Board, error) {
+	_, err := c.GetDeletedBoardById(ctx, orgId, boardId)
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Boards.Restore(ctx, boardId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBoardById(ctx, boardId)
 }
 
 func (c *Controller) GetMembersByBoardId(boardId string) (*[]models.User, error) {
@@ -352,6 +427,10 @@ func (c *Controller) RemoveMemberFromBoard(ctx context.Context, userId string, o
 	if err != nil {
 		return err
 	}
+	err = leaveIfLastGuestBoard(userId, orgId)
+	if err != nil {
+		return err
+	}
 	err = c.UpdateBoardModifiedAt(ctx, boardId)
 	if err != nil {
 		return err
@@ -359,99 +438,58 @@ func (c *Controller) RemoveMemberFromBoard(ctx context.Context, userId string, o
 	return nil
 }
 
-func (c *Controller) CreateBoardWithAI(ctx context.Context, userId string, name string, description string, isPrivate bool, orgId string, detailLevel string, storyPointType string, storyPointExamples string) (*models.Board, error) {
-	requestUrl := fmt.Sprintf("%s/api/generate/board", c.cfg.AI.APIHost)
-	formData := url.Values{}
-	formData.Add("title", name)
-	formData.Add("description", description)
-
-	if detailLevel == "" {
-		formData.Add("detail_level", "very detailed")
-	} else {
-		formData.Add("detail_level", detailLevel)
-	}
-	if storyPointType == "" {
-		formData.Add("story_point_type", "T-Shirt Sizes")
-	} else {
-		formData.Add("story_point_type", storyPointType)
-	}
-	if storyPointExamples == "" {
-		formData.Add("story_points", "S, M, L, XL")
-	} else {
-		formData.Add("story_points", storyPointExamples)
-	}
-
-	req, err := http.NewRequest("POST", requestUrl, strings.NewReader(formData.Encode()))
+// AddGuestToBoard gives the user the board's member role without making them a member of the
+// organization, so they can work on this board and nothing else in it
+func (c *Controller) AddGuestToBoard(ctx context.Context, userId string, orgId string, boardId string) error {
+	isMember, err := organization.IsMember(userId, orgId)
 	if err != nil {
-		fmt.Printf("error making http request: %s\n", err)
-		return nil, err
+		return err
 	}
-	req.Header.Add("Content-Type", "application/form-data")
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		log.Fatalf("Error occurred during making request. %v", err)
-		return nil, err
+	if isMember {
+		return controllers.NewError(controllers.CodeConflict, fmt.Sprintf("user %s is already a member of organization %s, add them as a board member instead", userId, orgId))
 	}
-	data, err := io.ReadAll(res.Body)
+	guestRole, err := organization.GuestRole(orgId)
 	if err != nil {
-		log.Fatalf("Error occurred during conversion of HTTP resonse body into bytes. %v", err)
-		return nil, err
+		return err
 	}
-
-	var sprints models.AIGeneratedSprint
-	err = json.Unmarshal(data, &sprints)
+	err = auth.AddUserToRole(userId, guestRole.Id)
 	if err != nil {
-		log.Fatalf("Error occurred during unmarshalling. %v", err)
-		return nil, err
+		return err
 	}
-	newBoard, err := c.CreateBoard(ctx, userId, name, description, isPrivate, orgId)
+	return c.AddMemberToBoard(ctx, userId, orgId, boardId)
+}
+
+// leaveIfLastGuestBoard drops the organization's guest role once a guest is on none of its boards
+func leaveIfLastGuestBoard(userId string, orgId string) error {
+	roles, err := auth.GetUserRoles(userId)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	boardId := newBoard.Id.String()
-
-	for sprint, tasks := range sprints {
-		newPanel, err := c.CreatePanel(ctx, sprint, boardId)
-		if err != nil {
-			return nil, err
-		}
-		panelId := newPanel.Id.String()
-		newStack, err := c.CreateStack(ctx, "To-Do", boardId, panelId)
-		if err != nil {
-			return nil, err
+	guestRoleName := fmt.Sprintf("org%s:guest", orgId)
+	boardRolePrefix := fmt.Sprintf("org%s:board", orgId)
+	guestRoleId := ""
+	for _, role := range *roles {
+		if strings.HasPrefix(role.Name, boardRolePrefix) {
+			return nil
 		}
-		for _, task := range tasks {
-			CardStoryPointsString := fmt.Sprintf("%v", task.CardStoryPoints)
-			_, err := c.CreateCard(ctx, task.CardTitle, task.CardDesc, CardStoryPointsString, boardId, newStack.Id.String())
-			if err != nil {
-				return nil, err
-			}
+		if role.Name == guestRoleName {
+			guestRoleId = role.Id
 		}
 	}
-
-	return newBoard, nil
+	if guestRoleId == "" {
+		return nil
+	}
+	return auth.RemoveUserFromRole(userId, guestRoleId)
 }
 
 func (c *Controller) CanUseAI(ctx context.Context, orgId string) (bool, error) {
-	var ai_enabled bool
-
-	err := c.db.DB.GetContext(ctx, &ai_enabled, `
-		SELECT ai_enabled FROM Organizations WHERE id=$1;
-	`, orgId)
+	organization, err := c.store.Organizations.Get(ctx, orgId)
 	if err != nil {
-		return false, err
+		return false, controllers.NotFound(err, "organization", orgId)
 	}
-	return ai_enabled, nil
+	return organization.AiEnabled, nil
 }
 
 func (c *Controller) UpdateBoardModifiedAt(ctx context.Context, boardId string) error {
-	modified_at := time.Now().UTC()
-	_, err := c.db.DB.ExecContext(ctx, `
-		UPDATE Boards SET modified_at=$1 WHERE id=$2;
-	`, modified_at, boardId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.store.Boards.Touch(ctx, boardId, time.Now().UTC())
 }
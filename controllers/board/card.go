@@ -3,23 +3,24 @@ package board
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
 	"github.com/google/uuid"
 )
 
 func (c *Controller) GetCardsByStackId(ctx context.Context, stackId string) (*[]models.Card, error) {
-	cards := make([]models.Card, 0)
-	err := c.db.DB.SelectContext(ctx, &cards, `
-		SELECT * FROM Cards WHERE stack_id=$1 ORDER BY position ASC;
-	`, stackId)
+	cards, err := c.store.Cards.ListByStack(ctx, stackId)
 	if err != nil {
 		return nil, err
 	}
@@ -27,21 +28,23 @@ func (c *Controller) GetCardsByStackId(ctx context.Context, stackId string) (*[]
 }
 
 func (c *Controller) CreateCard(ctx context.Context, title string, description string, points string, boardId string, stackId string) (*models.Card, error) {
-	var nextPosition int
-	err := c.db.DB.GetContext(ctx, &nextPosition, `
-		SELECT COALESCE(MAX(position)+1, 0) AS next_position FROM Cards where stack_id=$1;
-	`, stackId)
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	nextPosition, err := c.store.Cards.NextPosition(ctx, stackId)
 	if err != nil {
 		return nil, err
 	}
-	cardId := uuid.New().String()
-	_, err = c.db.DB.ExecContext(ctx, `
-		INSERT INTO Cards (id, title, description, points, position, stack_id) VALUES ($1, $2, $3, $4, $5, $6);
-	`, cardId, title, description, points, nextPosition, stackId)
+	stackUUID, err := uuid.Parse(stackId)
+	if err != nil {
+		return nil, controllers.NotFound(sql.ErrNoRows, "stack", stackId)
+	}
+	cardId := uuid.New()
+	err = c.store.Cards.Create(ctx, models.Card{Id: cardId, Title: title, Description: description, Points: points, Position: nextPosition, StackId: stackUUID})
 	if err != nil {
 		return nil, err
 	}
-	card, err := c.GetCardById(ctx, cardId)
+	card, err := c.GetCardById(ctx, cardId.String())
 	if err != nil {
 		return nil, err
 	}
@@ -55,122 +58,145 @@ func (c *Controller) CreateCard(ctx context.Context, title string, description s
 }
 
 func (c *Controller) GetCardById(ctx context.Context, cardId string) (*models.Card, error) {
-	card := models.Card{}
-	err := c.db.DB.GetContext(ctx, &card, `
-		SELECT * FROM Cards WHERE id=$1;
-	`, cardId)
+	card, err := c.store.Cards.Get(ctx, cardId)
 	if err != nil {
-		return nil, err
+		return nil, controllers.NotFound(err, "card", cardId)
 	}
-	return &card, nil
+	return card, nil
 }
 
-func (c *Controller) UpdateCardById(ctx context.Context, boardId string, stackId string, cardId string, newStackId string, title string, description string, points string, position *int) error {
-	card, err := c.GetCardById(ctx, cardId)
+func (c *Controller) GetDeletedCardById(ctx context.Context, boardId string, cardId string) (*models.Card, error) {
+	card, err := c.store.Cards.GetDeleted(ctx, boardId, cardId)
 	if err != nil {
-		return err
+		return nil, controllers.NotFound(err, "card", cardId)
+	}
+	return card, nil
+}
+
+func (c *Controller) UpdateCardById(ctx context.Context, boardId string, stackId string, cardId string, version int, newStackId string, title string, description string, points string, position *int) error {
+	patch := models.CardPatch{Points: models.NewOptional(points)}
+	if title != "" {
+		patch.Title = models.NewOptional(title)
 	}
-	if title == "" {
-		title = card.Title
+	if description != "" {
+		patch.Description = models.NewOptional(description)
 	}
-	if description == "" {
-		description = card.Description
+	if newStackId != "" {
+		patch.StackId = models.NewOptional(newStackId)
 	}
-	if position == nil && newStackId != "" {
-		var nextPosition int
-		err := c.db.DB.GetContext(ctx, &nextPosition, `
-			SELECT COALESCE(MAX(position)+1, 0) AS next_position FROM Cards where stack_id=$1;
-		`, newStackId)
-		if err != nil {
-			return err
-		}
-		position = &nextPosition
-	} else {
-		if position == nil {
-			position = &card.Position
-		}
+	if position != nil {
+		patch.Position = models.NewOptional(*position)
 	}
-	if newStackId == "" {
-		newStackId = stackId
-	} else {
-		var isStackInBoard bool
-		err := c.db.DB.GetContext(ctx, &isStackInBoard, `
-			SELECT EXISTS(
-				SELECT 1
-					FROM Stacks s
-						JOIN panels p on s.panel_id = p.id
-						JOIN boards b on p.board_id = b.id
-					WHERE s.id = $1 AND b.id = $2
-			);
-		`, newStackId, boardId)
+	_, err := c.PatchCardById(ctx, boardId, stackId, cardId, version, patch)
+	return err
+}
+
+// PatchCardById only changes the fields that are set on the patch. Description and points are
+// cleared when they are set to null or an empty string.
+func (c *Controller) PatchCardById(ctx context.Context, boardId string, stackId string, cardId string, version int, patch models.CardPatch) (*models.Card, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	card, err := c.GetCardById(ctx, cardId)
+	if err != nil {
+		return nil, err
+	}
+	title := patch.Title.Or(card.Title)
+	description := patch.Description.Or(card.Description)
+	points := patch.Points.Or(card.Points)
+	oldStackId := card.StackId.String()
+	newStackId := oldStackId
+	if patch.StackId.Set {
+		newStackId = patch.StackId.Value
+		isStackInBoard, err := c.store.Stacks.IsInBoard(ctx, newStackId, boardId)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !isStackInBoard {
-			return errors.New("stack is not in the same board")
+			return nil, controllers.NewError(controllers.CodeValidationFailed, "stack is not in the same board")
 		}
 	}
+	changesStack := newStackId != oldStackId
 
-	if *position != card.Position {
-		var maxPosition int
-		err := c.db.DB.GetContext(ctx, &maxPosition, `
-			SELECT COALESCE(MAX(position), 0) AS max_position FROM Cards where stack_id=$1;
-		`, newStackId)
+	position := card.Position
+	if changesStack {
+		// A card moved to another stack goes after its last card unless it is given a position
+		nextPosition, err := c.store.Cards.NextPosition(ctx, newStackId)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		position = patch.Position.Or(nextPosition)
+		if err := checkInsertPosition(position, nextPosition); err != nil {
+			return nil, err
 		}
-		if *position > maxPosition || *position < 0 {
-			return errors.New("position is out of range")
+	} else if patch.Position.Set && patch.Position.Value != card.Position {
+		position = patch.Position.Value
+		nextPosition, err := c.store.Cards.NextPosition(ctx, newStackId)
+		if err != nil {
+			return nil, err
 		}
+		if err := checkPosition(position, nextPosition); err != nil {
+			return nil, err
+		}
+	}
 
-		if *position > card.Position {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Cards SET position=position-1 WHERE stack_id=$1 AND position>$2 AND position<=$3;
-			`, newStackId, card.Position, *position)
-		} else {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Cards SET position=position+1 WHERE stack_id=$1 AND position<$2 AND position>=$3;
-			`, newStackId, card.Position, *position)
+	err = claimVersion(ctx, c.store.Cards, cardId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if changesStack {
+		err = moveBetweenParents(ctx, c.store.Cards, oldStackId, card.Position, newStackId, position)
+		if err != nil {
+			return nil, err
 		}
+	} else if position != card.Position {
+		err = moveSiblings(ctx, c.store.Cards, newStackId, card.Position, position)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Cards SET title=$1, description=$2, points=$3, position=$4, stack_id=$5 WHERE id=$6;
-	`, title, description, points, *position, newStackId, cardId)
+	newStackUUID, err := uuid.Parse(newStackId)
 	if err != nil {
-		return err
+		return nil, controllers.NotFound(sql.ErrNoRows, "stack", newStackId)
+	}
+	card.Title = title
+	card.Description = description
+	card.Points = points
+	card.Position = position
+	card.StackId = newStackUUID
+	err = c.store.Cards.Update(ctx, *card)
+	if err != nil {
+		return nil, err
 	}
 
 	err = c.UpdateBoardModifiedAt(ctx, boardId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return c.GetCardById(ctx, cardId)
 }
 
-func (c *Controller) DeleteCardById(ctx context.Context, boardId string, stackId string, cardId string) error {
+func (c *Controller) DeleteCardById(ctx context.Context, boardId string, stackId string, cardId string, version int) error {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
 	card, err := c.GetCardById(ctx, cardId)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		DELETE FROM assigned_cards WHERE card_id=$1;
-	`, cardId)
+	err = claimVersion(ctx, c.store.Cards, cardId, version)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		DELETE FROM Cards WHERE id=$1;
-	`, cardId)
+	// Assignments and the card's position are kept while it is in the trash so RestoreCardById
+	// can put it back exactly where it was; they are only removed when the trash is purged
+	err = c.store.Cards.SoftDelete(ctx, cardId, time.Now().UTC())
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Cards SET position=position-1 WHERE stack_id=$1 AND position>$2;
-	`, stackId, card.Position)
+	err = c.store.Cards.ShiftPositions(ctx, stackId, card.Position+1, store.End, -1)
 	if err != nil {
 		return err
 	}
@@ -183,7 +209,52 @@ func (c *Controller) DeleteCardById(ctx context.Context, boardId string, stackId
 	return nil
 }
 
+func (c *Controller) RestoreCardById(ctx context.Context, boardId string, cardId string) (*models.Card, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	card, err := c.GetDeletedCardById(ctx, boardId, cardId)
+	if err != nil {
+		return nil, err
+	}
+	stackId := card.StackId.String()
+	_, err = c.GetStackById(ctx, stackId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrParentInTrash
+		}
+		return nil, err
+	}
+
+	nextPosition, err := c.store.Cards.NextPosition(ctx, stackId)
+	if err != nil {
+		return nil, err
+	}
+	position := card.Position
+	if position > nextPosition {
+		position = nextPosition
+	}
+	err = c.store.Cards.ShiftPositions(ctx, stackId, position, store.End, 1)
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Cards.Restore(ctx, cardId, position)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.UpdateBoardModifiedAt(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCardById(ctx, cardId)
+}
+
 func (c *Controller) CreateCardWithAI(ctx context.Context, boardId string, cardStackId string) (*models.Card, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
 	requestUrl := fmt.Sprintf("%s/api/generate/card", c.cfg.AI.APIHost)
 
 	detailedBoard, err := c.GetCompleteBoardById(ctx, boardId)
@@ -239,9 +310,10 @@ func (c *Controller) CreateCardWithAI(ctx context.Context, boardId string, cardS
 }
 
 func (c *Controller) AssignCardToUser(ctx context.Context, boardId string, cardId string, userId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		INSERT INTO assigned_cards (user_id, card_id) VALUES ($1, $2);
-	`, userId, cardId)
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
+	err := c.store.Assignments.Assign(ctx, cardId, userId)
 	if err != nil {
 		return err
 	}
@@ -255,9 +327,10 @@ func (c *Controller) AssignCardToUser(ctx context.Context, boardId string, cardI
 }
 
 func (c *Controller) UnassignCardFromUser(ctx context.Context, boardId string, cardId string, userId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		DELETE FROM assigned_cards WHERE user_id=$1 AND card_id=$2;
-	`, userId, cardId)
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
+	err := c.store.Assignments.Unassign(ctx, cardId, userId)
 	if err != nil {
 		return err
 	}
@@ -271,10 +344,7 @@ func (c *Controller) UnassignCardFromUser(ctx context.Context, boardId string, c
 }
 
 func (c *Controller) GetAssignedUsersByCardId(ctx context.Context, cardId string) (*[]string, error) {
-	var userIds []string
-	err := c.db.DB.SelectContext(ctx, &userIds, `
-		SELECT user_id FROM assigned_cards WHERE card_id=$1;
-	`, cardId)
+	userIds, err := c.store.Assignments.ListUserIds(ctx, cardId)
 	if err != nil {
 		return nil, err
 	}
@@ -283,10 +353,7 @@ func (c *Controller) GetAssignedUsersByCardId(ctx context.Context, cardId string
 }
 
 func (c *Controller) GetAssignedCardsByUserId(ctx context.Context, boardId string, userId string) (*[]string, error) {
-	var cardIds []string
-	err := c.db.DB.SelectContext(ctx, &cardIds, `
-		SELECT card_id FROM assigned_cards WHERE user_id=$1;
-	`, userId)
+	cardIds, err := c.store.Assignments.ListCardIds(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,231 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+// GenerateBoard has the AI service write a board, then creates it with its panels and cards. The
+// board's id is picked when the job is queued, so an attempt that stopped partway can find and
+// undo what the one before it created.
+var GenerateBoard = jobs.Type[GenerateBoardPayload]{Name: "generate_board"}
+
+// Generating a board is slow and every attempt asks the AI service again, so it isn't tried as
+// often as other jobs
+const generateBoardAttempts = 3
+
+type GenerateBoardPayload struct {
+	BoardId            uuid.UUID `json:"board_id"`
+	UserId             string    `json:"user_id"`
+	OrganizationId     string    `json:"organization_id"`
+	Title              string    `json:"title"`
+	Description        string    `json:"description"`
+	IsPrivate          bool      `json:"is_private"`
+	DetailLevel        string    `json:"detail_level"`
+	StoryPointType     string    `json:"story_point_type"`
+	StoryPointExamples string    `json:"story_point_examples"`
+}
+
+// Stages a generate_board job reports in its progress
+const (
+	GenerationWaitingForAI = "waiting_for_ai"
+	GenerationCreating     = "creating"
+	GenerationProvisioning = "provisioning"
+	GenerationDone         = "done"
+)
+
+// BoardGenerationProgress is the progress of a generate_board job. PanelsCreated counts up to
+// Panels while the board is being created.
+type BoardGenerationProgress struct {
+	Stage         string    `json:"stage"`
+	BoardId       uuid.UUID `json:"board_id"`
+	Panels        int       `json:"panels"`
+	PanelsCreated int       `json:"panels_created"`
+}
+
+// CreateBoardWithAI queues a job that generates the board, and returns the job so the caller can
+// follow it with GetBoardGeneration
+func (c *Controller) CreateBoardWithAI(ctx context.Context, userId string, name string, description string, isPrivate bool, orgId string, detailLevel string, storyPointType string, storyPointExamples string) (*models.Job, error) {
+	payload := GenerateBoardPayload{
+		BoardId:            uuid.New(),
+		UserId:             userId,
+		OrganizationId:     orgId,
+		Title:              name,
+		Description:        description,
+		IsPrivate:          isPrivate,
+		DetailLevel:        detailLevel,
+		StoryPointType:     storyPointType,
+		StoryPointExamples: storyPointExamples,
+	}
+	return GenerateBoard.Enqueue(ctx, jobs.NewQueue(c.store.Jobs), payload, jobs.MaxAttempts(generateBoardAttempts))
+}
+
+// GetBoardGeneration gets a generate_board job queued for the organization. Other jobs are not
+// found, so this can't be used to look at jobs the caller has nothing to do with.
+func (c *Controller) GetBoardGeneration(ctx context.Context, orgId string, jobId string) (*models.Job, error) {
+	job, err := jobs.NewQueue(c.store.Jobs).GetJob(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+	var payload GenerateBoardPayload
+	if job.Type != GenerateBoard.Name || json.Unmarshal(job.Payload, &payload) != nil || payload.OrganizationId != orgId {
+		return nil, controllers.NotFound(sql.ErrNoRows, "board generation", jobId)
+	}
+	return job, nil
+}
+
+// GenerateBoard is the handler for GenerateBoard jobs. If anything fails after the board was
+// created, the board is deleted again so the next attempt starts over.
+func (c *Controller) GenerateBoard(ctx context.Context, payload GenerateBoardPayload) error {
+	boardId := payload.BoardId.String()
+	progress := BoardGenerationProgress{Stage: GenerationWaitingForAI, BoardId: payload.BoardId}
+	board, err := c.store.Boards.Get(ctx, boardId)
+	if err == nil {
+		// An earlier attempt created the board, and either finished before it could be recorded or
+		// stopped partway
+		if board.ProvisioningStatus == models.ProvisioningReady {
+			progress.Stage = GenerationDone
+			return jobs.SetProgress(ctx, progress)
+		}
+		err = c.discardGeneratedBoard(ctx, payload)
+		if err != nil {
+			return fmt.Errorf("failed to delete the board an earlier attempt left: %w", err)
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	err = jobs.SetProgress(ctx, progress)
+	if err != nil {
+		return err
+	}
+	sprints, err := c.requestAIBoard(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	progress.Stage = GenerationCreating
+	progress.Panels = len(sprints)
+	err = jobs.SetProgress(ctx, progress)
+	if err != nil {
+		return err
+	}
+	_, err = c.createBoard(ctx, payload.BoardId, payload.UserId, payload.Title, payload.Description, payload.IsPrivate, payload.OrganizationId)
+	if err != nil {
+		return err
+	}
+	for sprint, tasks := range sprints {
+		err = c.createGeneratedPanel(ctx, boardId, sprint, tasks)
+		if err == nil {
+			progress.PanelsCreated++
+			err = jobs.SetProgress(ctx, progress)
+		}
+		if err != nil {
+			return errors.Join(err, c.discardGeneratedBoard(ctx, payload))
+		}
+	}
+
+	progress.Stage = GenerationProvisioning
+	err = jobs.SetProgress(ctx, progress)
+	if err != nil {
+		return errors.Join(err, c.discardGeneratedBoard(ctx, payload))
+	}
+	// Provisioning deletes the board itself if it fails
+	err = c.ProvisionBoard(ctx, payload.UserId, boardId, payload.OrganizationId)
+	if err != nil {
+		return err
+	}
+	progress.Stage = GenerationDone
+	return jobs.SetProgress(ctx, progress)
+}
+
+func (c *Controller) createGeneratedPanel(ctx context.Context, boardId string, sprint string, tasks []models.AIGeneratedCard) error {
+	newPanel, err := c.CreatePanel(ctx, sprint, boardId)
+	if err != nil {
+		return err
+	}
+	newStack, err := c.CreateStack(ctx, "To-Do", boardId, newPanel.Id.String())
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		CardStoryPointsString := fmt.Sprintf("%v", task.CardStoryPoints)
+		_, err := c.CreateCard(ctx, task.CardTitle, task.CardDesc, CardStoryPointsString, boardId, newStack.Id.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discardGeneratedBoard deletes a generated board along with any roles provisioning it got to
+func (c *Controller) discardGeneratedBoard(ctx context.Context, payload GenerateBoardPayload) error {
+	return c.boardProvisioning(payload.UserId, payload.BoardId.String(), payload.OrganizationId).Compensate(ctx)
+}
+
+// requestAIBoard asks the AI service for the board's sprints and their cards. The service turning
+// the request down won't change on another attempt, so that error is permanent unless it was only
+// rate limited.
+func (c *Controller) requestAIBoard(ctx context.Context, payload GenerateBoardPayload) (models.AIGeneratedSprint, error) {
+	requestUrl := fmt.Sprintf("%s/api/generate/board", c.cfg.AI.APIHost)
+	formData := url.Values{}
+	formData.Add("title", payload.Title)
+	formData.Add("description", payload.Description)
+
+	if payload.DetailLevel == "" {
+		formData.Add("detail_level", "very detailed")
+	} else {
+		formData.Add("detail_level", payload.DetailLevel)
+	}
+	if payload.StoryPointType == "" {
+		formData.Add("story_point_type", "T-Shirt Sizes")
+	} else {
+		formData.Add("story_point_type", payload.StoryPointType)
+	}
+	if payload.StoryPointExamples == "" {
+		formData.Add("story_points", "S, M, L, XL")
+	} else {
+		formData.Add("story_points", payload.StoryPointExamples)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", requestUrl, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/form-data")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the AI service: %w", err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the AI service's response: %w", err)
+	}
+	if res.StatusCode >= 400 {
+		err = fmt.Errorf("AI service responded with %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+		if res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	var sprints models.AIGeneratedSprint
+	err = json.Unmarshal(data, &sprints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the AI service's board: %w", err)
+	}
+	return sprints, nil
+}
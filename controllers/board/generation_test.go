@@ -0,0 +1,175 @@
+package board
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+const generatedSprints = `{
+	"Sprint 1": [{"title": "Set up CI", "description": "Run the tests on every push", "story_points": "S"}],
+	"Sprint 2": [{"title": "Log in", "description": "Sign in with Auth0", "story_points": "M"}, {"title": "Log out", "description": "", "story_points": "S"}]
+}`
+
+// newTestGeneration points the controller at a fake AI service that answers with status and body,
+// and returns a worker that runs generate_board jobs
+func newTestGeneration(t *testing.T, c *Controller, status int, body string) *jobs.Worker {
+	t.Helper()
+	ai := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(status)
+		fmt.Fprint(writer, body)
+	}))
+	t.Cleanup(ai.Close)
+	c.cfg.AI.APIHost = ai.URL
+	worker := jobs.NewWorker(c.store.Jobs, 1, time.Second, time.Minute)
+	jobs.Register(worker, GenerateBoard, c.GenerateBoard)
+	return worker
+}
+
+func runGeneration(t *testing.T, c *Controller, worker *jobs.Worker, organizationId string, jobId string) (*models.Job, BoardGenerationProgress) {
+	t.Helper()
+	ctx := context.Background()
+	err := worker.RunPending(ctx)
+	if err != nil {
+		t.Fatalf("failed to run jobs: %v", err)
+	}
+	job, err := c.GetBoardGeneration(ctx, organizationId, jobId)
+	if err != nil {
+		t.Fatalf("failed to get board generation: %v", err)
+	}
+	var progress BoardGenerationProgress
+	err = json.Unmarshal(job.Progress, &progress)
+	if err != nil {
+		t.Fatalf("failed to decode progress %s: %v", job.Progress, err)
+	}
+	return job, progress
+}
+
+func TestGenerateBoard(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, _ := newTestOrganizationBoard(t)
+	worker := newTestGeneration(t, c, http.StatusOK, generatedSprints)
+
+	job, err := c.CreateBoardWithAI(ctx, "auth0|owner", "Generated", "A web app", false, organizationId, "", "", "")
+	if err != nil {
+		t.Fatalf("failed to queue board generation: %v", err)
+	}
+	if job.Status != models.JobQueued {
+		t.Fatalf("expected the generation to be queued, got %s", job.Status)
+	}
+
+	job, progress := runGeneration(t, c, worker, organizationId, job.Id.String())
+	if job.Status != models.JobSucceeded {
+		t.Fatalf("expected the generation to succeed, got %s: %v", job.Status, job.LastError)
+	}
+	if progress.Stage != GenerationDone || progress.Panels != 2 || progress.PanelsCreated != 2 {
+		t.Fatalf("expected the progress to show both panels done, got %+v", progress)
+	}
+	boardId := progress.BoardId.String()
+	board, err := c.GetCompleteBoardById(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get the generated board: %v", err)
+	}
+	if board.Title != "Generated" || len(board.Panels) != 2 {
+		t.Fatalf("expected the board to have the AI's two panels, got %+v", board)
+	}
+	generated, err := c.GetBoardById(ctx, boardId)
+	if err != nil || generated.ProvisioningStatus != models.ProvisioningReady {
+		t.Fatalf("expected the generated board to be ready, got %+v, %v", generated, err)
+	}
+	if !hasPermission(fake.UserPermissions("auth0|owner"), fmt.Sprintf("org%s:board%s:delete", organizationId, boardId)) {
+		t.Fatal("expected the owner to get the generated board's permissions")
+	}
+}
+
+func TestGenerateBoardStartsOverAfterAnInterruptedAttempt(t *testing.T) {
+	ctx := context.Background()
+	c, _, _, organizationId, _ := newTestOrganizationBoard(t)
+	worker := newTestGeneration(t, c, http.StatusOK, generatedSprints)
+	job, err := c.CreateBoardWithAI(ctx, "auth0|owner", "Generated", "", false, organizationId, "", "", "")
+	if err != nil {
+		t.Fatalf("failed to queue board generation: %v", err)
+	}
+
+	// The server stopped after an earlier attempt created the board and one of its panels
+	var payload GenerateBoardPayload
+	err = json.Unmarshal(job.Payload, &payload)
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	_, err = c.createBoard(ctx, payload.BoardId, "auth0|owner", "Generated", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	_, err = c.CreatePanel(ctx, "Left over", payload.BoardId.String())
+	if err != nil {
+		t.Fatalf("failed to create panel: %v", err)
+	}
+
+	job, progress := runGeneration(t, c, worker, organizationId, job.Id.String())
+	if job.Status != models.JobSucceeded {
+		t.Fatalf("expected the generation to succeed, got %s: %v", job.Status, job.LastError)
+	}
+	board, err := c.GetCompleteBoardById(ctx, progress.BoardId.String())
+	if err != nil {
+		t.Fatalf("failed to get the generated board: %v", err)
+	}
+	if len(board.Panels) != 2 {
+		t.Fatalf("expected the left over panel to be gone, got %+v", board.Panels)
+	}
+}
+
+func TestGenerateBoardFailuresAreJobFailures(t *testing.T) {
+	ctx := context.Background()
+	c, _, _, organizationId, _ := newTestOrganizationBoard(t)
+
+	for _, test := range []struct {
+		status   int
+		body     string
+		expected string
+	}{
+		{http.StatusServiceUnavailable, "model is overloaded", models.JobQueued},
+		{http.StatusOK, "not a board", models.JobQueued},
+		{http.StatusBadRequest, "description is too long", models.JobDead},
+	} {
+		worker := newTestGeneration(t, c, test.status, test.body)
+		job, err := c.CreateBoardWithAI(ctx, "auth0|owner", "Generated", "", false, organizationId, "", "", "")
+		if err != nil {
+			t.Fatalf("failed to queue board generation: %v", err)
+		}
+		job, progress := runGeneration(t, c, worker, organizationId, job.Id.String())
+		if job.Status != test.expected || job.LastError == nil {
+			t.Errorf("expected a %d response with %q to leave the job %s with its error, got %s", test.status, test.body, test.expected, job.Status)
+		}
+		_, err = c.GetBoardById(ctx, progress.BoardId.String())
+		expectCode(t, err, controllers.CodeNotFound)
+	}
+}
+
+func TestGetBoardGenerationOnlyFindsTheOrganizationsJobs(t *testing.T) {
+	ctx := context.Background()
+	c, _, _, organizationId, _ := newTestOrganizationBoard(t)
+	job, err := c.CreateBoardWithAI(ctx, "auth0|owner", "Generated", "", false, organizationId, "", "", "")
+	if err != nil {
+		t.Fatalf("failed to queue board generation: %v", err)
+	}
+	_, err = c.GetBoardGeneration(ctx, uuid.New().String(), job.Id.String())
+	expectCode(t, err, controllers.CodeNotFound)
+
+	other, err := jobs.SendEmail.Enqueue(ctx, jobs.NewQueue(c.store.Jobs), mail.Message{To: "owner@example.com"})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	_, err = c.GetBoardGeneration(ctx, organizationId, other.Id.String())
+	expectCode(t, err, controllers.CodeNotFound)
+}
@@ -0,0 +1,141 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+func newTestOrganizationBoard(t *testing.T) (*Controller, *organization.Controller, *auth0test.Server, string, string) {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+	fake.AddUser(models.User{UserID: "auth0|guest", Email: "guest@example.com"})
+
+	ctx := context.Background()
+	s := store.NewMemory()
+	organizations := organization.NewController(cfg, s)
+	org, err := organizations.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := org.Id.String()
+	err = organizations.ProvisionOrganization(ctx, "auth0|owner", organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+	c := NewController(cfg, s)
+	board, err := c.CreateBoard(ctx, "auth0|owner", "Board", "", true, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	err = c.ProvisionBoard(ctx, "auth0|owner", board.Id.String(), organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision board: %v", err)
+	}
+	return c, organizations, fake, organizationId, board.Id.String()
+}
+
+func hasPermission(permissions []string, name string) bool {
+	for _, permission := range permissions {
+		if permission == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGuestCanReadBoardButNotOrganization(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, boardId := newTestOrganizationBoard(t)
+
+	err := c.AddGuestToBoard(ctx, "auth0|guest", organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to add guest: %v", err)
+	}
+	permissions := fake.UserPermissions("auth0|guest")
+	if !hasPermission(permissions, fmt.Sprintf("org%s:board%s:read", organizationId, boardId)) {
+		t.Fatalf("expected the guest to read the board, got %v", permissions)
+	}
+	if hasPermission(permissions, fmt.Sprintf("org%s:read", organizationId)) {
+		t.Fatalf("expected the guest not to read the organization, got %v", permissions)
+	}
+
+	members, err := user.GetOrgMembers(organizationId)
+	if err != nil {
+		t.Fatalf("failed to get members: %v", err)
+	}
+	for _, member := range *members {
+		if member.UserID == "auth0|guest" {
+			t.Fatal("expected the guest to be left out of the members")
+		}
+	}
+	guests, err := user.GetOrgGuests(organizationId)
+	if err != nil {
+		t.Fatalf("failed to get guests: %v", err)
+	}
+	if len(*guests) != 1 || (*guests)[0].UserID != "auth0|guest" {
+		t.Fatalf("expected the guest to be listed, got %+v", *guests)
+	}
+
+	err = c.RemoveMemberFromBoard(ctx, "auth0|guest", organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to remove guest: %v", err)
+	}
+	guests, err = user.GetOrgGuests(organizationId)
+	if err != nil {
+		t.Fatalf("failed to get guests: %v", err)
+	}
+	if len(*guests) != 0 {
+		t.Fatalf("expected no guests once they left their last board, got %+v", *guests)
+	}
+}
+
+func TestMembersCantBeGuestsAndGuestsCanJoin(t *testing.T) {
+	ctx := context.Background()
+	c, organizations, _, organizationId, boardId := newTestOrganizationBoard(t)
+
+	err := c.AddGuestToBoard(ctx, "auth0|owner", organizationId, boardId)
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != controllers.CodeConflict {
+		t.Fatalf("expected a member not to be added as a guest, got %v", err)
+	}
+
+	err = c.AddGuestToBoard(ctx, "auth0|guest", organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to add guest: %v", err)
+	}
+	err = organizations.AddMember("auth0|guest", organizationId)
+	if err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	isMember, err := organization.IsMember("auth0|guest", organizationId)
+	if err != nil {
+		t.Fatalf("failed to check membership: %v", err)
+	}
+	if !isMember {
+		t.Fatal("expected the guest to become a member")
+	}
+	guests, err := user.GetOrgGuests(organizationId)
+	if err != nil {
+		t.Fatalf("failed to get guests: %v", err)
+	}
+	if len(*guests) != 0 {
+		t.Fatalf("expected the new member to stop being a guest, got %+v", *guests)
+	}
+}
@@ -2,18 +2,18 @@ package board
 
 import (
 	"context"
-	"errors"
+	"database/sql"
+	"time"
 
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
 	"github.com/google/uuid"
 )
 
 func (c *Controller) GetPanelsByBoardId(ctx context.Context, boardId string) (*[]models.Panel, error) {
-	panels := make([]models.Panel, 0)
-	err := c.db.DB.SelectContext(ctx, &panels, `
-		SELECT * FROM Panels WHERE board_id=$1 ORDER BY position ASC;
-	`, boardId)
+	panels, err := c.store.Panels.ListByBoard(ctx, boardId)
 	if err != nil {
 		return nil, err
 	}
@@ -21,21 +21,23 @@ func (c *Controller) GetPanelsByBoardId(ctx context.Context, boardId string) (*[
 }
 
 func (c *Controller) CreatePanel(ctx context.Context, title string, boardId string) (*models.Panel, error) {
-	var nextPosition int
-	err := c.db.DB.GetContext(ctx, &nextPosition, `
-		SELECT COALESCE(MAX(position)+1, 0) AS next_position FROM Panels where board_id=$1;
-	`, boardId)
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	nextPosition, err := c.store.Panels.NextPosition(ctx, boardId)
 	if err != nil {
 		return nil, err
 	}
-	panelId := uuid.New().String()
-	_, err = c.db.DB.ExecContext(ctx, `
-		INSERT INTO Panels (id, title, position, board_id) VALUES ($1, $2, $3, $4);
-	`, panelId, title, nextPosition, boardId)
+	boardUUID, err := uuid.Parse(boardId)
+	if err != nil {
+		return nil, controllers.NotFound(sql.ErrNoRows, "board", boardId)
+	}
+	panelId := uuid.New()
+	err = c.store.Panels.Create(ctx, models.Panel{Id: panelId, Title: title, Position: nextPosition, BoardId: boardUUID})
 	if err != nil {
 		return nil, err
 	}
-	panel, err := c.GetPanelById(ctx, panelId)
+	panel, err := c.GetPanelById(ctx, panelId.String())
 	if err != nil {
 		return nil, err
 	}
@@ -47,82 +49,99 @@ func (c *Controller) CreatePanel(ctx context.Context, title string, boardId stri
 }
 
 func (c *Controller) GetPanelById(ctx context.Context, panelId string) (*models.Panel, error) {
-	var panel models.Panel
-	err := c.db.DB.GetContext(ctx, &panel, `
-		SELECT * FROM Panels WHERE id=$1;
-	`, panelId)
+	panel, err := c.store.Panels.Get(ctx, panelId)
 	if err != nil {
-		return nil, err
+		return nil, controllers.NotFound(err, "panel", panelId)
 	}
-	return &panel, nil
+	return panel, nil
 }
 
-func (c *Controller) UpdatePanelById(ctx context.Context, boardId string, panelId string, title string, position *int) error {
-	panel, err := c.GetPanelById(ctx, panelId)
+func (c *Controller) GetDeletedPanelById(ctx context.Context, boardId string, panelId string) (*models.Panel, error) {
+	panel, err := c.store.Panels.GetDeleted(ctx, boardId, panelId)
 	if err != nil {
-		return err
+		return nil, controllers.NotFound(err, "panel", panelId)
 	}
-	if title == "" {
-		title = panel.Title
+	return panel, nil
+}
+
+func (c *Controller) UpdatePanelById(ctx context.Context, boardId string, panelId string, version int, title string, position *int) error {
+	patch := models.PanelPatch{}
+	if title != "" {
+		patch.Title = models.NewOptional(title)
+	}
+	if position != nil {
+		patch.Position = models.NewOptional(*position)
+	}
+	_, err := c.PatchPanelById(ctx, boardId, panelId, version, patch)
+	return err
+}
+
+// PatchPanelById only changes the fields that are set on the patch
+func (c *Controller) PatchPanelById(ctx context.Context, boardId string, panelId string, version int, patch models.PanelPatch) (*models.Panel, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
 	}
-	if position == nil {
-		position = &panel.Position
+	panel, err := c.GetPanelById(ctx, panelId)
+	if err != nil {
+		return nil, err
 	}
+	title := patch.Title.Or(panel.Title)
+	position := patch.Position.Or(panel.Position)
 
-	if *position != panel.Position {
-		var maxPosition int
-		err := c.db.DB.GetContext(ctx, &maxPosition, `
-			SELECT COALESCE(MAX(position), 0) AS max_position FROM Panels where board_id=$1;
-		`, boardId)
+	if position != panel.Position {
+		nextPosition, err := c.store.Panels.NextPosition(ctx, boardId)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if *position > maxPosition || *position < 0 {
-			return errors.New("position is out of range")
+		if err := checkPosition(position, nextPosition); err != nil {
+			return nil, err
 		}
+	}
 
-		if *position > panel.Position {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Panels SET position=position-1 WHERE board_id=$1 AND position>$2 AND position<=$3;
-			`, boardId, panel.Position, *position)
-		} else {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Panels SET position=position+1 WHERE board_id=$1 AND position<$2 AND position>=$3;
-			`, boardId, panel.Position, *position)
-		}
+	err = claimVersion(ctx, c.store.Panels, panelId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if position != panel.Position {
+		err = moveSiblings(ctx, c.store.Panels, boardId, panel.Position, position)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Panels SET title=$1, position=$2 WHERE id=$3;
-	`, title, *position, panelId)
+	panel.Title = title
+	panel.Position = position
+	err = c.store.Panels.Update(ctx, *panel)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = c.UpdateBoardModifiedAt(ctx, boardId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return c.GetPanelById(ctx, panelId)
 }
 
-func (c *Controller) DeletePanelById(ctx context.Context, boardId string, panelId string) error {
+func (c *Controller) DeletePanelById(ctx context.Context, boardId string, panelId string, version int) error {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
 	panel, err := c.GetPanelById(ctx, panelId)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		DELETE FROM Panels WHERE id=$1;
-	`, panelId)
+	err = claimVersion(ctx, c.store.Panels, panelId, version)
+	if err != nil {
+		return err
+	}
+	// The panel keeps its position while in the trash so RestorePanelById can put it back there
+	err = c.store.Panels.SoftDelete(ctx, panelId, time.Now().UTC())
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Panels SET position=position-1 WHERE board_id=$1 AND position>$2;
-	`, boardId, panel.Position)
+	err = c.store.Panels.ShiftPositions(ctx, boardId, panel.Position+1, store.End, -1)
 	if err != nil {
 		return err
 	}
@@ -135,6 +154,39 @@ func (c *Controller) DeletePanelById(ctx context.Context, boardId string, panelI
 	return nil
 }
 
+func (c *Controller) RestorePanelById(ctx context.Context, boardId string, panelId string) (*models.Panel, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	panel, err := c.GetDeletedPanelById(ctx, boardId, panelId)
+	if err != nil {
+		return nil, err
+	}
+	nextPosition, err := c.store.Panels.NextPosition(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	position := panel.Position
+	if position > nextPosition {
+		position = nextPosition
+	}
+	err = c.store.Panels.ShiftPositions(ctx, boardId, position, store.End, 1)
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Panels.Restore(ctx, panelId, position)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.UpdateBoardModifiedAt(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPanelById(ctx, panelId)
+}
+
 func (c *Controller) GetCompletePanelById(ctx context.Context, panelId string) (*models.CompletePanel, error) {
 	panel, err := c.GetPanelById(ctx, panelId)
 	if err != nil {
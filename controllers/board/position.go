@@ -0,0 +1,51 @@
+package board
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+var errPositionOutOfRange = controllers.NewError(controllers.CodeValidationFailed, "position is out of range")
+
+// checkPosition makes sure an item can be moved to position among siblings whose next free
+// position is nextPosition. Position 0 is always allowed, even when there are no siblings.
+func checkPosition(position int, nextPosition int) error {
+	maxPosition := nextPosition - 1
+	if maxPosition < 0 {
+		maxPosition = 0
+	}
+	if position > maxPosition || position < 0 {
+		return errPositionOutOfRange
+	}
+	return nil
+}
+
+// checkInsertPosition makes sure an item from another parent can be put at position among
+// siblings whose next free position is nextPosition, which includes right after the last of them
+func checkInsertPosition(position int, nextPosition int) error {
+	if position > nextPosition || position < 0 {
+		return errPositionOutOfRange
+	}
+	return nil
+}
+
+// moveBetweenParents closes the gap an item leaves at oldPosition under oldParentId and makes room
+// for it at newPosition under newParentId
+func moveBetweenParents(ctx context.Context, repository store.Positioned, oldParentId string, oldPosition int, newParentId string, newPosition int) error {
+	err := repository.ShiftPositions(ctx, oldParentId, oldPosition+1, store.End, -1)
+	if err != nil {
+		return err
+	}
+	return repository.ShiftPositions(ctx, newParentId, newPosition, store.End, 1)
+}
+
+// moveSiblings shifts the siblings between an item's old and new position by one so the item can
+// take its new position without leaving a gap behind
+func moveSiblings(ctx context.Context, repository store.Positioned, parentId string, oldPosition int, newPosition int) error {
+	if newPosition > oldPosition {
+		return repository.ShiftPositions(ctx, parentId, oldPosition+1, newPosition, -1)
+	}
+	return repository.ShiftPositions(ctx, parentId, newPosition, oldPosition-1, 1)
+}
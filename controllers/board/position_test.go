@@ -0,0 +1,264 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/google/uuid"
+)
+
+func newTestBoard(t *testing.T) (*Controller, string) {
+	t.Helper()
+	c := NewController(&config.Config{}, store.NewMemory())
+	board, err := c.CreateBoard(context.Background(), "auth0|owner", "Board", "", false, uuid.New().String())
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	return c, board.Id.String()
+}
+
+func createPanels(t *testing.T, c *Controller, boardId string, titles ...string) []*models.Panel {
+	t.Helper()
+	panels := make([]*models.Panel, len(titles))
+	for i, title := range titles {
+		panel, err := c.CreatePanel(context.Background(), title, boardId)
+		if err != nil {
+			t.Fatalf("failed to create panel: %v", err)
+		}
+		panels[i] = panel
+	}
+	return panels
+}
+
+func createCards(t *testing.T, c *Controller, boardId string, stackId string, titles ...string) []*models.Card {
+	t.Helper()
+	cards := make([]*models.Card, len(titles))
+	for i, title := range titles {
+		card, err := c.CreateCard(context.Background(), title, "", "1", boardId, stackId)
+		if err != nil {
+			t.Fatalf("failed to create card: %v", err)
+		}
+		cards[i] = card
+	}
+	return cards
+}
+
+func expectPanelOrder(t *testing.T, c *Controller, boardId string, titles ...string) {
+	t.Helper()
+	panels, err := c.GetPanelsByBoardId(context.Background(), boardId)
+	if err != nil {
+		t.Fatalf("failed to get panels: %v", err)
+	}
+	if len(*panels) != len(titles) {
+		t.Fatalf("expected %d panels, got %d", len(titles), len(*panels))
+	}
+	for i, panel := range *panels {
+		if panel.Title != titles[i] || panel.Position != i {
+			t.Fatalf("expected %s at position %d, got %s at position %d", titles[i], i, panel.Title, panel.Position)
+		}
+	}
+}
+
+func expectCardOrder(t *testing.T, c *Controller, stackId string, titles ...string) {
+	t.Helper()
+	cards, err := c.GetCardsByStackId(context.Background(), stackId)
+	if err != nil {
+		t.Fatalf("failed to get cards: %v", err)
+	}
+	if len(*cards) != len(titles) {
+		t.Fatalf("expected %d cards, got %d", len(titles), len(*cards))
+	}
+	for i, card := range *cards {
+		if card.Title != titles[i] || card.Position != i {
+			t.Fatalf("expected %s at position %d, got %s at position %d", titles[i], i, card.Title, card.Position)
+		}
+	}
+}
+
+func TestCreatePanelAppendsToEnd(t *testing.T) {
+	c, boardId := newTestBoard(t)
+	createPanels(t, c, boardId, "A", "B", "C")
+	expectPanelOrder(t, c, boardId, "A", "B", "C")
+}
+
+func TestMovePanelShiftsSiblings(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panels := createPanels(t, c, boardId, "A", "B", "C", "D")
+
+	moved, err := c.PatchPanelById(ctx, boardId, panels[0].Id.String(), panels[0].Version, models.PanelPatch{Position: models.NewOptional(2)})
+	if err != nil {
+		t.Fatalf("failed to move panel forward: %v", err)
+	}
+	expectPanelOrder(t, c, boardId, "B", "C", "A", "D")
+
+	_, err = c.PatchPanelById(ctx, boardId, moved.Id.String(), moved.Version, models.PanelPatch{Position: models.NewOptional(0)})
+	if err != nil {
+		t.Fatalf("failed to move panel back: %v", err)
+	}
+	expectPanelOrder(t, c, boardId, "A", "B", "C", "D")
+}
+
+func TestMovingBumpsShiftedSiblingVersions(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panels := createPanels(t, c, boardId, "A", "B", "C")
+
+	_, err := c.PatchPanelById(ctx, boardId, panels[2].Id.String(), panels[2].Version, models.PanelPatch{Position: models.NewOptional(0)})
+	if err != nil {
+		t.Fatalf("failed to move panel: %v", err)
+	}
+	// A was shifted, so a client still holding its old version must refetch it
+	_, err = c.PatchPanelById(ctx, boardId, panels[0].Id.String(), panels[0].Version, models.PanelPatch{Title: models.NewOptional("A2")})
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("expected version mismatch, got %v", err)
+	}
+}
+
+func TestMoveRejectsPositionOutOfRange(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panels := createPanels(t, c, boardId, "A", "B")
+
+	for _, position := range []int{-1, 2} {
+		_, err := c.PatchPanelById(ctx, boardId, panels[0].Id.String(), panels[0].Version, models.PanelPatch{Position: models.NewOptional(position)})
+		var controllerError *controllers.Error
+		if !errors.As(err, &controllerError) || controllerError.Code != controllers.CodeValidationFailed {
+			t.Fatalf("expected validation error moving to %d, got %v", position, err)
+		}
+	}
+	expectPanelOrder(t, c, boardId, "A", "B")
+}
+
+func TestMoveCardToAnotherStack(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panel := createPanels(t, c, boardId, "Panel")[0]
+	stacks := make([]string, 2)
+	for i, title := range []string{"To-Do", "Done"} {
+		stack, err := c.CreateStack(ctx, title, boardId, panel.Id.String())
+		if err != nil {
+			t.Fatalf("failed to create stack: %v", err)
+		}
+		stacks[i] = stack.Id.String()
+	}
+	todo := createCards(t, c, boardId, stacks[0], "A", "B", "C")
+	createCards(t, c, boardId, stacks[1], "X", "Y")
+
+	// Without a position the card goes after the last card of its new stack
+	_, err := c.PatchCardById(ctx, boardId, stacks[0], todo[0].Id.String(), todo[0].Version, models.CardPatch{StackId: models.NewOptional(stacks[1])})
+	if err != nil {
+		t.Fatalf("failed to move card: %v", err)
+	}
+	expectCardOrder(t, c, stacks[0], "B", "C")
+	expectCardOrder(t, c, stacks[1], "X", "Y", "A")
+
+	cardC, err := c.GetCardById(ctx, todo[2].Id.String())
+	if err != nil {
+		t.Fatalf("failed to get card: %v", err)
+	}
+	_, err = c.PatchCardById(ctx, boardId, stacks[0], cardC.Id.String(), cardC.Version, models.CardPatch{StackId: models.NewOptional(stacks[1]), Position: models.NewOptional(1)})
+	if err != nil {
+		t.Fatalf("failed to move card: %v", err)
+	}
+	expectCardOrder(t, c, stacks[0], "B")
+	expectCardOrder(t, c, stacks[1], "X", "C", "Y", "A")
+
+	cardB, err := c.GetCardById(ctx, todo[1].Id.String())
+	if err != nil {
+		t.Fatalf("failed to get card: %v", err)
+	}
+	_, err = c.PatchCardById(ctx, boardId, stacks[0], cardB.Id.String(), cardB.Version, models.CardPatch{StackId: models.NewOptional(stacks[1]), Position: models.NewOptional(5)})
+	expectCode(t, err, controllers.CodeValidationFailed)
+	expectCardOrder(t, c, stacks[0], "B")
+}
+
+func TestDeleteAndRestoreCardKeepsPositionsContiguous(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panel := createPanels(t, c, boardId, "Panel")[0]
+	stack, err := c.CreateStack(ctx, "Stack", boardId, panel.Id.String())
+	if err != nil {
+		t.Fatalf("failed to create stack: %v", err)
+	}
+	stackId := stack.Id.String()
+	cards := createCards(t, c, boardId, stackId, "A", "B", "C")
+
+	err = c.DeleteCardById(ctx, boardId, stackId, cards[1].Id.String(), cards[1].Version)
+	if err != nil {
+		t.Fatalf("failed to delete card: %v", err)
+	}
+	expectCardOrder(t, c, stackId, "A", "C")
+
+	trash, err := c.GetBoardTrash(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get trash: %v", err)
+	}
+	if len(trash.Cards) != 1 || trash.Cards[0].Id != cards[1].Id {
+		t.Fatalf("expected B in the trash, got %v", trash.Cards)
+	}
+
+	_, err = c.RestoreCardById(ctx, boardId, cards[1].Id.String())
+	if err != nil {
+		t.Fatalf("failed to restore card: %v", err)
+	}
+	expectCardOrder(t, c, stackId, "A", "B", "C")
+}
+
+func TestRestoreClampsPositionToEnd(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panels := createPanels(t, c, boardId, "A", "B", "C")
+
+	err := c.DeletePanelById(ctx, boardId, panels[2].Id.String(), panels[2].Version)
+	if err != nil {
+		t.Fatalf("failed to delete panel: %v", err)
+	}
+	b, err := c.GetPanelById(ctx, panels[1].Id.String())
+	if err != nil {
+		t.Fatalf("failed to get panel: %v", err)
+	}
+	err = c.DeletePanelById(ctx, boardId, b.Id.String(), b.Version)
+	if err != nil {
+		t.Fatalf("failed to delete panel: %v", err)
+	}
+	expectPanelOrder(t, c, boardId, "A")
+
+	// C was at position 2, but with only A left it goes straight after it
+	_, err = c.RestorePanelById(ctx, boardId, panels[2].Id.String())
+	if err != nil {
+		t.Fatalf("failed to restore panel: %v", err)
+	}
+	expectPanelOrder(t, c, boardId, "A", "C")
+}
+
+func TestArchivedBoardIsReadOnly(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	panel := createPanels(t, c, boardId, "A")[0]
+
+	_, err := c.ArchiveBoardById(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to archive board: %v", err)
+	}
+	_, err = c.CreatePanel(ctx, "B", boardId)
+	if !errors.Is(err, ErrBoardArchived) {
+		t.Fatalf("expected board archived error, got %v", err)
+	}
+	err = c.DeletePanelById(ctx, boardId, panel.Id.String(), panel.Version)
+	if !errors.Is(err, ErrBoardArchived) {
+		t.Fatalf("expected board archived error, got %v", err)
+	}
+
+	_, err = c.UnarchiveBoardById(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to unarchive board: %v", err)
+	}
+	createPanels(t, c, boardId, "B")
+	expectPanelOrder(t, c, boardId, "A", "B")
+}
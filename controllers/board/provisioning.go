@@ -0,0 +1,119 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// ProvisionBoard sets up the roles and permissions of a board made by CreateBoard in the
+// background, so creating a board doesn't wait on Auth0
+var ProvisionBoard = jobs.Type[ProvisionBoardPayload]{Name: "provision_board"}
+
+type ProvisionBoardPayload struct {
+	BoardId        string `json:"board_id"`
+	OwnerId        string `json:"owner_id"`
+	OrganizationId string `json:"organization_id"`
+}
+
+// ResumeProvisioning runs ResumeProvisioning every so often, see jobs.RegisterRecurring
+var ResumeProvisioning = jobs.Type[struct{}]{Name: "resume_board_provisioning"}
+
+// ProvisionQueuedBoard is the handler for ProvisionBoard jobs. Boards that are already ready or
+// gone, like when ResumeProvisioning got to them first, are left alone. Provisioning that fails
+// deletes the board, so it isn't tried again.
+func (c *Controller) ProvisionQueuedBoard(ctx context.Context, payload ProvisionBoardPayload) error {
+	board, err := c.store.Boards.Get(ctx, payload.BoardId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if board.ProvisioningStatus != models.ProvisioningInProgress {
+		return nil
+	}
+	err = c.ProvisionBoard(ctx, payload.OwnerId, payload.BoardId, payload.OrganizationId)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	return nil
+}
+
+// ResumeProvisioning picks up boards created more than staleAfter ago whose provisioning never
+// finished, like when the server restarted part way through, and finishes it. Boards whose
+// provisioning failed and couldn't be undone are undone again. Each board that fails is logged
+// and tried again next time.
+//
+// Boards a provision_board or generate_board job is still going to work on are left to the job,
+// and each board is claimed first, so no two servers resume the same one.
+func (c *Controller) ResumeProvisioning(ctx context.Context, staleAfter time.Duration) error {
+	cutoff := time.Now().UTC().Add(-staleAfter)
+	busy, err := c.boardsWithActiveJobs(ctx)
+	if err != nil {
+		return err
+	}
+	unfinished, err := c.store.Boards.ListByProvisioningStatus(ctx, models.ProvisioningInProgress, cutoff)
+	if err != nil {
+		return err
+	}
+	for _, board := range unfinished {
+		claimed, err := c.claimProvisioning(ctx, busy, board, cutoff)
+		if err == nil && claimed {
+			err = c.boardProvisioning(board.OwnerId, board.Id.String(), board.OrganizationId.String()).Run(ctx)
+		}
+		if err != nil {
+			log.Printf("Failed to resume provisioning board %s: %v", board.Id, err)
+		}
+	}
+	failed, err := c.store.Boards.ListByProvisioningStatus(ctx, models.ProvisioningFailed, cutoff)
+	if err != nil {
+		return err
+	}
+	for _, board := range failed {
+		claimed, err := c.claimProvisioning(ctx, busy, board, cutoff)
+		if err == nil && claimed {
+			err = c.boardProvisioning(board.OwnerId, board.Id.String(), board.OrganizationId.String()).Compensate(ctx)
+		}
+		if err != nil {
+			log.Printf("Failed to undo provisioning board %s: %v", board.Id, err)
+		}
+	}
+	return nil
+}
+
+// boardsWithActiveJobs lists the ids of the boards that queued or running jobs are provisioning or
+// generating
+func (c *Controller) boardsWithActiveJobs(ctx context.Context) (map[string]bool, error) {
+	queue := jobs.NewQueue(c.store.Jobs)
+	busy := make(map[string]bool)
+	provisioning, err := ProvisionBoard.Active(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+	for _, payload := range provisioning {
+		busy[payload.BoardId] = true
+	}
+	generating, err := GenerateBoard.Active(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+	for _, payload := range generating {
+		busy[payload.BoardId.String()] = true
+	}
+	return busy, nil
+}
+
+// claimProvisioning takes over the board's provisioning unless a job is working on it, it has
+// changed since it was listed or someone else claimed it since cutoff
+func (c *Controller) claimProvisioning(ctx context.Context, busy map[string]bool, board models.Board, cutoff time.Time) (bool, error) {
+	if busy[board.Id.String()] {
+		return false, nil
+	}
+	return c.store.Boards.ClaimProvisioning(ctx, board.Id.String(), board.ProvisioningStatus, cutoff)
+}
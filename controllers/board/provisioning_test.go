@@ -0,0 +1,237 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+// accessStartingWith lists the names of the roles and permissions starting with prefix
+func accessStartingWith(fake *auth0test.Server, prefix string) []string {
+	names := make([]string, 0)
+	for _, role := range fake.Roles() {
+		if strings.HasPrefix(role.Name, prefix) {
+			names = append(names, role.Name)
+		}
+	}
+	for _, permission := range fake.Permissions() {
+		if strings.HasPrefix(permission, prefix) {
+			names = append(names, permission)
+		}
+	}
+	return names
+}
+
+func TestFailedProvisioningDeletesTheBoard(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, _ := newTestOrganizationBoard(t)
+	board, err := c.CreateBoard(ctx, "auth0|owner", "Board", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	if board.ProvisioningStatus != models.ProvisioningInProgress {
+		t.Fatalf("expected a new board to be provisioning, got %s", board.ProvisioningStatus)
+	}
+	boardId := board.Id.String()
+
+	// Giving the owner their roles is the last step, so everything before it has to be undone
+	fake.FailRequests("POST", "/users/*", 1)
+	err = c.ProvisionBoard(ctx, "auth0|owner", boardId, organizationId)
+	if err == nil {
+		t.Fatal("expected provisioning to fail")
+	}
+	_, err = c.GetBoardById(ctx, boardId)
+	expectCode(t, err, controllers.CodeNotFound)
+	prefix := fmt.Sprintf("org%s:board%s:", organizationId, boardId)
+	if access := accessStartingWith(fake, prefix); len(access) > 0 {
+		t.Fatalf("expected the board's roles and permissions to be deleted, got %v", access)
+	}
+	if !hasPermission(fake.Permissions(), fmt.Sprintf("org%s:read", organizationId)) {
+		t.Fatal("expected the organization to keep its permissions")
+	}
+}
+
+func TestCreateBoardQueuesItsProvisioning(t *testing.T) {
+	ctx := context.Background()
+	c, organizations, fake, organizationId, _ := newTestOrganizationBoard(t)
+	worker := jobs.NewWorker(c.store.Jobs, 1, time.Second, time.Minute)
+	jobs.Register(worker, ProvisionBoard, c.ProvisionQueuedBoard)
+	jobs.Register(worker, organization.ProvisionOrganization, organizations.ProvisionQueuedOrganization)
+	queue := jobs.NewQueue(c.store.Jobs)
+
+	board, err := c.CreateBoard(ctx, "auth0|owner", "Board", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	boardId := board.Id.String()
+	deleted, err := c.CreateBoard(ctx, "auth0|owner", "Deleted", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	deletedId := deleted.Id.String()
+	err = c.store.Boards.Delete(ctx, deletedId)
+	if err != nil {
+		t.Fatalf("failed to delete board: %v", err)
+	}
+
+	// The organization and board that were provisioned right away are left alone too
+	err = worker.RunPending(ctx)
+	if err != nil {
+		t.Fatalf("failed to run jobs: %v", err)
+	}
+	board, err = c.GetBoardById(ctx, boardId)
+	if err != nil || board.ProvisioningStatus != models.ProvisioningReady {
+		t.Fatalf("expected the board to be ready once its job ran, got %+v, %v", board, err)
+	}
+	if !hasPermission(fake.UserPermissions("auth0|owner"), fmt.Sprintf("org%s:board%s:delete", organizationId, boardId)) {
+		t.Fatal("expected the owner to get the board's permissions")
+	}
+	if access := accessStartingWith(fake, fmt.Sprintf("org%s:board%s:", organizationId, deletedId)); len(access) > 0 {
+		t.Fatalf("expected nothing to be provisioned for a deleted board, got %v", access)
+	}
+	succeeded, err := queue.ListJobs(ctx, models.JobSucceeded, 10)
+	if err != nil || len(succeeded) != 4 {
+		t.Fatalf("expected all four provisioning jobs to succeed, got %v, %v", succeeded, err)
+	}
+
+	// Provisioning that fails deletes the board, so its job isn't retried
+	fake.FailRequests("POST", "/users/*", 1)
+	failing, err := c.CreateBoard(ctx, "auth0|owner", "Failing", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	err = worker.RunPending(ctx)
+	if err != nil {
+		t.Fatalf("failed to run jobs: %v", err)
+	}
+	_, err = c.GetBoardById(ctx, failing.Id.String())
+	expectCode(t, err, controllers.CodeNotFound)
+	dead, err := queue.ListJobs(ctx, models.JobDead, 10)
+	if err != nil || len(dead) != 1 || dead[0].Type != ProvisionBoard.Name {
+		t.Fatalf("expected the failed provisioning to be dead, got %v, %v", dead, err)
+	}
+}
+
+func TestResumeProvisioning(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, _ := newTestOrganizationBoard(t)
+
+	// The server stopped after creating the owner role but before recording it, and the board's
+	// provision_board job never got queued
+	interrupted, err := c.createBoard(ctx, uuid.New(), "auth0|owner", "Interrupted", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	interruptedId := interrupted.Id.String()
+	ownerRoleName := fmt.Sprintf("org%s:board%s:owner", organizationId, interruptedId)
+	_, err = auth.CreateRole(ownerRoleName, "")
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+
+	// Undoing failed too, since the role couldn't be deleted
+	failed, err := c.createBoard(ctx, uuid.New(), "auth0|owner", "Failed", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	failedId := failed.Id.String()
+	fake.FailRequests("POST", "/users/*", 1)
+	fake.FailRequests("DELETE", "/roles/*", 1)
+	err = c.ProvisionBoard(ctx, "auth0|owner", failedId, organizationId)
+	if err == nil {
+		t.Fatal("expected provisioning to fail")
+	}
+	failed, err = c.GetBoardById(ctx, failedId)
+	if err != nil {
+		t.Fatalf("expected the board to be kept until it can be undone, got %v", err)
+	}
+	if failed.ProvisioningStatus != models.ProvisioningFailed {
+		t.Fatalf("expected the board to be failed, got %s", failed.ProvisioningStatus)
+	}
+
+	err = c.ResumeProvisioning(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to resume provisioning: %v", err)
+	}
+	interrupted, err = c.GetBoardById(ctx, interruptedId)
+	if err != nil || interrupted.ProvisioningStatus != models.ProvisioningInProgress {
+		t.Fatalf("expected boards that were just created to be left alone, got %+v, %v", interrupted, err)
+	}
+
+	err = c.ResumeProvisioning(ctx, -time.Second)
+	if err != nil {
+		t.Fatalf("failed to resume provisioning: %v", err)
+	}
+	interrupted, err = c.GetBoardById(ctx, interruptedId)
+	if err != nil || interrupted.ProvisioningStatus != models.ProvisioningReady {
+		t.Fatalf("expected the interrupted board to be ready, got %+v, %v", interrupted, err)
+	}
+	ownerRoles := 0
+	for _, role := range fake.Roles() {
+		if role.Name == ownerRoleName {
+			ownerRoles++
+		}
+	}
+	if ownerRoles != 1 {
+		t.Fatalf("expected the owner role to be reused, got %d of them", ownerRoles)
+	}
+	if !hasPermission(fake.UserPermissions("auth0|owner"), fmt.Sprintf("org%s:board%s:delete", organizationId, interruptedId)) {
+		t.Fatal("expected the owner to get the interrupted board's permissions")
+	}
+
+	_, err = c.GetBoardById(ctx, failedId)
+	expectCode(t, err, controllers.CodeNotFound)
+	if access := accessStartingWith(fake, fmt.Sprintf("org%s:board%s:", organizationId, failedId)); len(access) > 0 {
+		t.Fatalf("expected the failed board's roles and permissions to be deleted, got %v", access)
+	}
+}
+
+func TestResumeProvisioningLeavesBoardsOthersAreWorkingOn(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, _ := newTestOrganizationBoard(t)
+
+	// Its provision_board job is still queued
+	queued, err := c.CreateBoard(ctx, "auth0|owner", "Queued", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	err = c.ResumeProvisioning(ctx, -time.Minute)
+	if err != nil {
+		t.Fatalf("failed to resume provisioning: %v", err)
+	}
+	queued, err = c.GetBoardById(ctx, queued.Id.String())
+	if err != nil || queued.ProvisioningStatus != models.ProvisioningInProgress {
+		t.Fatalf("expected the board to be left to its job, got %+v, %v", queued, err)
+	}
+	if access := accessStartingWith(fake, fmt.Sprintf("org%s:board%s:", organizationId, queued.Id)); len(access) > 0 {
+		t.Fatalf("expected nothing to be provisioned for the board, got %v", access)
+	}
+
+	// Only one server gets to resume a board until its claim is stale
+	boardId := queued.Id.String()
+	cutoff := time.Now().Add(-time.Minute)
+	for _, expected := range []bool{true, false} {
+		claimed, err := c.store.Boards.ClaimProvisioning(ctx, boardId, models.ProvisioningInProgress, cutoff)
+		if err != nil || claimed != expected {
+			t.Fatalf("expected claiming the board to return %v, got %v, %v", expected, claimed, err)
+		}
+	}
+	claimed, err := c.store.Boards.ClaimProvisioning(ctx, boardId, models.ProvisioningFailed, time.Now().Add(time.Minute))
+	if err != nil || claimed {
+		t.Fatalf("expected a board that isn't failed not to be claimed to be undone, got %v, %v", claimed, err)
+	}
+	claimed, err = c.store.Boards.ClaimProvisioning(ctx, boardId, models.ProvisioningInProgress, time.Now().Add(time.Minute))
+	if err != nil || !claimed {
+		t.Fatalf("expected a stale claim to be taken over, got %v, %v", claimed, err)
+	}
+}
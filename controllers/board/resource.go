@@ -0,0 +1,111 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// ResourceIds are the ids in a request's path, from the organization down to the card. Ids that
+// aren't in the path are left empty.
+type ResourceIds struct {
+	OrganizationId string
+	BoardId        string
+	PanelId        string
+	StackId        string
+	CardId         string
+}
+
+// Resources are what a request's path points at. Only the ones whose ids were given are set.
+type Resources struct {
+	Board *models.Board
+	Panel *models.Panel
+	Stack *models.Stack
+	Card  *models.Card
+}
+
+// ResolveResources loads the board, panel, stack and card in ids and makes sure each one belongs to
+// the one before it, so a card can't be reached through a board it isn't on. Anything that doesn't
+// exist or belongs somewhere else is not found.
+//
+// includeTrash lets the panel, stack and card be in the trash, and the board too when it is the
+// last id given, so they can be restored. Whether they can actually be restored is up to the
+// restore itself.
+func (c *Controller) ResolveResources(ctx context.Context, ids ResourceIds, includeTrash bool) (*Resources, error) {
+	resources := &Resources{}
+	if ids.BoardId == "" {
+		return resources, nil
+	}
+
+	board, err := c.store.Boards.Get(ctx, ids.BoardId)
+	if errors.Is(err, sql.ErrNoRows) && includeTrash && ids.PanelId == "" {
+		board, err = c.store.Boards.GetDeleted(ctx, ids.OrganizationId, ids.BoardId)
+	}
+	if err != nil {
+		return nil, controllers.NotFound(err, "board", ids.BoardId)
+	}
+	if !sameId(board.OrganizationId.String(), ids.OrganizationId) {
+		return nil, notInParent("board", ids.BoardId, "organization", ids.OrganizationId)
+	}
+	resources.Board = board
+	if ids.PanelId == "" {
+		return resources, nil
+	}
+
+	panel, err := c.store.Panels.Get(ctx, ids.PanelId)
+	if errors.Is(err, sql.ErrNoRows) && includeTrash {
+		panel, err = c.store.Panels.GetDeleted(ctx, ids.BoardId, ids.PanelId)
+	}
+	if err != nil {
+		return nil, controllers.NotFound(err, "panel", ids.PanelId)
+	}
+	if !sameId(panel.BoardId.String(), ids.BoardId) {
+		return nil, notInParent("panel", ids.PanelId, "board", ids.BoardId)
+	}
+	resources.Panel = panel
+	if ids.StackId == "" {
+		return resources, nil
+	}
+
+	stack, err := c.store.Stacks.Get(ctx, ids.StackId)
+	if errors.Is(err, sql.ErrNoRows) && includeTrash {
+		stack, err = c.store.Stacks.GetDeleted(ctx, ids.BoardId, ids.StackId)
+	}
+	if err != nil {
+		return nil, controllers.NotFound(err, "stack", ids.StackId)
+	}
+	if !sameId(stack.PanelId.String(), ids.PanelId) {
+		return nil, notInParent("stack", ids.StackId, "panel", ids.PanelId)
+	}
+	resources.Stack = stack
+	if ids.CardId == "" {
+		return resources, nil
+	}
+
+	card, err := c.store.Cards.Get(ctx, ids.CardId)
+	if errors.Is(err, sql.ErrNoRows) && includeTrash {
+		card, err = c.store.Cards.GetDeleted(ctx, ids.BoardId, ids.CardId)
+	}
+	if err != nil {
+		return nil, controllers.NotFound(err, "card", ids.CardId)
+	}
+	if !sameId(card.StackId.String(), ids.StackId) {
+		return nil, notInParent("card", ids.CardId, "stack", ids.StackId)
+	}
+	resources.Card = card
+	return resources, nil
+}
+
+// sameId compares a stored id with one from a path, which might not be lower case
+func sameId(id string, pathId string) bool {
+	return strings.EqualFold(id, pathId)
+}
+
+func notInParent(resource string, id string, parent string, parentId string) error {
+	return controllers.NewError(controllers.CodeNotFound, fmt.Sprintf("no %s found with id %s in %s %s", resource, id, parent, parentId))
+}
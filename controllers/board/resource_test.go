@@ -0,0 +1,68 @@
+package board
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/google/uuid"
+)
+
+func TestResolveResourcesChecksEachParent(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	board, err := c.GetBoardById(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get board: %v", err)
+	}
+	organizationId := board.OrganizationId.String()
+	otherBoard, err := c.CreateBoard(ctx, "auth0|owner", "Other", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	panel := createPanels(t, c, boardId, "Panel")[0]
+	stack, err := c.CreateStack(ctx, "Stack", boardId, panel.Id.String())
+	if err != nil {
+		t.Fatalf("failed to create stack: %v", err)
+	}
+	card := createCards(t, c, boardId, stack.Id.String(), "Card")[0]
+
+	ids := ResourceIds{
+		OrganizationId: organizationId,
+		BoardId:        boardId,
+		PanelId:        panel.Id.String(),
+		StackId:        stack.Id.String(),
+		CardId:         card.Id.String(),
+	}
+	resources, err := c.ResolveResources(ctx, ids, false)
+	if err != nil {
+		t.Fatalf("failed to resolve resources: %v", err)
+	}
+	if resources.Board.Id != board.Id || resources.Panel.Id != panel.Id || resources.Stack.Id != stack.Id || resources.Card.Id != card.Id {
+		t.Fatalf("expected the board, panel, stack and card, got %+v", resources)
+	}
+
+	elsewhere := ids
+	elsewhere.BoardId = otherBoard.Id.String()
+	_, err = c.ResolveResources(ctx, elsewhere, false)
+	expectCode(t, err, controllers.CodeNotFound)
+	elsewhere = ids
+	elsewhere.OrganizationId = uuid.New().String()
+	_, err = c.ResolveResources(ctx, elsewhere, false)
+	expectCode(t, err, controllers.CodeNotFound)
+	elsewhere = ids
+	elsewhere.StackId = uuid.New().String()
+	_, err = c.ResolveResources(ctx, elsewhere, false)
+	expectCode(t, err, controllers.CodeNotFound)
+
+	err = c.DeleteCardById(ctx, boardId, stack.Id.String(), card.Id.String(), card.Version)
+	if err != nil {
+		t.Fatalf("failed to delete card: %v", err)
+	}
+	_, err = c.ResolveResources(ctx, ids, false)
+	expectCode(t, err, controllers.CodeNotFound)
+	resources, err = c.ResolveResources(ctx, ids, true)
+	if err != nil || resources.Card.Id != card.Id {
+		t.Fatalf("expected the card in the trash to resolve for restoring, got %v", err)
+	}
+}
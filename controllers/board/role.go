@@ -0,0 +1,282 @@
+package board
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// The roles every board is created with. Adding members relies on the member role and the owner
+// role is how the board is managed, so neither can be renamed or deleted, and the owner role can't
+// be changed at all.
+const (
+	boardOwnerRole  = "owner"
+	boardMemberRole = "member"
+)
+
+var boardRoleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// GetBoardRoles lists the board's roles, including the owner and member roles
+func (c *Controller) GetBoardRoles(orgId string, boardId string) (*[]auth.Role, error) {
+	boardRolePrefix := fmt.Sprintf("org%s:board%s:", orgId, boardId)
+	roles, err := auth.GetRoles(&boardRolePrefix)
+	if err != nil {
+		return nil, err
+	}
+	boardRoles := make([]auth.Role, 0, len(*roles))
+	for _, role := range *roles {
+		if strings.HasPrefix(role.Name, boardRolePrefix) {
+			boardRoles = append(boardRoles, role)
+		}
+	}
+	return &boardRoles, nil
+}
+
+// GetBoardRole returns the role if it is one of the board's
+func (c *Controller) GetBoardRole(orgId string, boardId string, roleId string) (*auth.Role, error) {
+	role, err := auth.GetRoleById(roleId)
+	if err != nil || !strings.HasPrefix(role.Name, fmt.Sprintf("org%s:board%s:", orgId, boardId)) {
+		return nil, controllers.NewError(controllers.CodeNotFound, fmt.Sprintf("no role found with id %s on board %s", roleId, boardId))
+	}
+	return role, nil
+}
+
+// CreateBoardRole makes a role on the board with some of the board's permissions
+func (c *Controller) CreateBoardRole(orgId string, boardId string, name string, description string, permissionNames []string) (*auth.Role, error) {
+	err := checkBoardRoleName(name)
+	if err != nil {
+		return nil, err
+	}
+	err = checkBoardPermissions(orgId, boardId, permissionNames)
+	if err != nil {
+		return nil, err
+	}
+	roleName := fmt.Sprintf("org%s:board%s:%s", orgId, boardId, name)
+	roles, err := c.GetBoardRoles(orgId, boardId)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range *roles {
+		if strings.EqualFold(role.Name, roleName) {
+			return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("board %s already has a role named %s", boardId, name))
+		}
+	}
+
+	role, err := auth.CreateRole(roleName, description)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissionNames) > 0 {
+		err = auth.AddPermissionsToRole(role.Id, permissionNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add permissions to role %s: %w", role.Id, err)
+		}
+	}
+	return role, nil
+}
+
+// UpdateBoardRole renames the role, changes its description and sets its permissions to exactly
+// permissionNames. Empty names and descriptions and nil permissionNames are left as they are.
+func (c *Controller) UpdateBoardRole(orgId string, boardId string, roleId string, name string, description string, permissionNames []string) (*auth.Role, error) {
+	role, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return nil, err
+	}
+	boardRolePrefix := fmt.Sprintf("org%s:board%s:", orgId, boardId)
+	currentName := strings.TrimPrefix(role.Name, boardRolePrefix)
+	if currentName == boardOwnerRole {
+		return nil, controllers.NewError(controllers.CodeForbidden, "the owner role of a board can't be changed")
+	}
+	if name != "" && name != currentName {
+		if currentName == boardMemberRole {
+			return nil, controllers.NewError(controllers.CodeForbidden, "the member role of a board can't be renamed")
+		}
+		err = checkBoardRoleName(name)
+		if err != nil {
+			return nil, err
+		}
+		roles, err := c.GetBoardRoles(orgId, boardId)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range *roles {
+			if other.Id != roleId && strings.EqualFold(other.Name, boardRolePrefix+name) {
+				return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("board %s already has a role named %s", boardId, name))
+			}
+		}
+		role.Name = boardRolePrefix + name
+	}
+	if description != "" {
+		role.Description = description
+	}
+	err = checkBoardPermissions(orgId, boardId, permissionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	err = auth.UpdateRole(role.Id, role.Name, role.Description)
+	if err != nil {
+		return nil, err
+	}
+	if permissionNames == nil {
+		return role, nil
+	}
+	currentPermissions, err := auth.GetRolePermissions(roleId)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(permissionNames))
+	for _, permissionName := range permissionNames {
+		wanted[permissionName] = true
+	}
+	removePermissionNames := make([]string, 0)
+	for _, permission := range *currentPermissions {
+		if wanted[permission.Name] {
+			delete(wanted, permission.Name)
+		} else {
+			removePermissionNames = append(removePermissionNames, permission.Name)
+		}
+	}
+	addPermissionNames := make([]string, 0, len(wanted))
+	for _, permissionName := range permissionNames {
+		if wanted[permissionName] {
+			addPermissionNames = append(addPermissionNames, permissionName)
+		}
+	}
+	if len(addPermissionNames) > 0 {
+		err = auth.AddPermissionsToRole(roleId, addPermissionNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add permissions %v to role %s: %w", addPermissionNames, roleId, err)
+		}
+	}
+	if len(removePermissionNames) > 0 {
+		err = auth.RemovePermissionsFromRole(roleId, removePermissionNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove permissions %v from role %s: %w", removePermissionNames, roleId, err)
+		}
+	}
+	return role, nil
+}
+
+func (c *Controller) DeleteBoardRole(orgId string, boardId string, roleId string) error {
+	role, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimPrefix(role.Name, fmt.Sprintf("org%s:board%s:", orgId, boardId))
+	if name == boardOwnerRole || name == boardMemberRole {
+		return controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("the %s role of a board can't be deleted", name))
+	}
+	return auth.DeleteRole(roleId)
+}
+
+func (c *Controller) GetBoardRolePermissions(orgId string, boardId string, roleId string) (*[]auth.Permission, error) {
+	_, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return nil, err
+	}
+	return auth.GetRolePermissions(roleId)
+}
+
+func (c *Controller) GetBoardRoleMembers(orgId string, boardId string, roleId string) (*[]models.User, error) {
+	_, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return nil, err
+	}
+	return user.GetUsersWithRole(roleId)
+}
+
+// AddMemberToBoardRole gives the role to someone who is in the organization or already on the
+// board as a guest. The caller with tokenCustomClaims must be allowed to give the role, see
+// CheckCanGrantBoardRole.
+func (c *Controller) AddMemberToBoardRole(tokenCustomClaims *auth.CustomClaims, orgId string, boardId string, roleId string, userId string) error {
+	role, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return err
+	}
+	rolePermissions, err := auth.GetRolePermissions(roleId)
+	if err != nil {
+		return err
+	}
+	err = CheckCanGrantBoardRole(tokenCustomClaims, orgId, boardId, role.Name, *rolePermissions)
+	if err != nil {
+		return err
+	}
+	isMember, err := organization.IsMember(userId, orgId)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		roles, err := auth.GetUserRoles(userId)
+		if err != nil {
+			return err
+		}
+		boardRolePrefix := fmt.Sprintf("org%s:board%s:", orgId, boardId)
+		onBoard := false
+		for _, role := range *roles {
+			if strings.HasPrefix(role.Name, boardRolePrefix) {
+				onBoard = true
+				break
+			}
+		}
+		if !onBoard {
+			return controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("user %s is not a member of organization %s or board %s", userId, orgId, boardId))
+		}
+	}
+	return auth.AddUserToRole(userId, roleId)
+}
+
+// CheckCanGrantBoardRole makes sure the caller with tokenCustomClaims already has every permission
+// the board role gives, so giving out a role can't get anyone more than the caller has. Only those
+// who can update the board can make anyone its owner.
+func CheckCanGrantBoardRole(tokenCustomClaims *auth.CustomClaims, orgId string, boardId string, roleName string, rolePermissions []auth.Permission) error {
+	if roleName == fmt.Sprintf("org%s:board%s:%s", orgId, boardId, boardOwnerRole) && !tokenCustomClaims.HasPermission(fmt.Sprintf("org%s:board%s:update", orgId, boardId)) {
+		return controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("only those who can update board %s can give its owner role", boardId))
+	}
+	for _, permission := range rolePermissions {
+		if !tokenCustomClaims.HasPermission(permission.Name) {
+			return controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("role %s gives the permission %s, which the caller doesn't have", roleName, permission.Name))
+		}
+	}
+	return nil
+}
+
+func (c *Controller) RemoveMemberFromBoardRole(orgId string, boardId string, roleId string, userId string) error {
+	_, err := c.GetBoardRole(orgId, boardId, roleId)
+	if err != nil {
+		return err
+	}
+	err = auth.RemoveUserFromRole(userId, roleId)
+	if err != nil {
+		return err
+	}
+	return leaveIfLastGuestBoard(userId, orgId)
+}
+
+func checkBoardRoleName(name string) error {
+	if !boardRoleNamePattern.MatchString(name) {
+		return controllers.NewError(controllers.CodeValidationFailed, "role names can only have letters, numbers, _ and -, and be up to 64 characters long")
+	}
+	if strings.EqualFold(name, boardOwnerRole) || strings.EqualFold(name, boardMemberRole) {
+		return controllers.NewError(controllers.CodeConflict, fmt.Sprintf("every board already has a %s role", strings.ToLower(name)))
+	}
+	return nil
+}
+
+// checkBoardPermissions makes sure roles are only made from the board's own permissions
+func checkBoardPermissions(orgId string, boardId string, permissionNames []string) error {
+	prefix := fmt.Sprintf("org%s:board%s:", orgId, boardId)
+	for _, permissionName := range permissionNames {
+		action, found := strings.CutPrefix(permissionName, prefix)
+		if _, isAction := boardActions[action]; !found || !isAction {
+			return controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("%s is not a permission of board %s", permissionName, boardId))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func expectCode(t *testing.T, err error, code controllers.ErrorCode) {
+	t.Helper()
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != code {
+		t.Fatalf("expected %s error, got %v", code, err)
+	}
+}
+
+func TestCustomBoardRoleGivesItsPermissions(t *testing.T) {
+	c, organizations, fake, organizationId, boardId := newTestOrganizationBoard(t)
+	fake.AddUser(models.User{UserID: "auth0|editor", Email: "editor@example.com"})
+	err := organizations.AddMember("auth0|editor", organizationId)
+	if err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	boardPerm := func(action string) string {
+		return fmt.Sprintf("org%s:board%s:%s", organizationId, boardId, action)
+	}
+
+	role, err := c.CreateBoardRole(organizationId, boardId, "editor", "Can change the layout", []string{boardPerm("read"), boardPerm("create_panel")})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	owner := &auth.CustomClaims{Permissions: fake.UserPermissions("auth0|owner")}
+	err = c.AddMemberToBoardRole(owner, organizationId, boardId, role.Id, "auth0|editor")
+	if err != nil {
+		t.Fatalf("failed to add member to role: %v", err)
+	}
+	if !hasPermission(fake.UserPermissions("auth0|editor"), boardPerm("create_panel")) {
+		t.Fatalf("expected the editor to create panels, got %v", fake.UserPermissions("auth0|editor"))
+	}
+
+	_, err = c.UpdateBoardRole(organizationId, boardId, role.Id, "", "", []string{boardPerm("read"), boardPerm("create_stack")})
+	if err != nil {
+		t.Fatalf("failed to update role: %v", err)
+	}
+	permissions := fake.UserPermissions("auth0|editor")
+	if hasPermission(permissions, boardPerm("create_panel")) || !hasPermission(permissions, boardPerm("create_stack")) {
+		t.Fatalf("expected the editor's permissions to follow the role, got %v", permissions)
+	}
+
+	roles, err := c.GetBoardRoles(organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to get roles: %v", err)
+	}
+	if len(*roles) != 3 {
+		t.Fatalf("expected the owner, member and editor roles, got %+v", *roles)
+	}
+
+	err = c.DeleteBoardRole(organizationId, boardId, role.Id)
+	if err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+	if hasPermission(fake.UserPermissions("auth0|editor"), boardPerm("read")) {
+		t.Fatal("expected the editor to lose the board with the role")
+	}
+}
+
+func TestBoardRolesStayWithinTheBoard(t *testing.T) {
+	c, _, fake, organizationId, boardId := newTestOrganizationBoard(t)
+
+	_, err := c.CreateBoardRole(organizationId, boardId, "viewer", "", []string{fmt.Sprintf("org%s:boards_admin", organizationId)})
+	expectCode(t, err, controllers.CodeValidationFailed)
+	_, err = c.CreateBoardRole(organizationId, boardId, "Owner", "", nil)
+	expectCode(t, err, controllers.CodeConflict)
+	_, err = c.CreateBoardRole(organizationId, boardId, "two words", "", nil)
+	expectCode(t, err, controllers.CodeValidationFailed)
+
+	roles, err := c.GetBoardRoles(organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to get roles: %v", err)
+	}
+	for _, role := range *roles {
+		err = c.DeleteBoardRole(organizationId, boardId, role.Id)
+		expectCode(t, err, controllers.CodeForbidden)
+	}
+
+	owner := &auth.CustomClaims{Permissions: fake.UserPermissions("auth0|owner")}
+	err = c.AddMemberToBoardRole(owner, organizationId, boardId, (*roles)[0].Id, "auth0|guest")
+	expectCode(t, err, controllers.CodeValidationFailed)
+}
+
+func TestBoardRolesCanOnlyBeGivenByThoseWhoHaveTheirPermissions(t *testing.T) {
+	c, organizations, fake, organizationId, boardId := newTestOrganizationBoard(t)
+	fake.AddUser(models.User{UserID: "auth0|bob", Email: "bob@example.com"})
+	err := organizations.AddMember("auth0|bob", organizationId)
+	if err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	boardPerm := func(action string) string {
+		return fmt.Sprintf("org%s:board%s:%s", organizationId, boardId, action)
+	}
+	owner := &auth.CustomClaims{Permissions: fake.UserPermissions("auth0|owner")}
+	recruiter := &auth.CustomClaims{Permissions: []string{fmt.Sprintf("org%s:read", organizationId), boardPerm("read"), boardPerm("add_members")}}
+
+	viewer, err := c.CreateBoardRole(organizationId, boardId, "viewer", "", []string{boardPerm("read")})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	editor, err := c.CreateBoardRole(organizationId, boardId, "editor", "", []string{boardPerm("read"), boardPerm("create_panel")})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	roles, err := c.GetBoardRoles(organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to get roles: %v", err)
+	}
+	var ownerRoleId string
+	for _, role := range *roles {
+		if role.Name == boardPerm(boardOwnerRole) {
+			ownerRoleId = role.Id
+		}
+	}
+
+	err = c.AddMemberToBoardRole(recruiter, organizationId, boardId, ownerRoleId, "auth0|bob")
+	expectCode(t, err, controllers.CodeForbidden)
+	err = c.AddMemberToBoardRole(recruiter, organizationId, boardId, editor.Id, "auth0|bob")
+	expectCode(t, err, controllers.CodeForbidden)
+	if hasPermission(fake.UserPermissions("auth0|bob"), boardPerm("create_panel")) {
+		t.Fatal("expected bob not to get permissions the recruiter doesn't have")
+	}
+	err = c.AddMemberToBoardRole(recruiter, organizationId, boardId, viewer.Id, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to add member to role: %v", err)
+	}
+	err = c.AddMemberToBoardRole(owner, organizationId, boardId, ownerRoleId, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to add member to the owner role: %v", err)
+	}
+	if !hasPermission(fake.UserPermissions("auth0|bob"), boardPerm("delete")) {
+		t.Fatal("expected bob to own the board")
+	}
+}
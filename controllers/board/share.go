@@ -0,0 +1,121 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// shareTokenPrefix starts every share token so they can't be mistaken for access tokens
+const shareTokenPrefix = "sss_"
+
+var (
+	ErrInvalidShare          = controllers.NewError(controllers.CodeNotFound, "share link is invalid, expired or has been revoked")
+	ErrSharePasswordRequired = controllers.NewError(controllers.CodeUnauthorized, "this board needs a password to view")
+	ErrSharePasswordWrong    = controllers.NewError(controllers.CodeUnauthorized, "the password for this board is incorrect")
+)
+
+func (c *Controller) GetBoardShares(ctx context.Context, boardId string) (*[]models.BoardShare, error) {
+	shares, err := c.store.Shares.ListByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		shares[i].PasswordProtected = shares[i].PasswordHash != nil
+	}
+	return &shares, nil
+}
+
+// CreateBoardShare makes a link anyone can view the board with. It needs password to be viewed if
+// password isn't empty, and never expires if expiresAt is nil.
+func (c *Controller) CreateBoardShare(ctx context.Context, boardId string, createdBy string, password string, expiresAt *time.Time) (*models.NewBoardShare, error) {
+	board, err := c.GetBoardById(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	var passwordHash *string
+	if password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("password can't be used: %s", err.Error()))
+		}
+		hashedString := string(hashed)
+		passwordHash = &hashedString
+	}
+	token, tokenHash, err := controllers.GenerateToken(shareTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	share := models.BoardShare{
+		Id:           uuid.New(),
+		BoardId:      board.Id,
+		CreatedBy:    createdBy,
+		TokenHash:    tokenHash,
+		PasswordHash: passwordHash,
+	}
+	err = c.store.Shares.Create(ctx, share, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.store.Shares.Get(ctx, boardId, share.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	created.PasswordProtected = created.PasswordHash != nil
+	return &models.NewBoardShare{
+		BoardShare: *created,
+		Token:      token,
+		URL:        fmt.Sprintf("%s?token=%s", c.cfg.Shares.URL, url.QueryEscape(token)),
+	}, nil
+}
+
+func (c *Controller) RevokeBoardShare(ctx context.Context, boardId string, shareId string) error {
+	err := c.store.Shares.Revoke(ctx, boardId, shareId, time.Now())
+	return controllers.NotFound(err, "share", shareId)
+}
+
+// GetSharedBoard returns the board a share token is for, without anything that says who is on it:
+// no owner and no assignees. Each view is counted on the share.
+func (c *Controller) GetSharedBoard(ctx context.Context, token string, password string) (*models.CompleteBoard, error) {
+	if !strings.HasPrefix(token, shareTokenPrefix) {
+		return nil, ErrInvalidShare
+	}
+	share, err := c.store.Shares.GetByTokenHash(ctx, controllers.HashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidShare
+		}
+		return nil, err
+	}
+	if share.PasswordHash != nil {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+		err = bcrypt.CompareHashAndPassword([]byte(*share.PasswordHash), []byte(password))
+		if err != nil {
+			return nil, ErrSharePasswordWrong
+		}
+	}
+	board, err := c.getCompleteBoard(ctx, share.BoardId.String(), false)
+	if err != nil {
+		if errors.Is(err, controllers.ErrNotFound) {
+			return nil, ErrInvalidShare
+		}
+		return nil, err
+	}
+	board.OwnerId = ""
+	err = c.store.Shares.RecordAccess(ctx, share.Id.String(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
@@ -0,0 +1,79 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSharedBoardNeedsPasswordAndHidesPeople(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	createPanels(t, c, boardId, "A")
+	share, err := c.CreateBoardShare(ctx, boardId, "auth0|owner", "hunter22", nil)
+	if err != nil {
+		t.Fatalf("failed to create share: %v", err)
+	}
+	if !share.PasswordProtected {
+		t.Fatal("expected the share to be password protected")
+	}
+
+	_, err = c.GetSharedBoard(ctx, share.Token, "")
+	if !errors.Is(err, ErrSharePasswordRequired) {
+		t.Fatalf("expected a password to be required, got %v", err)
+	}
+	_, err = c.GetSharedBoard(ctx, share.Token, "hunter2")
+	if !errors.Is(err, ErrSharePasswordWrong) {
+		t.Fatalf("expected the password to be wrong, got %v", err)
+	}
+	board, err := c.GetSharedBoard(ctx, share.Token, "hunter22")
+	if err != nil {
+		t.Fatalf("failed to get shared board: %v", err)
+	}
+	if board.OwnerId != "" {
+		t.Fatalf("expected the owner to be left out, got %s", board.OwnerId)
+	}
+	if len(board.Panels) != 1 || board.Panels[0].Title != "A" {
+		t.Fatalf("expected panel A, got %+v", board.Panels)
+	}
+
+	shares, err := c.GetBoardShares(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get shares: %v", err)
+	}
+	if len(*shares) != 1 || (*shares)[0].AccessCount != 1 {
+		t.Fatalf("expected one share viewed once, got %+v", *shares)
+	}
+}
+
+func TestRevokedAndExpiredSharesStopWorking(t *testing.T) {
+	ctx := context.Background()
+	c, boardId := newTestBoard(t)
+	share, err := c.CreateBoardShare(ctx, boardId, "auth0|owner", "", nil)
+	if err != nil {
+		t.Fatalf("failed to create share: %v", err)
+	}
+	_, err = c.GetSharedBoard(ctx, share.Token, "")
+	if err != nil {
+		t.Fatalf("failed to get shared board: %v", err)
+	}
+	err = c.RevokeBoardShare(ctx, boardId, share.Id.String())
+	if err != nil {
+		t.Fatalf("failed to revoke share: %v", err)
+	}
+	_, err = c.GetSharedBoard(ctx, share.Token, "")
+	if !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("expected a revoked share to stop working, got %v", err)
+	}
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired, err := c.CreateBoardShare(ctx, boardId, "auth0|owner", "", &expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create share: %v", err)
+	}
+	_, err = c.GetSharedBoard(ctx, expired.Token, "")
+	if !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("expected an expired share to stop working, got %v", err)
+	}
+}
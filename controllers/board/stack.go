@@ -2,18 +2,19 @@ package board
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
 	"github.com/google/uuid"
 )
 
 func (c *Controller) GetStacksByPanelId(ctx context.Context, panelId string) (*[]models.Stack, error) {
-	stacks := make([]models.Stack, 0)
-	err := c.db.DB.SelectContext(ctx, &stacks, `
-		SELECT * FROM Stacks WHERE panel_id=$1 ORDER BY position ASC;
-	`, panelId)
+	stacks, err := c.store.Stacks.ListByPanel(ctx, panelId)
 	if err != nil {
 		return nil, err
 	}
@@ -21,21 +22,23 @@ func (c *Controller) GetStacksByPanelId(ctx context.Context, panelId string) (*[
 }
 
 func (c *Controller) CreateStack(ctx context.Context, title string, boardId string, panelId string) (*models.Stack, error) {
-	var nextPosition int
-	err := c.db.DB.GetContext(ctx, &nextPosition, `
-		SELECT COALESCE(MAX(position)+1, 0) AS next_position FROM Stacks where panel_id=$1;
-	`, panelId)
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	nextPosition, err := c.store.Stacks.NextPosition(ctx, panelId)
 	if err != nil {
 		return nil, err
 	}
-	stackId := uuid.New().String()
-	_, err = c.db.DB.ExecContext(ctx, `
-		INSERT INTO Stacks (id, title, position, panel_id) VALUES ($1, $2, $3, $4);
-	`, stackId, title, nextPosition, panelId)
+	panelUUID, err := uuid.Parse(panelId)
+	if err != nil {
+		return nil, controllers.NotFound(sql.ErrNoRows, "panel", panelId)
+	}
+	stackId := uuid.New()
+	err = c.store.Stacks.Create(ctx, models.Stack{Id: stackId, Title: title, Position: nextPosition, PanelId: panelUUID})
 	if err != nil {
 		return nil, err
 	}
-	stack, err := c.GetStackById(ctx, stackId)
+	stack, err := c.GetStackById(ctx, stackId.String())
 	if err != nil {
 		return nil, err
 	}
@@ -49,82 +52,99 @@ func (c *Controller) CreateStack(ctx context.Context, title string, boardId stri
 }
 
 func (c *Controller) GetStackById(ctx context.Context, stackId string) (*models.Stack, error) {
-	stack := models.Stack{}
-	err := c.db.DB.GetContext(ctx, &stack, `
-		SELECT * FROM Stacks WHERE id=$1;
-	`, stackId)
+	stack, err := c.store.Stacks.Get(ctx, stackId)
 	if err != nil {
-		return nil, err
+		return nil, controllers.NotFound(err, "stack", stackId)
 	}
-	return &stack, nil
+	return stack, nil
 }
 
-func (c *Controller) UpdateStackById(ctx context.Context, boardId string, panelId string, stackId string, title string, position *int) error {
-	stack, err := c.GetStackById(ctx, stackId)
+func (c *Controller) GetDeletedStackById(ctx context.Context, boardId string, stackId string) (*models.Stack, error) {
+	stack, err := c.store.Stacks.GetDeleted(ctx, boardId, stackId)
 	if err != nil {
-		return err
+		return nil, controllers.NotFound(err, "stack", stackId)
+	}
+	return stack, nil
+}
+
+func (c *Controller) UpdateStackById(ctx context.Context, boardId string, panelId string, stackId string, version int, title string, position *int) error {
+	patch := models.StackPatch{}
+	if title != "" {
+		patch.Title = models.NewOptional(title)
+	}
+	if position != nil {
+		patch.Position = models.NewOptional(*position)
 	}
-	if title == "" {
-		title = stack.Title
+	_, err := c.PatchStackById(ctx, boardId, panelId, stackId, version, patch)
+	return err
+}
+
+// PatchStackById only changes the fields that are set on the patch
+func (c *Controller) PatchStackById(ctx context.Context, boardId string, panelId string, stackId string, version int, patch models.StackPatch) (*models.Stack, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
 	}
-	if position == nil {
-		position = &stack.Position
+	stack, err := c.GetStackById(ctx, stackId)
+	if err != nil {
+		return nil, err
 	}
+	title := patch.Title.Or(stack.Title)
+	position := patch.Position.Or(stack.Position)
 
-	if *position != stack.Position {
-		var maxPosition int
-		err := c.db.DB.GetContext(ctx, &maxPosition, `
-			SELECT COALESCE(MAX(position), 0) AS max_position FROM Stacks where panel_id=$1;
-		`, panelId)
+	if position != stack.Position {
+		nextPosition, err := c.store.Stacks.NextPosition(ctx, panelId)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if *position > maxPosition || *position < 0 {
-			return errors.New("position is out of range")
+		if err := checkPosition(position, nextPosition); err != nil {
+			return nil, err
 		}
+	}
 
-		if *position > stack.Position {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Stacks SET position=position-1 WHERE panel_id=$1 AND position>$2 AND position<=$3;
-			`, panelId, stack.Position, *position)
-		} else {
-			_, err = c.db.DB.ExecContext(ctx, `
-				UPDATE Stacks SET position=position+1 WHERE panel_id=$1 AND position<$2 AND position>=$3;
-			`, panelId, stack.Position, *position)
-		}
+	err = claimVersion(ctx, c.store.Stacks, stackId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if position != stack.Position {
+		err = moveSiblings(ctx, c.store.Stacks, panelId, stack.Position, position)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Stacks SET title=$1, position=$2 WHERE id=$3;
-	`, title, *position, stackId)
+	stack.Title = title
+	stack.Position = position
+	err = c.store.Stacks.Update(ctx, *stack)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = c.UpdateBoardModifiedAt(ctx, boardId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return c.GetStackById(ctx, stackId)
 }
 
-func (c *Controller) DeleteStackById(ctx context.Context, boardId string, panelId string, stackId string) error {
+func (c *Controller) DeleteStackById(ctx context.Context, boardId string, panelId string, stackId string, version int) error {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return err
+	}
 	stack, err := c.GetStackById(ctx, stackId)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		DELETE FROM Stacks WHERE id=$1;
-	`, stackId)
+	err = claimVersion(ctx, c.store.Stacks, stackId, version)
+	if err != nil {
+		return err
+	}
+	// The stack keeps its position while in the trash so RestoreStackById can put it back there
+	err = c.store.Stacks.SoftDelete(ctx, stackId, time.Now().UTC())
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Stacks SET position=position-1 WHERE panel_id=$1 AND position>$2;
-	`, panelId, stack.Position)
+	err = c.store.Stacks.ShiftPositions(ctx, panelId, stack.Position+1, store.End, -1)
 	if err != nil {
 		return err
 	}
@@ -137,6 +157,48 @@ func (c *Controller) DeleteStackById(ctx context.Context, boardId string, panelI
 	return nil
 }
 
+func (c *Controller) RestoreStackById(ctx context.Context, boardId string, stackId string) (*models.Stack, error) {
+	if err := c.ensureBoardNotArchived(ctx, boardId); err != nil {
+		return nil, err
+	}
+	stack, err := c.GetDeletedStackById(ctx, boardId, stackId)
+	if err != nil {
+		return nil, err
+	}
+	panelId := stack.PanelId.String()
+	_, err = c.GetPanelById(ctx, panelId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrParentInTrash
+		}
+		return nil, err
+	}
+
+	nextPosition, err := c.store.Stacks.NextPosition(ctx, panelId)
+	if err != nil {
+		return nil, err
+	}
+	position := stack.Position
+	if position > nextPosition {
+		position = nextPosition
+	}
+	err = c.store.Stacks.ShiftPositions(ctx, panelId, position, store.End, 1)
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Stacks.Restore(ctx, stackId, position)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.UpdateBoardModifiedAt(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetStackById(ctx, stackId)
+}
+
 func (c *Controller) GetCompleteStackById(ctx context.Context, stackId string) (*models.CompleteStack, error) {
 
 	stack, err := c.GetStackById(ctx, stackId)
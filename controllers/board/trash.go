@@ -0,0 +1,64 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// ErrParentInTrash is returned when restoring something whose panel or stack is still in the trash
+var ErrParentInTrash = controllers.NewError(controllers.CodeParentInTrash, "the item's parent is in the trash and must be restored first")
+
+func (c *Controller) GetDeletedBoardsInOrg(ctx context.Context, orgId string) (*[]models.Board, error) {
+	boards, err := c.store.Boards.ListDeletedByOrganization(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	return &boards, nil
+}
+
+// GetBoardTrash lists the panels, stacks and cards that were deleted directly from the board.
+// Children of a deleted panel or stack aren't listed since restoring the parent brings them back.
+func (c *Controller) GetBoardTrash(ctx context.Context, boardId string) (*models.BoardTrash, error) {
+	panels, err := c.store.Panels.ListDeletedByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	stacks, err := c.store.Stacks.ListDeletedByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := c.store.Cards.ListDeletedByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BoardTrash{Panels: panels, Stacks: stacks, Cards: cards}, nil
+}
+
+// PurgeTrash runs PurgeTrash every so often, see jobs.RegisterRecurring
+var PurgeTrash = jobs.Type[struct{}]{Name: "purge_trash"}
+
+// PurgeTrash permanently deletes anything that has been in the trash for longer than retention.
+// Boards keep their roles and permissions while in the trash so they can be restored, so those are
+// only deleted here. A board whose roles can't be deleted is logged and left for ReconcileAccess.
+func (c *Controller) PurgeTrash(ctx context.Context, retention time.Duration) error {
+	cutoff := time.Now().UTC().Add(-retention)
+	// Only the boards that were actually purged lose their access, not ones restored since
+	boards, err := c.store.Boards.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	for _, board := range boards {
+		err = auth.DeleteAccess(ctx, fmt.Sprintf("org%s:board%s:", board.OrganizationId, board.Id))
+		if err != nil {
+			log.Printf("Failed to delete access for board %s: %v", board.Id, err)
+		}
+	}
+	return nil
+}
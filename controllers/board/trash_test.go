@@ -0,0 +1,55 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPurgingBoardDeletesItsAccess(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId, boardId := newTestOrganizationBoard(t)
+	boardPrefix := fmt.Sprintf("org%s:board%s:", organizationId, boardId)
+	hasBoardAccess := func() bool {
+		for _, role := range fake.Roles() {
+			if strings.HasPrefix(role.Name, boardPrefix) {
+				return true
+			}
+		}
+		for _, permission := range fake.Permissions() {
+			if strings.HasPrefix(permission, boardPrefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	board, err := c.GetBoardById(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get board: %v", err)
+	}
+	err = c.DeleteBoardById(ctx, boardId, board.Version)
+	if err != nil {
+		t.Fatalf("failed to delete board: %v", err)
+	}
+	err = c.PurgeTrash(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to purge trash: %v", err)
+	}
+	if !hasBoardAccess() {
+		t.Fatal("expected the board to keep its roles while it can still be restored")
+	}
+
+	err = c.PurgeTrash(ctx, -time.Second)
+	if err != nil {
+		t.Fatalf("failed to purge trash: %v", err)
+	}
+	if hasBoardAccess() {
+		t.Fatalf("expected the board's roles and permissions to be deleted, got %v and %v", fake.Roles(), fake.Permissions())
+	}
+	if !hasPermission(fake.Permissions(), fmt.Sprintf("org%s:read", organizationId)) {
+		t.Fatal("expected the organization to keep its permissions")
+	}
+}
@@ -0,0 +1,26 @@
+package board
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+// ErrVersionMismatch is returned when a board, panel, stack or card was changed by someone else
+// after the client last fetched it
+var ErrVersionMismatch = controllers.NewError(controllers.CodeVersionMismatch, "version does not match the current version")
+
+// claimVersion bumps the row's version if it still matches the one the client last saw. It is done
+// before anything else is written so that when two people change the same item at once, only one
+// of them goes ahead and the other gets ErrVersionMismatch without having changed anything.
+func claimVersion(ctx context.Context, repository store.Versioned, id string, version int) error {
+	claimed, err := repository.ClaimVersion(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrVersionMismatch
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package credential
+
+import (
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/patrickmn/go-cache"
+)
+
+type Controller struct {
+	cfg   *config.Config
+	store *store.Store
+	// userPermissions keeps what Auth0 says each user can do, so personal access tokens don't
+	// call the management API on every request
+	userPermissions *cache.Cache
+}
+
+func NewController(cfg *config.Config, store *store.Store) *Controller {
+	return &Controller{
+		cfg:             cfg,
+		store:           store,
+		userPermissions: cache.New(5*time.Minute, 10*time.Minute),
+	}
+}
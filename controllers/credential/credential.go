@@ -0,0 +1,176 @@
+package credential
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/google/uuid"
+)
+
+// APIKeySubjectPrefix starts the subject of requests made with an API key, since they aren't made
+// by a user
+const APIKeySubjectPrefix = "apikey|"
+
+var errInvalidToken = errors.New("token is invalid, revoked or expired")
+
+func (c *Controller) CreatePersonalAccessToken(ctx context.Context, userId string, name string, permissions []string, expiresAt *time.Time) (*models.NewPersonalAccessToken, error) {
+	token, tokenHash, err := controllers.GenerateToken(auth.PersonalAccessTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	pat := models.PersonalAccessToken{
+		Id:          uuid.New(),
+		UserId:      userId,
+		Name:        name,
+		TokenHash:   tokenHash,
+		Permissions: models.PermissionList(permissions),
+	}
+	err = c.store.Credentials.CreatePersonalAccessToken(ctx, pat, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.store.Credentials.GetPersonalAccessToken(ctx, userId, pat.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	return &models.NewPersonalAccessToken{PersonalAccessToken: *created, Token: token}, nil
+}
+
+func (c *Controller) GetPersonalAccessTokens(ctx context.Context, userId string) (*[]models.PersonalAccessToken, error) {
+	tokens, err := c.store.Credentials.ListPersonalAccessTokens(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+func (c *Controller) DeletePersonalAccessToken(ctx context.Context, userId string, tokenId string) error {
+	err := c.store.Credentials.DeletePersonalAccessToken(ctx, userId, tokenId)
+	return controllers.NotFound(err, "personal access token", tokenId)
+}
+
+func (c *Controller) CreateAPIKey(ctx context.Context, organizationId string, createdBy string, name string, permissions []string, expiresAt *time.Time) (*models.NewAPIKey, error) {
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	token, tokenHash, err := controllers.GenerateToken(auth.APIKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	key := models.APIKey{
+		Id:             uuid.New(),
+		OrganizationId: organization.Id,
+		CreatedBy:      createdBy,
+		Name:           name,
+		TokenHash:      tokenHash,
+		Permissions:    models.PermissionList(permissions),
+	}
+	err = c.store.Credentials.CreateAPIKey(ctx, key, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.store.Credentials.GetAPIKey(ctx, organizationId, key.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	return &models.NewAPIKey{APIKey: *created, Token: token}, nil
+}
+
+func (c *Controller) GetAPIKeys(ctx context.Context, organizationId string) (*[]models.APIKey, error) {
+	keys, err := c.store.Credentials.ListAPIKeys(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+func (c *Controller) DeleteAPIKey(ctx context.Context, organizationId string, keyId string) error {
+	err := c.store.Credentials.DeleteAPIKey(ctx, organizationId, keyId)
+	return controllers.NotFound(err, "API key", keyId)
+}
+
+// Authenticate turns a personal access token or API key into the same claims a JWT would have, so
+// handlers check permissions the same way no matter how the request was signed in. A personal
+// access token never has more permissions than its user currently does, and an API key never has
+// permissions outside of its organization.
+func (c *Controller) Authenticate(ctx context.Context, token string) (*validator.ValidatedClaims, error) {
+	tokenHash := controllers.HashToken(token)
+	switch {
+	case strings.HasPrefix(token, auth.PersonalAccessTokenPrefix):
+		pat, err := c.store.Credentials.GetPersonalAccessTokenByHash(ctx, tokenHash, time.Now())
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errInvalidToken
+			}
+			return nil, err
+		}
+		userPermissions, err := c.getUserPermissions(pat.UserId)
+		if err != nil {
+			return nil, err
+		}
+		permissions := make([]string, 0, len(pat.Permissions))
+		for _, permission := range pat.Permissions {
+			if userPermissions.Allows(permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+		// Not being able to record when it was used shouldn't fail the request
+		c.store.Credentials.MarkPersonalAccessTokenUsed(ctx, pat.Id.String(), time.Now())
+		return newClaims(pat.UserId, pat.Id.String(), permissions), nil
+
+	case strings.HasPrefix(token, auth.APIKeyPrefix):
+		key, err := c.store.Credentials.GetAPIKeyByHash(ctx, tokenHash, time.Now())
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errInvalidToken
+			}
+			return nil, err
+		}
+		orgPrefix := fmt.Sprintf("org%s:", key.OrganizationId)
+		permissions := make([]string, 0, len(key.Permissions))
+		for _, permission := range key.Permissions {
+			if strings.HasPrefix(permission, orgPrefix) {
+				permissions = append(permissions, permission)
+			}
+		}
+		c.store.Credentials.MarkAPIKeyUsed(ctx, key.Id.String(), time.Now())
+		return newClaims(APIKeySubjectPrefix+key.Id.String(), key.Id.String(), permissions), nil
+	}
+	return nil, errInvalidToken
+}
+
+func (c *Controller) getUserPermissions(userId string) (auth.PermissionSet, error) {
+	if cached, found := c.userPermissions.Get(userId); found {
+		return cached.(auth.PermissionSet), nil
+	}
+	userPermissions, err := auth.GetUserPermissions(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions for user %s: %w", userId, err)
+	}
+	permissionNames := make([]string, 0, len(*userPermissions))
+	for _, permission := range *userPermissions {
+		permissionNames = append(permissionNames, permission.Name)
+	}
+	permissions := auth.NewPermissionSet(permissionNames)
+	c.userPermissions.SetDefault(userId, permissions)
+	return permissions, nil
+}
+
+func newClaims(subject string, tokenId string, permissions []string) *validator.ValidatedClaims {
+	return &validator.ValidatedClaims{
+		RegisteredClaims: validator.RegisteredClaims{Subject: subject},
+		CustomClaims: &auth.CustomClaims{
+			Permissions: permissions,
+			TokenId:     tokenId,
+		},
+	}
+}
@@ -0,0 +1,137 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+// newTestOrganization returns a controller on an in-memory store with an organization owned by
+// auth0|owner
+func newTestOrganization(t *testing.T) (*Controller, *store.Store, string) {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+
+	ctx := context.Background()
+	s := store.NewMemory()
+	organizations := organization.NewController(cfg, s)
+	org, err := organizations.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := org.Id.String()
+	err = organizations.ProvisionOrganization(ctx, "auth0|owner", organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+	return NewController(cfg, s), s, organizationId
+}
+
+func expectCode(t *testing.T, err error, code controllers.ErrorCode) {
+	t.Helper()
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != code {
+		t.Fatalf("expected %s error, got %v", code, err)
+	}
+}
+
+func TestPersonalAccessTokensOnlyHaveTheirUsersPermissions(t *testing.T) {
+	ctx := context.Background()
+	c, _, organizationId := newTestOrganization(t)
+	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
+
+	created, err := c.CreatePersonalAccessToken(ctx, "auth0|owner", "script", []string{readOrgPerm, "org1:read"}, nil)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	claims, err := c.Authenticate(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("failed to authenticate with token: %v", err)
+	}
+	customClaims := claims.CustomClaims.(*auth.CustomClaims)
+	if claims.RegisteredClaims.Subject != "auth0|owner" || customClaims.TokenId != created.Id.String() {
+		t.Fatalf("expected the token to act as its user, got %+v", claims)
+	}
+	if !reflect.DeepEqual(customClaims.Permissions, []string{readOrgPerm}) {
+		t.Fatalf("expected only the permissions the user has, got %v", customClaims.Permissions)
+	}
+
+	tokens, err := c.GetPersonalAccessTokens(ctx, "auth0|owner")
+	if err != nil || len(*tokens) != 1 || (*tokens)[0].LastUsedAt == nil {
+		t.Fatalf("expected one token that has been used, got %+v, %v", tokens, err)
+	}
+
+	err = c.DeletePersonalAccessToken(ctx, "auth0|bob", created.Id.String())
+	expectCode(t, err, controllers.CodeNotFound)
+	err = c.DeletePersonalAccessToken(ctx, "auth0|owner", created.Id.String())
+	if err != nil {
+		t.Fatalf("failed to delete token: %v", err)
+	}
+	_, err = c.Authenticate(ctx, created.Token)
+	if !errors.Is(err, errInvalidToken) {
+		t.Fatalf("expected a deleted token to stop working, got %v", err)
+	}
+}
+
+func TestAPIKeysOnlyHaveTheirOrganizationsPermissions(t *testing.T) {
+	ctx := context.Background()
+	c, s, organizationId := newTestOrganization(t)
+	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
+
+	created, err := c.CreateAPIKey(ctx, organizationId, "auth0|owner", "ci", []string{readOrgPerm, "org1:read"}, nil)
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	claims, err := c.Authenticate(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("failed to authenticate with API key: %v", err)
+	}
+	if claims.RegisteredClaims.Subject != APIKeySubjectPrefix+created.Id.String() {
+		t.Fatalf("expected the key not to act as a user, got %s", claims.RegisteredClaims.Subject)
+	}
+	if permissions := claims.CustomClaims.(*auth.CustomClaims).Permissions; !reflect.DeepEqual(permissions, []string{readOrgPerm}) {
+		t.Fatalf("expected only the organization's permissions, got %v", permissions)
+	}
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired, err := c.CreateAPIKey(ctx, organizationId, "auth0|owner", "old", []string{readOrgPerm}, &expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	_, err = c.Authenticate(ctx, expired.Token)
+	if !errors.Is(err, errInvalidToken) {
+		t.Fatalf("expected an expired key not to work, got %v", err)
+	}
+
+	err = c.DeleteAPIKey(ctx, "00000000-0000-0000-0000-000000000000", created.Id.String())
+	expectCode(t, err, controllers.CodeNotFound)
+	_, err = c.CreateAPIKey(ctx, "00000000-0000-0000-0000-000000000000", "auth0|owner", "ci", []string{readOrgPerm}, nil)
+	expectCode(t, err, controllers.CodeNotFound)
+
+	err = s.Organizations.Delete(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to delete organization: %v", err)
+	}
+	keys, err := c.GetAPIKeys(ctx, organizationId)
+	if err != nil || len(*keys) != 0 {
+		t.Fatalf("expected the organization's keys to be deleted with it, got %+v, %v", keys, err)
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrorCode tells clients what kind of error happened without them having to parse the message
+type ErrorCode string
+
+const (
+	CodeBadRequest           ErrorCode = "bad_request"
+	CodeValidationFailed     ErrorCode = "validation_failed"
+	CodeUnauthorized         ErrorCode = "unauthorized"
+	CodeForbidden            ErrorCode = "forbidden"
+	CodeNotFound             ErrorCode = "not_found"
+	CodeMethodNotAllowed     ErrorCode = "method_not_allowed"
+	CodeConflict             ErrorCode = "conflict"
+	CodeBoardArchived        ErrorCode = "board_archived"
+	CodeParentInTrash        ErrorCode = "parent_in_trash"
+	CodeVersionMismatch      ErrorCode = "version_mismatch"
+	CodePreconditionRequired ErrorCode = "precondition_required"
+	CodeInternal             ErrorCode = "internal_error"
+)
+
+// Error is a domain error that the routers turn into a response with the right status code
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Details interface{}
+	Err     error
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any Error with the same code, so errors.Is(err, ErrNotFound) works no matter
+// what was not found
+func (e *Error) Is(target error) bool {
+	var targetError *Error
+	if !errors.As(target, &targetError) {
+		return false
+	}
+	return targetError.Code == e.Code
+}
+
+var (
+	ErrNotFound  = NewError(CodeNotFound, "not found")
+	ErrForbidden = NewError(CodeForbidden, "forbidden")
+)
+
+// NotFound turns sql.ErrNoRows into a not found error naming what was being looked up. Other
+// errors are returned as they are.
+func NotFound(err error, resource string, id string) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return &Error{
+		Code:    CodeNotFound,
+		Message: fmt.Sprintf("no %s found with id %s", resource, id),
+		Err:     err,
+	}
+}
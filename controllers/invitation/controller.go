@@ -0,0 +1,30 @@
+package invitation
+
+import (
+	"context"
+	"net"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+type Controller struct {
+	cfg           *config.Config
+	store         *store.Store
+	sender        mail.Sender
+	organizations *organization.Controller
+	// lookupTXT finds a domain's TXT records when verifying it
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+func NewController(cfg *config.Config, store *store.Store, sender mail.Sender) *Controller {
+	return &Controller{
+		cfg:           cfg,
+		store:         store,
+		sender:        sender,
+		organizations: organization.NewController(cfg, store),
+		lookupTXT:     net.DefaultResolver.LookupTXT,
+	}
+}
@@ -0,0 +1,189 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// verificationPrefix starts the TXT record a domain needs before users can join by it
+const verificationPrefix = "syncspace-verification="
+
+// publicMailDomains are mail providers anyone can sign up with, so an organization allowing one
+// would let everyone in no matter who owns the domain
+var publicMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "outlook.com": true, "hotmail.com": true, "live.com": true,
+	"msn.com": true, "yahoo.com": true, "ymail.com": true, "icloud.com": true, "me.com": true,
+	"mac.com": true, "aol.com": true, "proton.me": true, "protonmail.com": true, "gmx.com": true,
+	"gmx.de": true, "gmx.net": true, "web.de": true, "mail.com": true, "yandex.com": true,
+	"yandex.ru": true, "zoho.com": true, "fastmail.com": true, "hey.com": true, "tutanota.com": true,
+	"qq.com": true, "163.com": true,
+}
+
+func (c *Controller) GetDomains(ctx context.Context, organizationId string) (*[]models.OrganizationDomain, error) {
+	domains, err := c.store.Domains.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &domains, nil
+}
+
+// AddDomain lets users with a verified email at domain join the organization once the domain is
+// verified with VerifyDomain. Adding a domain that is already allowed changes the role it gives.
+func (c *Controller) AddDomain(ctx context.Context, organizationId string, createdBy string, domain string, roleId *string) (*models.OrganizationDomain, error) {
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	normalized := normalizeDomain(domain)
+	if !domainPattern.MatchString(normalized) {
+		return nil, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("%q is not a valid domain", domain))
+	}
+	if publicMailDomains[normalized] {
+		return nil, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("%s is a public mail provider, so anyone could join with it", normalized))
+	}
+	if roleId != nil {
+		err = checkRole(organizationId, *roleId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	token, _, err := controllers.GenerateToken("")
+	if err != nil {
+		return nil, err
+	}
+	err = c.store.Domains.Add(ctx, models.OrganizationDomain{
+		OrganizationId:     organization.Id,
+		Domain:             normalized,
+		RoleId:             roleId,
+		VerificationRecord: verificationPrefix + token,
+		CreatedBy:          createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.getDomain(ctx, organizationId, normalized)
+}
+
+// VerifyDomain checks that domain has the TXT record it was given when it was added, which only
+// whoever controls its DNS can publish. Users can only join by domain after this.
+func (c *Controller) VerifyDomain(ctx context.Context, organizationId string, domain string) (*models.OrganizationDomain, error) {
+	allowed, err := c.getDomain(ctx, organizationId, normalizeDomain(domain))
+	if err != nil {
+		return nil, err
+	}
+	if allowed.VerifiedAt != nil {
+		return allowed, nil
+	}
+	records, err := c.lookupTXT(ctx, allowed.Domain)
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) && dnsError.IsNotFound {
+		records, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up TXT records of %s: %w", allowed.Domain, err)
+	}
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == allowed.VerificationRecord {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("%s has no TXT record %q yet", allowed.Domain, allowed.VerificationRecord))
+	}
+	err = c.store.Domains.Verify(ctx, organizationId, allowed.Domain)
+	if err != nil {
+		return nil, controllers.NotFound(err, "domain", domain)
+	}
+	return c.getDomain(ctx, organizationId, allowed.Domain)
+}
+
+func (c *Controller) getDomain(ctx context.Context, organizationId string, domain string) (*models.OrganizationDomain, error) {
+	domains, err := c.store.Domains.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	for _, allowed := range domains {
+		if allowed.Domain == domain {
+			return &allowed, nil
+		}
+	}
+	return nil, controllers.NotFound(sql.ErrNoRows, "domain", domain)
+}
+
+func (c *Controller) RemoveDomain(ctx context.Context, organizationId string, domain string) error {
+	err := c.store.Domains.Remove(ctx, organizationId, normalizeDomain(domain))
+	return controllers.NotFound(err, "domain", domain)
+}
+
+// JoinByDomain adds the user to every organization that allows and has verified their email's
+// domain. It is meant to be called after signing in, so it does nothing for unverified emails, and
+// doesn't add users back to organizations they joined this way before and have since left.
+func (c *Controller) JoinByDomain(ctx context.Context, userId string) (*[]models.Organization, error) {
+	joined := make([]models.Organization, 0)
+	invitee, err := user.GetUser(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userId, err)
+	}
+	_, domain, found := strings.Cut(invitee.Email, "@")
+	if !invitee.EmailVerified || !found {
+		return &joined, nil
+	}
+	allowed, err := c.store.Domains.ListByDomain(ctx, normalizeDomain(domain))
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return &joined, nil
+	}
+	organizationIds, err := memberships(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, allowedDomain := range allowed {
+		organizationId := allowedDomain.OrganizationId.String()
+		if allowedDomain.VerifiedAt == nil || organizationIds[organizationId] {
+			continue
+		}
+		hasJoined, err := c.store.Domains.HasJoined(ctx, organizationId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if hasJoined {
+			continue
+		}
+		organization, err := c.store.Organizations.Get(ctx, organizationId)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = c.join(userId, organizationId, allowedDomain.RoleId)
+		if err != nil {
+			return nil, err
+		}
+		err = c.store.Domains.RecordJoin(ctx, organizationId, userId)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, *organization)
+	}
+	return &joined, nil
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+}
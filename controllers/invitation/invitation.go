@@ -0,0 +1,275 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+// tokenPrefix and linkTokenPrefix start invitation and invite link tokens, so they can't be
+// mistaken for access tokens or each other
+const (
+	tokenPrefix     = "ssi_"
+	linkTokenPrefix = "ssl_"
+)
+
+var ErrInvalidInvitation = controllers.NewError(controllers.CodeNotFound, "invitation is invalid, expired or has already been answered")
+
+func (c *Controller) GetPendingInvitations(ctx context.Context, organizationId string) (*[]models.Invitation, error) {
+	invitations, err := c.store.Invitations.ListPending(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &invitations, nil
+}
+
+// CreateInvitation emails an invitation to join the organization. roleId, if given, must be one of
+// the organization's roles and is assigned on top of the member role when the invitation is
+// accepted.
+func (c *Controller) CreateInvitation(ctx context.Context, organizationId string, invitedBy string, email string, roleId *string) (*models.Invitation, error) {
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	address, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if roleId != nil {
+		err = checkRole(organizationId, *roleId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	hasPending, err := c.store.Invitations.HasPending(ctx, organizationId, address)
+	if err != nil {
+		return nil, err
+	}
+	if hasPending {
+		return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("%s already has a pending invitation to organization %s, resend it instead", address, organizationId))
+	}
+
+	token, tokenHash, err := controllers.GenerateToken(tokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	invitation := models.Invitation{
+		Id:             uuid.New(),
+		OrganizationId: organization.Id,
+		Email:          address,
+		RoleId:         roleId,
+		InvitedBy:      invitedBy,
+		TokenHash:      tokenHash,
+	}
+	err = c.store.Invitations.Create(ctx, invitation, time.Now().Add(c.cfg.Invitations.TTL))
+	if err != nil {
+		return nil, err
+	}
+	err = c.send(ctx, organization, address, token)
+	if err != nil {
+		// nobody can accept an invitation they never got, so don't leave it blocking a retry
+		c.store.Invitations.Respond(ctx, invitation.Id.String(), models.InvitationRevoked, time.Now())
+		return nil, err
+	}
+	return c.store.Invitations.Get(ctx, organizationId, invitation.Id.String())
+}
+
+// ResendInvitation emails a pending invitation again with a new token and expiry. The token from
+// any earlier email stops working.
+func (c *Controller) ResendInvitation(ctx context.Context, organizationId string, invitationId string) (*models.Invitation, error) {
+	invitation, err := c.getPendingInvitation(ctx, organizationId, invitationId)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	token, tokenHash, err := controllers.GenerateToken(tokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	renewed, err := c.store.Invitations.Renew(ctx, invitationId, tokenHash, time.Now().Add(c.cfg.Invitations.TTL))
+	if err != nil {
+		return nil, err
+	}
+	if !renewed {
+		return nil, notPending(invitationId)
+	}
+	err = c.send(ctx, organization, invitation.Email, token)
+	if err != nil {
+		return nil, err
+	}
+	return c.store.Invitations.Get(ctx, organizationId, invitationId)
+}
+
+func (c *Controller) RevokeInvitation(ctx context.Context, organizationId string, invitationId string) error {
+	_, err := c.getPendingInvitation(ctx, organizationId, invitationId)
+	if err != nil {
+		return err
+	}
+	revoked, err := c.store.Invitations.Respond(ctx, invitationId, models.InvitationRevoked, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return notPending(invitationId)
+	}
+	return nil
+}
+
+// AcceptInvitation adds the user to the organization the invitation is for, along with its role.
+// The user's email has to be the one the invitation was sent to.
+func (c *Controller) AcceptInvitation(ctx context.Context, token string, userId string) (*models.Invitation, error) {
+	invitation, err := c.getInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	invitee, err := user.GetUser(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userId, err)
+	}
+	if !strings.EqualFold(invitee.Email, invitation.Email) {
+		return nil, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("invitation %s was sent to a different email address", invitation.Id))
+	}
+
+	// adding roles twice does nothing, so if two requests accept at once it doesn't matter which
+	// one gets to mark the invitation as accepted
+	organizationId := invitation.OrganizationId.String()
+	err = c.join(userId, organizationId, invitation.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.store.Invitations.Respond(ctx, invitation.Id.String(), models.InvitationAccepted, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return c.store.Invitations.Get(ctx, organizationId, invitation.Id.String())
+}
+
+// DeclineInvitation only needs the token, so invitees can decline without signing up
+func (c *Controller) DeclineInvitation(ctx context.Context, token string) error {
+	invitation, err := c.getInvitationByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	declined, err := c.store.Invitations.Respond(ctx, invitation.Id.String(), models.InvitationDeclined, time.Now())
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
+
+func (c *Controller) getPendingInvitation(ctx context.Context, organizationId string, invitationId string) (*models.Invitation, error) {
+	invitation, err := c.store.Invitations.Get(ctx, organizationId, invitationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "invitation", invitationId)
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, notPending(invitationId)
+	}
+	return invitation, nil
+}
+
+func (c *Controller) getInvitationByToken(ctx context.Context, token string) (*models.Invitation, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, ErrInvalidInvitation
+	}
+	invitation, err := c.store.Invitations.GetPendingByTokenHash(ctx, controllers.HashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (c *Controller) send(ctx context.Context, organization *models.Organization, email string, token string) error {
+	link := fmt.Sprintf("%s?token=%s", c.cfg.Invitations.AcceptURL, url.QueryEscape(token))
+	message := mail.Message{
+		To:      email,
+		Subject: fmt.Sprintf("You've been invited to join %s on SyncSpace", organization.Name),
+		Body: fmt.Sprintf(
+			"You've been invited to join %s on SyncSpace.\n\nOpen this link to accept or decline:\n%s\n\nThe invitation expires in %s.\n",
+			organization.Name, link, c.cfg.Invitations.TTL,
+		),
+	}
+	err := c.sender.Send(ctx, message)
+	if err != nil {
+		return fmt.Errorf("failed to send invitation to %s: %w", email, err)
+	}
+	return nil
+}
+
+// join adds the user to the organization's member role, and to roleId if it is set
+func (c *Controller) join(userId string, organizationId string, roleId *string) error {
+	err := c.organizations.AddMember(userId, organizationId)
+	if err != nil {
+		return fmt.Errorf("failed to add user %s to organization %s: %w", userId, organizationId, err)
+	}
+	if roleId != nil {
+		err = auth.AddUserToRole(userId, *roleId)
+		if err != nil {
+			return fmt.Errorf("failed to add user %s to role %s: %w", userId, *roleId, err)
+		}
+	}
+	return nil
+}
+
+// memberships returns the ids of the organizations the user is a member of. Guests aren't
+// members, so they can still join through an invitation
+func memberships(userId string) (map[string]bool, error) {
+	roles, err := auth.GetUserRoles(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles of user %s: %w", userId, err)
+	}
+	organizationIds := make(map[string]bool)
+	for _, role := range *roles {
+		if !strings.HasPrefix(role.Name, "org") {
+			continue
+		}
+		organizationId, roleName, found := strings.Cut(strings.TrimPrefix(role.Name, "org"), ":")
+		if found && roleName == "member" {
+			organizationIds[organizationId] = true
+		}
+	}
+	return organizationIds, nil
+}
+
+// checkRole makes sure roleId belongs to the organization, so an invitation can't hand out roles
+// somewhere else
+func checkRole(organizationId string, roleId string) error {
+	role, err := auth.GetRoleById(roleId)
+	if err != nil || !strings.HasPrefix(role.Name, fmt.Sprintf("org%s:", organizationId)) {
+		return controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("role %s is not a role in organization %s", roleId, organizationId))
+	}
+	return nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	address, err := netmail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("%q is not a valid email address", email))
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+func notPending(invitationId string) error {
+	return controllers.NewError(controllers.CodeConflict, fmt.Sprintf("invitation %s is no longer pending", invitationId))
+}
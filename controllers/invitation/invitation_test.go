@@ -0,0 +1,307 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+// outbox keeps every message sent instead of sending it
+type outbox struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+func (o *outbox) Send(ctx context.Context, message mail.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+var tokenPattern = regexp.MustCompile(tokenPrefix + `[A-Za-z0-9_-]+`)
+
+// lastToken returns the token from the last message sent
+func (o *outbox) lastToken(t *testing.T) string {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.messages) == 0 {
+		t.Fatal("expected an invitation to be sent")
+	}
+	token := tokenPattern.FindString(o.messages[len(o.messages)-1].Body)
+	if token == "" {
+		t.Fatal("expected the invitation to contain a token")
+	}
+	return token
+}
+
+func newTestOrganization(t *testing.T) (*Controller, *outbox, *auth0test.Server, string) {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+	fake.AddUser(models.User{UserID: "auth0|bob", Email: "bob@example.com"})
+
+	ctx := context.Background()
+	s := store.NewMemory()
+	organizations := organization.NewController(cfg, s)
+	org, err := organizations.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	err = organizations.ProvisionOrganization(ctx, "auth0|owner", org.Id.String())
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+	sent := &outbox{}
+	return NewController(cfg, s, sent), sent, fake, org.Id.String()
+}
+
+func expectCode(t *testing.T, err error, code controllers.ErrorCode) {
+	t.Helper()
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != code {
+		t.Fatalf("expected %s error, got %v", code, err)
+	}
+}
+
+func TestAcceptInvitationAddsMember(t *testing.T) {
+	ctx := context.Background()
+	c, sent, fake, organizationId := newTestOrganization(t)
+
+	invitation, err := c.CreateInvitation(ctx, organizationId, "auth0|owner", " Bob@Example.com ", nil)
+	if err != nil {
+		t.Fatalf("failed to create invitation: %v", err)
+	}
+	if invitation.Email != "bob@example.com" || invitation.Status != models.InvitationPending {
+		t.Fatalf("expected a pending invitation for bob@example.com, got %+v", invitation)
+	}
+	_, err = c.CreateInvitation(ctx, organizationId, "auth0|owner", "bob@example.com", nil)
+	expectCode(t, err, controllers.CodeConflict)
+
+	token := sent.lastToken(t)
+	_, err = c.AcceptInvitation(ctx, token, "auth0|owner")
+	expectCode(t, err, controllers.CodeForbidden)
+
+	accepted, err := c.AcceptInvitation(ctx, token, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to accept invitation: %v", err)
+	}
+	if accepted.Status != models.InvitationAccepted {
+		t.Fatalf("expected invitation to be accepted, got %s", accepted.Status)
+	}
+	readPerm := fmt.Sprintf("org%s:read", organizationId)
+	hasRead := false
+	for _, permission := range fake.UserPermissions("auth0|bob") {
+		hasRead = hasRead || permission == readPerm
+	}
+	if !hasRead {
+		t.Fatalf("expected bob to be able to read the organization, got %v", fake.UserPermissions("auth0|bob"))
+	}
+
+	_, err = c.AcceptInvitation(ctx, token, "auth0|bob")
+	if !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("expected the token to only work once, got %v", err)
+	}
+}
+
+func TestResendInvitationReplacesToken(t *testing.T) {
+	ctx := context.Background()
+	c, sent, _, organizationId := newTestOrganization(t)
+
+	invitation, err := c.CreateInvitation(ctx, organizationId, "auth0|owner", "carol@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to create invitation: %v", err)
+	}
+	oldToken := sent.lastToken(t)
+	_, err = c.ResendInvitation(ctx, organizationId, invitation.Id.String())
+	if err != nil {
+		t.Fatalf("failed to resend invitation: %v", err)
+	}
+	newToken := sent.lastToken(t)
+
+	err = c.DeclineInvitation(ctx, oldToken)
+	if !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("expected the old token to stop working, got %v", err)
+	}
+	err = c.DeclineInvitation(ctx, newToken)
+	if err != nil {
+		t.Fatalf("failed to decline invitation: %v", err)
+	}
+	pending, err := c.GetPendingInvitations(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to get invitations: %v", err)
+	}
+	if len(*pending) != 0 {
+		t.Fatalf("expected no pending invitations, got %d", len(*pending))
+	}
+	err = c.RevokeInvitation(ctx, organizationId, invitation.Id.String())
+	expectCode(t, err, controllers.CodeConflict)
+}
+
+func TestInvitationRoleMustBelongToOrganization(t *testing.T) {
+	ctx := context.Background()
+	c, _, _, organizationId := newTestOrganization(t)
+
+	otherRole, err := auth.CreateRole("orgother:member", "Member of another organization")
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	_, err = c.CreateInvitation(ctx, organizationId, "auth0|owner", "dave@example.com", &otherRole.Id)
+	expectCode(t, err, controllers.CodeValidationFailed)
+}
+
+func TestInviteLinkStopsAtMaxUses(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId := newTestOrganization(t)
+	fake.AddUser(models.User{UserID: "auth0|carol", Email: "carol@example.com"})
+
+	maxUses := 1
+	link, err := c.CreateInviteLink(ctx, organizationId, "auth0|owner", nil, &maxUses, nil)
+	if err != nil {
+		t.Fatalf("failed to create invite link: %v", err)
+	}
+	_, err = c.JoinWithInviteLink(ctx, link.Token, "auth0|owner")
+	expectCode(t, err, controllers.CodeConflict)
+
+	_, err = c.JoinWithInviteLink(ctx, link.Token, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to join with invite link: %v", err)
+	}
+	_, err = c.JoinWithInviteLink(ctx, link.Token, "auth0|carol")
+	if !errors.Is(err, ErrInvalidInviteLink) {
+		t.Fatalf("expected the link to be used up, got %v", err)
+	}
+
+	links, err := c.GetInviteLinks(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to get invite links: %v", err)
+	}
+	if len(*links) != 1 || (*links)[0].Uses != 1 {
+		t.Fatalf("expected one link used once, got %+v", *links)
+	}
+}
+
+func TestJoinByDomainOnlyJoinsOnce(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId := newTestOrganization(t)
+	fake.AddUser(models.User{UserID: "auth0|erin", Email: "erin@Team.example.com", EmailVerified: true})
+	fake.AddUser(models.User{UserID: "auth0|mallory", Email: "mallory@team.example.com"})
+
+	_, err := c.AddDomain(ctx, organizationId, "auth0|owner", "not a domain", nil)
+	expectCode(t, err, controllers.CodeValidationFailed)
+	verifyDomain(t, c, organizationId, "@Team.Example.com")
+
+	joined, err := c.JoinByDomain(ctx, "auth0|mallory")
+	if err != nil {
+		t.Fatalf("failed to join by domain: %v", err)
+	}
+	if len(*joined) != 0 {
+		t.Fatalf("expected an unverified email not to join, got %+v", *joined)
+	}
+
+	joined, err = c.JoinByDomain(ctx, "auth0|erin")
+	if err != nil {
+		t.Fatalf("failed to join by domain: %v", err)
+	}
+	if len(*joined) != 1 || (*joined)[0].Id.String() != organizationId {
+		t.Fatalf("expected to join the organization, got %+v", *joined)
+	}
+
+	err = c.organizations.RemoveMember("auth0|erin", organizationId)
+	if err != nil {
+		t.Fatalf("failed to remove member: %v", err)
+	}
+	joined, err = c.JoinByDomain(ctx, "auth0|erin")
+	if err != nil {
+		t.Fatalf("failed to join by domain: %v", err)
+	}
+	if len(*joined) != 0 {
+		t.Fatalf("expected a member who left not to be added back, got %+v", *joined)
+	}
+}
+
+// verifyDomain adds domain to the organization and verifies it against a fake DNS with its record
+func verifyDomain(t *testing.T, c *Controller, organizationId string, domain string) {
+	t.Helper()
+	ctx := context.Background()
+	added, err := c.AddDomain(ctx, organizationId, "auth0|owner", domain, nil)
+	if err != nil {
+		t.Fatalf("failed to add domain: %v", err)
+	}
+	c.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		return []string{"v=spf1 -all", added.VerificationRecord}, nil
+	}
+	verified, err := c.VerifyDomain(ctx, organizationId, domain)
+	if err != nil {
+		t.Fatalf("failed to verify domain: %v", err)
+	}
+	if verified.VerifiedAt == nil {
+		t.Fatalf("expected the domain to be verified, got %+v", verified)
+	}
+}
+
+func TestJoinByDomainNeedsAVerifiedDomain(t *testing.T) {
+	ctx := context.Background()
+	c, _, fake, organizationId := newTestOrganization(t)
+	fake.AddUser(models.User{UserID: "auth0|erin", Email: "erin@example.com", EmailVerified: true})
+
+	_, err := c.AddDomain(ctx, organizationId, "auth0|owner", "Gmail.com", nil)
+	expectCode(t, err, controllers.CodeValidationFailed)
+
+	added, err := c.AddDomain(ctx, organizationId, "auth0|owner", "example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to add domain: %v", err)
+	}
+	if added.VerifiedAt != nil || added.VerificationRecord == "" {
+		t.Fatalf("expected a new domain to need verifying, got %+v", added)
+	}
+	joined, err := c.JoinByDomain(ctx, "auth0|erin")
+	if err != nil {
+		t.Fatalf("failed to join by domain: %v", err)
+	}
+	if len(*joined) != 0 {
+		t.Fatalf("expected an unverified domain not to let anyone join, got %+v", *joined)
+	}
+
+	c.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	_, err = c.VerifyDomain(ctx, organizationId, "example.com")
+	expectCode(t, err, controllers.CodeConflict)
+	c.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		return []string{"syncspace-verification=someone-elses-token"}, nil
+	}
+	_, err = c.VerifyDomain(ctx, organizationId, "example.com")
+	expectCode(t, err, controllers.CodeConflict)
+	_, err = c.VerifyDomain(ctx, organizationId, "other.example.com")
+	expectCode(t, err, controllers.CodeNotFound)
+
+	verifyDomain(t, c, organizationId, "example.com")
+	joined, err = c.JoinByDomain(ctx, "auth0|erin")
+	if err != nil {
+		t.Fatalf("failed to join by domain: %v", err)
+	}
+	if len(*joined) != 1 {
+		t.Fatalf("expected a verified domain to let the user join, got %+v", *joined)
+	}
+}
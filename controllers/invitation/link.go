@@ -0,0 +1,114 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidInviteLink = controllers.NewError(controllers.CodeNotFound, "invite link is invalid, expired or has been used up")
+
+func (c *Controller) GetInviteLinks(ctx context.Context, organizationId string) (*[]models.InviteLink, error) {
+	links, err := c.store.InviteLinks.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &links, nil
+}
+
+// CreateInviteLink makes a link anyone can use to join the organization. maxUses and expiresAt are
+// unlimited if nil, and roleId works the same as for an invitation.
+func (c *Controller) CreateInviteLink(ctx context.Context, organizationId string, createdBy string, roleId *string, maxUses *int, expiresAt *time.Time) (*models.NewInviteLink, error) {
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	if maxUses != nil && *maxUses < 1 {
+		return nil, controllers.NewError(controllers.CodeValidationFailed, "max_uses must be at least 1")
+	}
+	if roleId != nil {
+		err = checkRole(organizationId, *roleId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	token, tokenHash, err := controllers.GenerateToken(linkTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	link := models.InviteLink{
+		Id:             uuid.New(),
+		OrganizationId: organization.Id,
+		RoleId:         roleId,
+		CreatedBy:      createdBy,
+		TokenHash:      tokenHash,
+		MaxUses:        maxUses,
+	}
+	err = c.store.InviteLinks.Create(ctx, link, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.store.InviteLinks.Get(ctx, organizationId, link.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	return &models.NewInviteLink{
+		InviteLink: *created,
+		Token:      token,
+		URL:        fmt.Sprintf("%s?token=%s", c.cfg.Invitations.JoinURL, url.QueryEscape(token)),
+	}, nil
+}
+
+func (c *Controller) DeleteInviteLink(ctx context.Context, organizationId string, linkId string) error {
+	err := c.store.InviteLinks.Delete(ctx, organizationId, linkId)
+	return controllers.NotFound(err, "invite link", linkId)
+}
+
+// JoinWithInviteLink adds the user to the organization the link is for. Users who are already
+// members don't use the link up.
+func (c *Controller) JoinWithInviteLink(ctx context.Context, token string, userId string) (*models.Organization, error) {
+	if !strings.HasPrefix(token, linkTokenPrefix) {
+		return nil, ErrInvalidInviteLink
+	}
+	link, err := c.store.InviteLinks.GetByTokenHash(ctx, controllers.HashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidInviteLink
+		}
+		return nil, err
+	}
+	organizationId := link.OrganizationId.String()
+	organizationIds, err := memberships(userId)
+	if err != nil {
+		return nil, err
+	}
+	if organizationIds[organizationId] {
+		return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("user %s is already a member of organization %s", userId, organizationId))
+	}
+	used, err := c.store.InviteLinks.Use(ctx, link.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidInviteLink
+	}
+	// the use is already counted, so if this fails the link has one less use left, which is better
+	// than letting more people in than it allows
+	err = c.join(userId, organizationId, link.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	return organization, nil
+}
@@ -0,0 +1,131 @@
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/google/uuid"
+)
+
+// accessOwner is the organization, and board if there is one, that a role or permission name like
+// org<id>:board<id>:update belongs to
+type accessOwner struct {
+	organizationId string
+	boardId        string
+}
+
+func (owner accessOwner) prefix() string {
+	if owner.boardId == "" {
+		return fmt.Sprintf("org%s:", owner.organizationId)
+	}
+	return fmt.Sprintf("org%s:board%s:", owner.organizationId, owner.boardId)
+}
+
+// parseAccessOwner finds who a role or permission name belongs to. Names that aren't about a
+// single organization, like org<id>:board*:read, aren't ours to clean up.
+func parseAccessOwner(name string) (accessOwner, bool) {
+	rest, found := strings.CutPrefix(name, "org")
+	if !found {
+		return accessOwner{}, false
+	}
+	organizationId, rest, found := strings.Cut(rest, ":")
+	if !found || !isUuid(organizationId) {
+		return accessOwner{}, false
+	}
+	owner := accessOwner{organizationId: organizationId}
+	rest, found = strings.CutPrefix(rest, "board")
+	if !found {
+		return owner, true
+	}
+	boardId, _, found := strings.Cut(rest, ":")
+	if found && isUuid(boardId) {
+		owner.boardId = boardId
+	}
+	return owner, true
+}
+
+// ReconcileAccess runs ReconcileAccess every so often, see jobs.RegisterRecurring
+var ReconcileAccess = jobs.Type[struct{}]{Name: "reconcile_access"}
+
+// ReconcileAccess deletes the roles and permissions left behind by organizations and boards that
+// no longer exist, because cleaning up after them failed or they were deleted without it, like
+// when their owner's account is deleted. Boards in the trash still exist, since they can be
+// restored.
+func (c *Controller) ReconcileAccess(ctx context.Context) error {
+	roles, err := auth.GetRoles(nil)
+	if err != nil {
+		return err
+	}
+	permissions, err := auth.GetPermissions()
+	if err != nil {
+		return err
+	}
+	owners := make(map[accessOwner]bool)
+	for _, role := range *roles {
+		if owner, ok := parseAccessOwner(role.Name); ok {
+			owners[owner] = true
+		}
+	}
+	for _, permission := range *permissions {
+		if owner, ok := parseAccessOwner(permission.Name); ok {
+			owners[owner] = true
+		}
+	}
+
+	orphans := make(map[accessOwner]bool)
+	for owner := range owners {
+		exists, err := c.accessOwnerExists(ctx, owner)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			orphans[owner] = true
+		}
+	}
+	for orphan := range orphans {
+		// Deleting an organization's access deletes its boards' too
+		if orphan.boardId != "" && orphans[accessOwner{organizationId: orphan.organizationId}] {
+			continue
+		}
+		err = auth.DeleteAccess(ctx, orphan.prefix())
+		if err != nil {
+			return err
+		}
+		log.Printf("Deleted roles and permissions starting with %s", orphan.prefix())
+	}
+	return nil
+}
+
+func (c *Controller) accessOwnerExists(ctx context.Context, owner accessOwner) (bool, error) {
+	if owner.boardId == "" {
+		_, err := c.store.Organizations.Get(ctx, owner.organizationId)
+		return foundRow(err)
+	}
+	board, err := c.store.Boards.Get(ctx, owner.boardId)
+	if errors.Is(err, sql.ErrNoRows) {
+		board, err = c.store.Boards.GetDeleted(ctx, owner.organizationId, owner.boardId)
+	}
+	exists, err := foundRow(err)
+	if err != nil || !exists {
+		return false, err
+	}
+	return board.OrganizationId.String() == owner.organizationId, nil
+}
+
+func isUuid(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func foundRow(err error) (bool, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
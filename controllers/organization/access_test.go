@@ -0,0 +1,136 @@
+package organization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/google/uuid"
+)
+
+func hasName(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func roleNames(fake *auth0test.Server) []string {
+	names := make([]string, 0)
+	for _, role := range fake.Roles() {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func TestReconcileAccessDeletesOrphans(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+
+	s := store.NewMemory()
+	c := NewController(cfg, s)
+	org, err := c.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := org.Id.String()
+	err = c.ProvisionOrganization(ctx, "auth0|owner", organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+	board := models.Board{Id: uuid.New(), OrganizationId: org.Id, OwnerId: "auth0|owner", Title: "Board"}
+	err = s.Boards.Create(ctx, board)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+
+	boardPrefix := fmt.Sprintf("org%s:board%s:", organizationId, board.Id)
+	deletedBoardPrefix := fmt.Sprintf("org%s:board%s:", organizationId, uuid.New())
+	deletedOrgPrefix := fmt.Sprintf("org%s:", uuid.New())
+	for _, name := range []string{boardPrefix + "member", deletedBoardPrefix + "member", deletedOrgPrefix + "owner", "admin"} {
+		_, err = auth.CreateRole(name, "")
+		if err != nil {
+			t.Fatalf("failed to create role %s: %v", name, err)
+		}
+	}
+	err = auth.CreatePermissions([]auth.Permission{{Name: deletedBoardPrefix + "read"}, {Name: deletedOrgPrefix + "read"}, {Name: deletedOrgPrefix + "board*:read"}})
+	if err != nil {
+		t.Fatalf("failed to create permissions: %v", err)
+	}
+
+	err = c.ReconcileAccess(ctx)
+	if err != nil {
+		t.Fatalf("failed to reconcile access: %v", err)
+	}
+	roles, permissions := roleNames(fake), fake.Permissions()
+	for _, prefix := range []string{deletedBoardPrefix, deletedOrgPrefix} {
+		if hasName(roles, prefix) || hasName(permissions, prefix) {
+			t.Fatalf("expected everything starting with %s to be deleted, got %v and %v", prefix, roles, permissions)
+		}
+	}
+	for _, prefix := range []string{fmt.Sprintf("org%s:owner", organizationId), boardPrefix, "admin"} {
+		if !hasName(roles, prefix) {
+			t.Fatalf("expected %s to be kept, got %v", prefix, roles)
+		}
+	}
+	if !hasName(permissions, fmt.Sprintf("org%s:read", organizationId)) {
+		t.Fatalf("expected the organization's permissions to be kept, got %v", permissions)
+	}
+}
+
+func TestDeleteOrganizationDeletesItsAccess(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+
+	s := store.NewMemory()
+	c := NewController(cfg, s)
+	org, err := c.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := org.Id.String()
+	err = c.ProvisionOrganization(ctx, "auth0|owner", organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+
+	err = c.DeleteOrganizationById(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to delete organization: %v", err)
+	}
+	worker := jobs.NewWorker(s.Jobs, 1, time.Second, time.Minute)
+	jobs.RegisterHandlers(worker, mail.NewLogSender(""))
+	err = worker.RunPending(ctx)
+	if err != nil {
+		t.Fatalf("failed to run jobs: %v", err)
+	}
+	prefix := fmt.Sprintf("org%s:", organizationId)
+	if hasName(roleNames(fake), prefix) || hasName(fake.Permissions(), prefix) {
+		t.Fatalf("expected the organization's roles and permissions to be deleted, got %v and %v", fake.Roles(), fake.Permissions())
+	}
+}
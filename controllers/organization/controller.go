@@ -2,17 +2,17 @@ package organization
 
 import (
 	"github.com/Sync-Space-49/syncspace-server/config"
-	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
 )
 
 type Controller struct {
-	cfg *config.Config
-	db  *db.DB
+	cfg   *config.Config
+	store *store.Store
 }
 
-func NewController(cfg *config.Config, db *db.DB) *Controller {
+func NewController(cfg *config.Config, store *store.Store) *Controller {
 	return &Controller{
-		cfg: cfg,
-		db:  db,
+		cfg:   cfg,
+		store: store,
 	}
 }
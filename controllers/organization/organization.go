@@ -3,45 +3,54 @@ package organization
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/provisioning"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
 	"github.com/Sync-Space-49/syncspace-server/models"
 	"github.com/google/uuid"
 )
 
+// CreateOrganization inserts the organization as provisioning and queues a ProvisionOrganization
+// job to set up its roles
 func (c *Controller) CreateOrganization(ctx context.Context, userId string, title string, description *string, aiEnabled bool) (*models.Organization, error) {
-	var query string
-	if description == nil {
-		query = `INSERT INTO Organizations (id, owner_id, name, ai_enabled) VALUES ($1, $2, $3, $5);`
-	} else {
-		query = `INSERT INTO Organizations (id, owner_id, name, description, ai_enabled) VALUES ($1, $2, $3, $4, $5);`
+	organization := models.Organization{
+		Id:                 uuid.New(),
+		OwnerId:            userId,
+		Name:               title,
+		AiEnabled:          aiEnabled,
+		ProvisioningStatus: models.ProvisioningInProgress,
 	}
-	orgID := uuid.New().String()
-	_, err := c.db.DB.ExecContext(ctx, query, orgID, userId, title, description, aiEnabled)
+	if description != nil {
+		organization.Description = *description
+	}
+	err := c.store.Organizations.Create(ctx, organization)
 	if err != nil {
 		return nil, err
 	}
-	org, err := c.GetOrganizationById(ctx, orgID)
+	payload := ProvisionOrganizationPayload{OrganizationId: organization.Id.String(), OwnerId: userId}
+	_, err = ProvisionOrganization.Enqueue(ctx, jobs.NewQueue(c.store.Jobs), payload)
+	if err != nil {
+		// ResumeProvisioning still picks the organization up later
+		log.Printf("Failed to queue provisioning organization %s: %v", organization.Id, err)
+	}
+	org, err := c.GetOrganizationById(ctx, organization.Id.String())
 	if err != nil {
 		return nil, err
 	}
 	return org, nil
 }
 
-func (c *Controller) InitializeOrganization(ownerId string, organizationId string) error {
-	ownerRoleName := fmt.Sprintf("org%s:owner", organizationId)
-	ownerRoleDescription := fmt.Sprintf("Owner of organization with the id: %s", organizationId)
-	ownerRole, err := auth.CreateRole(ownerRoleName, ownerRoleDescription)
-	if err != nil {
-		return err
-	}
-	memberRoleName := fmt.Sprintf("org%s:member", organizationId)
-	memberRoleDescription := fmt.Sprintf("Member of organization with the id: %s", organizationId)
-	memberRole, err := auth.CreateRole(memberRoleName, memberRoleDescription)
-	if err != nil {
-		return err
-	}
+// ProvisionOrganization sets up the roles and permissions of an organization made by
+// CreateOrganization and marks it ready. If that fails, whatever was set up is undone and the
+// organization is deleted.
+func (c *Controller) ProvisionOrganization(ctx context.Context, ownerId string, organizationId string) error {
+	return c.organizationProvisioning(ownerId, organizationId).Run(ctx)
+}
 
+func (c *Controller) organizationProvisioning(ownerId string, organizationId string) *provisioning.Saga {
 	readPerm := auth.Permission{
 		Name:        fmt.Sprintf("org%s:read", organizationId),
 		Description: fmt.Sprintf("Allows you to read the contents of the organization with id %s", organizationId),
@@ -96,49 +105,32 @@ func (c *Controller) InitializeOrganization(ownerId string, organizationId strin
 	}
 	orgOwnerPermissions := append(orgMemberPermissions, deletePerm, updatePerm, addMembersPerm, removeMembersPerm, boardsAdminPerm, createRolesPerm, editRolesPerm, deleteRolesPerm, addRolesPerm, removeRolesPerm)
 
-	// Because the owner role has all permissions, we only need to call CreatePermissions once
-	err = auth.CreatePermissions(orgOwnerPermissions)
-	if err != nil {
-		return fmt.Errorf("failed to create permissions for organization: %w", err)
-	}
-
 	orgMemberPermissionNames := make([]string, len(orgMemberPermissions))
 	for i, permission := range orgMemberPermissions {
 		orgMemberPermissionNames[i] = permission.Name
 	}
-	err = auth.AddPermissionsToRole(memberRole.Id, orgMemberPermissionNames)
-	if err != nil {
-		return fmt.Errorf("failed to add permissions to member role: %w", err)
-	}
-	orgOwnerPermissionNames := make([]string, len(orgOwnerPermissions))
-	for i, permission := range orgOwnerPermissions {
-		orgOwnerPermissionNames[i] = permission.Name
-	}
-	err = auth.AddPermissionsToRole(ownerRole.Id, orgOwnerPermissionNames)
-	if err != nil {
-		return fmt.Errorf("failed to add permissions to owner role: %w", err)
-	}
-
-	err = auth.AddUserToRole(ownerId, ownerRole.Id)
-	if err != nil {
-		return fmt.Errorf("failed to add owner role to user: %w", err)
-	}
-	err = auth.AddUserToRole(ownerId, memberRole.Id)
-	if err != nil {
-		return fmt.Errorf("failed to add member role to user: %w", err)
-	}
-	return nil
+	access := provisioning.Access{
+		OwnerId: ownerId,
+		OwnerRole: auth.Role{
+			Name:        fmt.Sprintf("org%s:owner", organizationId),
+			Description: fmt.Sprintf("Owner of organization with the id: %s", organizationId),
+		},
+		MemberRole: auth.Role{
+			Name:        fmt.Sprintf("org%s:member", organizationId),
+			Description: fmt.Sprintf("Member of organization with the id: %s", organizationId),
+		},
+		Permissions:           orgOwnerPermissions,
+		MemberPermissionNames: orgMemberPermissionNames,
+	}
+	return provisioning.New(c.store.Provisioning, c.store.Organizations, organizationId, access.Steps())
 }
 
 func (c *Controller) GetOrganizationById(ctx context.Context, organizationId string) (*models.Organization, error) {
-	var organization models.Organization
-	err := c.db.DB.GetContext(ctx, &organization, `
-		SELECT * FROM Organizations WHERE id=$1;
-	`, organizationId)
+	organization, err := c.store.Organizations.Get(ctx, organizationId)
 	if err != nil {
-		return nil, err
+		return nil, controllers.NotFound(err, "organization", organizationId)
 	}
-	return &organization, nil
+	return organization, nil
 }
 
 func (c *Controller) UpdateOrganizationById(ctx context.Context, organizationId string, title string, description string, ai_enabled bool) error {
@@ -152,23 +144,40 @@ func (c *Controller) UpdateOrganizationById(ctx context.Context, organizationId
 	if description == "" {
 		description = org.Description
 	}
+	org.Name = title
+	org.Description = description
+	org.AiEnabled = ai_enabled
+	return c.store.Organizations.Update(ctx, *org)
+}
 
-	_, err = c.db.DB.ExecContext(ctx, `
-		UPDATE Organizations SET name=$1, description=$2, ai_enabled=$3 WHERE id=$4;
-	`, title, description, ai_enabled, organizationId)
+// PatchOrganizationById only changes the fields that are set on the patch
+func (c *Controller) PatchOrganizationById(ctx context.Context, organizationId string, patch models.OrganizationPatch) (*models.Organization, error) {
+	org, err := c.GetOrganizationById(ctx, organizationId)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	org.Name = patch.Name.Or(org.Name)
+	org.Description = patch.Description.Or(org.Description)
+	org.AiEnabled = patch.AiEnabled.Or(org.AiEnabled)
+	err = c.store.Organizations.Update(ctx, *org)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetOrganizationById(ctx, organizationId)
 }
 
+// DeleteOrganizationById deletes the organization and queues a job to delete the roles and
+// permissions of it and its boards. The organization is gone once its row is, so if the job can't
+// be queued the failure is only logged and ReconcileAccess deletes what is left later.
 func (c *Controller) DeleteOrganizationById(ctx context.Context, organizationId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		DELETE FROM Organizations WHERE id=$1;
-	`, organizationId)
+	err := c.store.Organizations.Delete(ctx, organizationId)
 	if err != nil {
 		return err
 	}
+	_, err = jobs.DeleteAccess.Enqueue(ctx, jobs.NewQueue(c.store.Jobs), jobs.DeleteAccessPayload{Prefix: fmt.Sprintf("org%s:", organizationId)})
+	if err != nil {
+		log.Printf("Failed to queue deleting access for organization %s: %v", organizationId, err)
+	}
 	return nil
 }
 
@@ -185,9 +194,63 @@ func (c *Controller) AddMember(userId string, organizationId string) error {
 	if err != nil {
 		return err
 	}
+	// A member can already reach every board a guest could, so joining ends the guest membership
+	guestRole, err := findGuestRole(organizationId)
+	if err != nil {
+		return err
+	}
+	if guestRole != nil {
+		err = auth.RemoveUserFromRole(userId, guestRole.Id)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// IsMember reports whether the user holds the organization's member role. Guests only hold roles
+// on single boards, so they are not members
+func IsMember(userId string, organizationId string) (bool, error) {
+	roles, err := auth.GetUserRoles(userId)
+	if err != nil {
+		return false, err
+	}
+	orgMemberRoleName := fmt.Sprintf("org%s:member", organizationId)
+	for _, role := range *roles {
+		if role.Name == orgMemberRoleName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GuestRole returns the organization's guest role, creating it the first time a guest is added.
+// It grants nothing itself, access comes from the board roles the guest is given
+func GuestRole(organizationId string) (*auth.Role, error) {
+	guestRole, err := findGuestRole(organizationId)
+	if err != nil || guestRole != nil {
+		return guestRole, err
+	}
+	guestRoleName := fmt.Sprintf("org%s:guest", organizationId)
+	guestRoleDescription := fmt.Sprintf("Guest of organization with the id: %s", organizationId)
+	return auth.CreateRole(guestRoleName, guestRoleDescription)
+}
+
+// findGuestRole returns nil if no guest has been added to the organization yet
+func findGuestRole(organizationId string) (*auth.Role, error) {
+	guestRoleName := fmt.Sprintf("org%s:guest", organizationId)
+	roles, err := auth.GetRoles(&guestRoleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range *roles {
+		if role.Name == guestRoleName {
+			return &role, nil
+		}
+	}
+	return nil, nil
+}
+
 func (c *Controller) RemoveMember(userId string, organizationId string) error {
 	orgRolePrefix := fmt.Sprintf("org%s:", organizationId)
 	orgRoles, err := auth.GetRoles(&orgRolePrefix)
@@ -0,0 +1,99 @@
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// ProvisionOrganization sets up the roles and permissions of an organization made by
+// CreateOrganization in the background, so creating one doesn't wait on Auth0
+var ProvisionOrganization = jobs.Type[ProvisionOrganizationPayload]{Name: "provision_organization"}
+
+type ProvisionOrganizationPayload struct {
+	OrganizationId string `json:"organization_id"`
+	OwnerId        string `json:"owner_id"`
+}
+
+// ResumeProvisioning runs ResumeProvisioning every so often, see jobs.RegisterRecurring
+var ResumeProvisioning = jobs.Type[struct{}]{Name: "resume_organization_provisioning"}
+
+// ProvisionQueuedOrganization is the handler for ProvisionOrganization jobs. Organizations that are
+// already ready or gone, like when ResumeProvisioning got to them first, are left alone.
+// Provisioning that fails deletes the organization, so it isn't tried again.
+func (c *Controller) ProvisionQueuedOrganization(ctx context.Context, payload ProvisionOrganizationPayload) error {
+	organization, err := c.store.Organizations.Get(ctx, payload.OrganizationId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if organization.ProvisioningStatus != models.ProvisioningInProgress {
+		return nil
+	}
+	err = c.ProvisionOrganization(ctx, payload.OwnerId, payload.OrganizationId)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	return nil
+}
+
+// ResumeProvisioning finishes provisioning organizations created more than staleAfter ago that
+// never became ready, and undoes it again for ones where it failed and couldn't be undone. Each
+// organization that fails is logged and tried again next time.
+//
+// Organizations a provision_organization job is still going to work on are left to the job, and
+// each organization is claimed first, so no two servers resume the same one.
+func (c *Controller) ResumeProvisioning(ctx context.Context, staleAfter time.Duration) error {
+	cutoff := time.Now().UTC().Add(-staleAfter)
+	queued, err := ProvisionOrganization.Active(ctx, jobs.NewQueue(c.store.Jobs))
+	if err != nil {
+		return err
+	}
+	busy := make(map[string]bool)
+	for _, payload := range queued {
+		busy[payload.OrganizationId] = true
+	}
+	unfinished, err := c.store.Organizations.ListByProvisioningStatus(ctx, models.ProvisioningInProgress, cutoff)
+	if err != nil {
+		return err
+	}
+	for _, organization := range unfinished {
+		claimed, err := c.claimProvisioning(ctx, busy, organization, cutoff)
+		if err == nil && claimed {
+			err = c.organizationProvisioning(organization.OwnerId, organization.Id.String()).Run(ctx)
+		}
+		if err != nil {
+			log.Printf("Failed to resume provisioning organization %s: %v", organization.Id, err)
+		}
+	}
+	failed, err := c.store.Organizations.ListByProvisioningStatus(ctx, models.ProvisioningFailed, cutoff)
+	if err != nil {
+		return err
+	}
+	for _, organization := range failed {
+		claimed, err := c.claimProvisioning(ctx, busy, organization, cutoff)
+		if err == nil && claimed {
+			err = c.organizationProvisioning(organization.OwnerId, organization.Id.String()).Compensate(ctx)
+		}
+		if err != nil {
+			log.Printf("Failed to undo provisioning organization %s: %v", organization.Id, err)
+		}
+	}
+	return nil
+}
+
+// claimProvisioning takes over the organization's provisioning unless its job is still queued or
+// running, it has changed since it was listed or someone else claimed it since cutoff
+func (c *Controller) claimProvisioning(ctx context.Context, busy map[string]bool, organization models.Organization, cutoff time.Time) (bool, error) {
+	if busy[organization.Id.String()] {
+		return false, nil
+	}
+	return c.store.Organizations.ClaimProvisioning(ctx, organization.Id.String(), organization.ProvisioningStatus, cutoff)
+}
@@ -0,0 +1,78 @@
+package provisioning
+
+import "github.com/Sync-Space-49/syncspace-server/auth"
+
+// Access is what an organization or board gets in Auth0: its permissions, an owner role with all
+// of them and a member role with some, and its owner holding both roles
+type Access struct {
+	OwnerId               string
+	OwnerRole             auth.Role
+	MemberRole            auth.Role
+	Permissions           []auth.Permission
+	MemberPermissionNames []string
+}
+
+// Steps returns the steps that set up the access. Roles are looked up by name before being
+// created, so a role made by a step that failed before it was recorded gets reused.
+func (a Access) Steps() []Step {
+	ownerPermissionNames := make([]string, len(a.Permissions))
+	for i, permission := range a.Permissions {
+		ownerPermissionNames[i] = permission.Name
+	}
+	return []Step{
+		roleStep("create_owner_role", a.OwnerRole),
+		roleStep("create_member_role", a.MemberRole),
+		{
+			Name: "create_permissions",
+			Do: func(results map[string]string) (string, error) {
+				return "", auth.CreatePermissions(a.Permissions)
+			},
+			Undo: func(result string) error {
+				return auth.DeletePermissions(a.Permissions)
+			},
+		},
+		// Deleting the roles takes their permissions and users with them, so the rest don't need
+		// undoing
+		{
+			Name: "add_member_role_permissions",
+			Do: func(results map[string]string) (string, error) {
+				return "", auth.AddPermissionsToRole(results["create_member_role"], a.MemberPermissionNames)
+			},
+		},
+		{
+			Name: "add_owner_role_permissions",
+			Do: func(results map[string]string) (string, error) {
+				return "", auth.AddPermissionsToRole(results["create_owner_role"], ownerPermissionNames)
+			},
+		},
+		{
+			Name: "add_owner_role_to_owner",
+			Do: func(results map[string]string) (string, error) {
+				return "", auth.AddUserToRole(a.OwnerId, results["create_owner_role"])
+			},
+		},
+		{
+			Name: "add_member_role_to_owner",
+			Do: func(results map[string]string) (string, error) {
+				return "", auth.AddUserToRole(a.OwnerId, results["create_member_role"])
+			},
+		},
+	}
+}
+
+// roleStep creates role and results in its id
+func roleStep(name string, role auth.Role) Step {
+	return Step{
+		Name: name,
+		Do: func(results map[string]string) (string, error) {
+			created, err := auth.EnsureRole(role.Name, role.Description)
+			if err != nil {
+				return "", err
+			}
+			return created.Id, nil
+		},
+		Undo: func(result string) error {
+			return auth.DeleteRoleNamed(role.Name)
+		},
+	}
+}
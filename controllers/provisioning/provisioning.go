@@ -0,0 +1,104 @@
+// Package provisioning sets up the Auth0 roles and permissions of new organizations and boards as a
+// saga. Each step is recorded once it finishes, so provisioning that failed or was cut short by a
+// restart can be picked up where it stopped, or undone along with the row it was for.
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+// Step is one part of provisioning. Do is given the results of the steps before it, and returns
+// its own, like the id of a role it created. Undo is given that result, or "" if the step never
+// finished, since it might have changed something before failing. Undo can be nil when undoing an
+// earlier step takes care of it.
+//
+// Both have to be safe to repeat, since the server can stop between a step's call to Auth0 and
+// recording that it finished.
+type Step struct {
+	Name string
+	Do   func(results map[string]string) (string, error)
+	Undo func(result string) error
+}
+
+// Saga provisions the organization or board with id resourceId in resources
+type Saga struct {
+	steps      store.ProvisioningRepository
+	resources  store.Provisioned
+	resourceId string
+	plan       []Step
+}
+
+func New(steps store.ProvisioningRepository, resources store.Provisioned, resourceId string, plan []Step) *Saga {
+	return &Saga{
+		steps:      steps,
+		resources:  resources,
+		resourceId: resourceId,
+		plan:       plan,
+	}
+}
+
+// Run does every step that hasn't finished yet and marks the resource ready. If a step fails, the
+// whole saga is compensated and the step's error is returned.
+func (s *Saga) Run(ctx context.Context) error {
+	results, err := s.steps.CompletedSteps(ctx, s.resourceId)
+	if err != nil {
+		return err
+	}
+	for _, step := range s.plan {
+		if _, done := results[step.Name]; done {
+			continue
+		}
+		result, err := step.Do(results)
+		if err == nil {
+			err = s.steps.CompleteStep(ctx, s.resourceId, step.Name, result)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to %s: %w", step.Name, err)
+			compensateErr := s.Compensate(ctx)
+			if compensateErr != nil {
+				return errors.Join(err, compensateErr)
+			}
+			return err
+		}
+		results[step.Name] = result
+	}
+	err = s.resources.SetProvisioningStatus(ctx, s.resourceId, models.ProvisioningReady)
+	if err != nil {
+		return err
+	}
+	return s.steps.Clear(ctx, s.resourceId)
+}
+
+// Compensate undoes every step, last first, and then deletes the resource. If a step can't be
+// undone the resource is marked failed instead, so compensating can be tried again later.
+func (s *Saga) Compensate(ctx context.Context) error {
+	results, err := s.steps.CompletedSteps(ctx, s.resourceId)
+	if err != nil {
+		return err
+	}
+	for i := len(s.plan) - 1; i >= 0; i-- {
+		step := s.plan[i]
+		if step.Undo != nil {
+			err = step.Undo(results[step.Name])
+			if err != nil {
+				err = fmt.Errorf("failed to undo %s: %w", step.Name, err)
+				statusErr := s.resources.SetProvisioningStatus(ctx, s.resourceId, models.ProvisioningFailed)
+				return errors.Join(err, statusErr)
+			}
+		}
+		err = s.steps.UndoStep(ctx, s.resourceId, step.Name)
+		if err != nil {
+			return err
+		}
+	}
+	err = s.resources.Delete(ctx, s.resourceId)
+	if err != nil {
+		return err
+	}
+	return s.steps.Clear(ctx, s.resourceId)
+}
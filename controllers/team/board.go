@@ -0,0 +1,143 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func (c *Controller) GetTeamBoards(ctx context.Context, organizationId string, teamId string) (*[]models.TeamBoard, error) {
+	_, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return nil, err
+	}
+	teamBoards, err := c.store.Teams.ListBoards(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+	return &teamBoards, nil
+}
+
+func (c *Controller) GetBoardTeams(ctx context.Context, boardId string) (*[]models.TeamBoard, error) {
+	teamBoards, err := c.store.Teams.ListByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &teamBoards, nil
+}
+
+// AddTeamToBoard gives the team's role the permissions of the board's member or owner role, so
+// everyone on the team, now or later, has them. Adding a team that is already on the board changes
+// its role. The caller with tokenCustomClaims must be allowed to give the board role, see
+// board.CheckCanGrantBoardRole.
+func (c *Controller) AddTeamToBoard(ctx context.Context, tokenCustomClaims *auth.CustomClaims, organizationId string, boardId string, teamId string, role string, addedBy string) (*models.TeamBoard, error) {
+	if role == "" {
+		role = models.TeamBoardMember
+	}
+	if role != models.TeamBoardMember && role != models.TeamBoardOwner {
+		return nil, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("role must be %s or %s", models.TeamBoardMember, models.TeamBoardOwner))
+	}
+	boardToAdd, err := c.store.Boards.Get(ctx, boardId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "board", boardId)
+	}
+	if boardToAdd.OrganizationId.String() != organizationId {
+		return nil, controllers.NotFound(sql.ErrNoRows, "board", boardId)
+	}
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	boardRoleName := fmt.Sprintf("org%s:board%s:%s", organizationId, boardId, role)
+	boardRoles, err := auth.GetRoles(&boardRoleName)
+	if err != nil {
+		return nil, err
+	}
+	if len(*boardRoles) == 0 {
+		return nil, fmt.Errorf("no %s role found for board %s", role, boardId)
+	}
+	boardPermissions, err := auth.GetRolePermissions((*boardRoles)[0].Id)
+	if err != nil {
+		return nil, err
+	}
+	err = board.CheckCanGrantBoardRole(tokenCustomClaims, organizationId, boardId, boardRoleName, *boardPermissions)
+	if err != nil {
+		return nil, err
+	}
+	err = removeBoardPermissions(team.RoleId, organizationId, boardId)
+	if err != nil {
+		return nil, err
+	}
+	permissionNames := make([]string, len(*boardPermissions))
+	for i, permission := range *boardPermissions {
+		permissionNames[i] = permission.Name
+	}
+	err = auth.AddPermissionsToRole(team.RoleId, permissionNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add board permissions to team %s: %w", teamId, err)
+	}
+
+	err = c.store.Teams.AddBoard(ctx, models.TeamBoard{
+		TeamId:  team.Id,
+		BoardId: boardToAdd.Id,
+		Role:    role,
+		AddedBy: addedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	teamBoards, err := c.store.Teams.ListByBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	for _, teamBoard := range teamBoards {
+		if teamBoard.TeamId == team.Id {
+			return &teamBoard, nil
+		}
+	}
+	return nil, controllers.NotFound(sql.ErrNoRows, "team", teamId)
+}
+
+// RemoveTeamFromBoard takes the board's permissions away from the team's role. Team members who
+// are also on the board themselves keep their own access.
+func (c *Controller) RemoveTeamFromBoard(ctx context.Context, organizationId string, boardId string, teamId string) error {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return err
+	}
+	err = c.store.Teams.RemoveBoard(ctx, teamId, boardId)
+	if err != nil {
+		return controllers.NotFound(err, "board", boardId)
+	}
+	return removeBoardPermissions(team.RoleId, organizationId, boardId)
+}
+
+// removeBoardPermissions takes every permission on the board away from the role
+func removeBoardPermissions(roleId string, organizationId string, boardId string) error {
+	permissions, err := auth.GetRolePermissions(roleId)
+	if err != nil {
+		return err
+	}
+	boardPermissionPrefix := fmt.Sprintf("org%s:board%s:", organizationId, boardId)
+	var permissionNames []string
+	for _, permission := range *permissions {
+		if strings.HasPrefix(permission.Name, boardPermissionPrefix) {
+			permissionNames = append(permissionNames, permission.Name)
+		}
+	}
+	if len(permissionNames) == 0 {
+		return nil
+	}
+	err = auth.RemovePermissionsFromRole(roleId, permissionNames)
+	if err != nil {
+		return fmt.Errorf("failed to remove board permissions from role %s: %w", roleId, err)
+	}
+	return nil
+}
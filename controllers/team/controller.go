@@ -0,0 +1,18 @@
+package team
+
+import (
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+type Controller struct {
+	cfg   *config.Config
+	store *store.Store
+}
+
+func NewController(cfg *config.Config, store *store.Store) *Controller {
+	return &Controller{
+		cfg:   cfg,
+		store: store,
+	}
+}
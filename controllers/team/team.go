@@ -0,0 +1,149 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/controllers/user"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) GetTeams(ctx context.Context, organizationId string) (*[]models.Team, error) {
+	teams, err := c.store.Teams.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &teams, nil
+}
+
+func (c *Controller) GetTeam(ctx context.Context, organizationId string, teamId string) (*models.Team, error) {
+	team, err := c.store.Teams.Get(ctx, organizationId, teamId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "team", teamId)
+	}
+	return team, nil
+}
+
+// CreateTeam makes the team along with the Auth0 role its members will hold
+func (c *Controller) CreateTeam(ctx context.Context, organizationId string, createdBy string, name string, description string) (*models.Team, error) {
+	org, err := c.store.Organizations.Get(ctx, organizationId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "organization", organizationId)
+	}
+	name, err = c.checkName(ctx, organizationId, "", name)
+	if err != nil {
+		return nil, err
+	}
+	teamId := uuid.New()
+	roleName := fmt.Sprintf("org%s:team%s", organizationId, teamId)
+	roleDescription := fmt.Sprintf("Members of team with the id: %s", teamId)
+	role, err := auth.CreateRole(roleName, roleDescription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role for team: %w", err)
+	}
+	team := models.Team{
+		Id:             teamId,
+		OrganizationId: org.Id,
+		Name:           name,
+		Description:    description,
+		RoleId:         role.Id,
+		CreatedBy:      createdBy,
+	}
+	err = c.store.Teams.Create(ctx, team)
+	if err != nil {
+		// Don't leave a role behind for a team that doesn't exist
+		auth.DeleteRole(role.Id)
+		return nil, err
+	}
+	return c.GetTeam(ctx, organizationId, teamId.String())
+}
+
+func (c *Controller) UpdateTeam(ctx context.Context, organizationId string, teamId string, name string, description string) (*models.Team, error) {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return nil, err
+	}
+	team.Name, err = c.checkName(ctx, organizationId, teamId, name)
+	if err != nil {
+		return nil, err
+	}
+	team.Description = description
+	err = c.store.Teams.Update(ctx, *team)
+	if err != nil {
+		return nil, controllers.NotFound(err, "team", teamId)
+	}
+	return c.GetTeam(ctx, organizationId, teamId)
+}
+
+// DeleteTeam deletes the team's role too, which takes away every board it was on from its members
+func (c *Controller) DeleteTeam(ctx context.Context, organizationId string, teamId string) error {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return err
+	}
+	err = auth.DeleteRole(team.RoleId)
+	if err != nil {
+		return fmt.Errorf("failed to delete role of team %s: %w", teamId, err)
+	}
+	err = c.store.Teams.Delete(ctx, organizationId, teamId)
+	return controllers.NotFound(err, "team", teamId)
+}
+
+func (c *Controller) GetTeamMembers(ctx context.Context, organizationId string, teamId string) (*[]models.User, error) {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return nil, err
+	}
+	return user.GetUsersWithRole(team.RoleId)
+}
+
+// AddTeamMember gives the user the team's role, and with it every board the team is on. Only
+// members of the organization can be on its teams.
+func (c *Controller) AddTeamMember(ctx context.Context, organizationId string, teamId string, userId string) error {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return err
+	}
+	isMember, err := organization.IsMember(userId, organizationId)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("user %s is not a member of organization %s", userId, organizationId))
+	}
+	return auth.AddUserToRole(userId, team.RoleId)
+}
+
+// RemoveTeamMember takes the team's role away, so the user loses the boards they could only reach
+// through the team
+func (c *Controller) RemoveTeamMember(ctx context.Context, organizationId string, teamId string, userId string) error {
+	team, err := c.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		return err
+	}
+	return auth.RemoveUserFromRole(userId, team.RoleId)
+}
+
+// checkName trims the name and makes sure no other team in the organization has it, ignoring case.
+// exceptTeamId is the team being renamed, if any.
+func (c *Controller) checkName(ctx context.Context, organizationId string, exceptTeamId string, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", controllers.NewError(controllers.CodeValidationFailed, "team name can't be empty")
+	}
+	teams, err := c.store.Teams.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		return "", err
+	}
+	for _, team := range teams {
+		if team.Id.String() != exceptTeamId && strings.EqualFold(team.Name, name) {
+			return "", controllers.NewError(controllers.CodeConflict, fmt.Sprintf("organization %s already has a team named %s", organizationId, name))
+		}
+	}
+	return name, nil
+}
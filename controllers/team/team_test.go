@@ -0,0 +1,206 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/auth/auth0test"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+func newTestTeam(t *testing.T) (*Controller, *auth0test.Server, string, string, string) {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	fake := auth0test.NewServer()
+	t.Cleanup(fake.Close)
+	fake.Configure(cfg)
+	fake.AddUser(models.User{UserID: "auth0|owner", Email: "owner@example.com"})
+	fake.AddUser(models.User{UserID: "auth0|bob", Email: "bob@example.com"})
+	fake.AddUser(models.User{UserID: "auth0|carol", Email: "carol@example.com"})
+
+	ctx := context.Background()
+	s := store.NewMemory()
+	organizations := organization.NewController(cfg, s)
+	org, err := organizations.CreateOrganization(ctx, "auth0|owner", "Org", nil, false)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := org.Id.String()
+	err = organizations.ProvisionOrganization(ctx, "auth0|owner", organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision organization: %v", err)
+	}
+	for _, userId := range []string{"auth0|bob", "auth0|carol"} {
+		err = organizations.AddMember(userId, organizationId)
+		if err != nil {
+			t.Fatalf("failed to add member: %v", err)
+		}
+	}
+	boards := board.NewController(cfg, s)
+	created, err := boards.CreateBoard(ctx, "auth0|owner", "Board", "", true, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	boardId := created.Id.String()
+	err = boards.ProvisionBoard(ctx, "auth0|owner", boardId, organizationId)
+	if err != nil {
+		t.Fatalf("failed to provision board: %v", err)
+	}
+
+	c := NewController(cfg, s)
+	team, err := c.CreateTeam(ctx, organizationId, "auth0|owner", " Design ", "")
+	if err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+	return c, fake, organizationId, boardId, team.Id.String()
+}
+
+func expectCode(t *testing.T, err error, code controllers.ErrorCode) {
+	t.Helper()
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != code {
+		t.Fatalf("expected %s error, got %v", code, err)
+	}
+}
+
+func hasPermission(fake *auth0test.Server, userId string, name string) bool {
+	for _, permission := range fake.UserPermissions(userId) {
+		if permission == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTeamMembersGetBoardAccess(t *testing.T) {
+	ctx := context.Background()
+	c, fake, organizationId, boardId, teamId := newTestTeam(t)
+	owner := &auth.CustomClaims{Permissions: fake.UserPermissions("auth0|owner")}
+	readBoardPerm := fmt.Sprintf("org%s:board%s:read", organizationId, boardId)
+	deleteBoardPerm := fmt.Sprintf("org%s:board%s:delete", organizationId, boardId)
+
+	err := c.AddTeamMember(ctx, organizationId, teamId, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to add team member: %v", err)
+	}
+	teamBoard, err := c.AddTeamToBoard(ctx, owner, organizationId, boardId, teamId, "", "auth0|owner")
+	if err != nil {
+		t.Fatalf("failed to add team to board: %v", err)
+	}
+	if teamBoard.Role != models.TeamBoardMember {
+		t.Fatalf("expected the team to be a board member, got %s", teamBoard.Role)
+	}
+	if !hasPermission(fake, "auth0|bob", readBoardPerm) || hasPermission(fake, "auth0|bob", deleteBoardPerm) {
+		t.Fatalf("expected bob to have the board member permissions, got %v", fake.UserPermissions("auth0|bob"))
+	}
+
+	// Members added later get the board too
+	err = c.AddTeamMember(ctx, organizationId, teamId, "auth0|carol")
+	if err != nil {
+		t.Fatalf("failed to add team member: %v", err)
+	}
+	if !hasPermission(fake, "auth0|carol", readBoardPerm) {
+		t.Fatal("expected carol to read the board")
+	}
+
+	_, err = c.AddTeamToBoard(ctx, owner, organizationId, boardId, teamId, models.TeamBoardOwner, "auth0|owner")
+	if err != nil {
+		t.Fatalf("failed to change the team's role: %v", err)
+	}
+	if !hasPermission(fake, "auth0|bob", deleteBoardPerm) {
+		t.Fatal("expected bob to have the board owner permissions")
+	}
+
+	err = c.RemoveTeamMember(ctx, organizationId, teamId, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to remove team member: %v", err)
+	}
+	if hasPermission(fake, "auth0|bob", readBoardPerm) {
+		t.Fatal("expected bob to lose the board when leaving the team")
+	}
+
+	err = c.RemoveTeamFromBoard(ctx, organizationId, boardId, teamId)
+	if err != nil {
+		t.Fatalf("failed to remove team from board: %v", err)
+	}
+	if hasPermission(fake, "auth0|carol", readBoardPerm) {
+		t.Fatal("expected carol to lose the board when the team was removed from it")
+	}
+	teamBoards, err := c.GetBoardTeams(ctx, boardId)
+	if err != nil {
+		t.Fatalf("failed to get board teams: %v", err)
+	}
+	if len(*teamBoards) != 0 {
+		t.Fatalf("expected no teams on the board, got %+v", *teamBoards)
+	}
+}
+
+func TestTeamsNeedUniqueNamesAndOrganizationMembers(t *testing.T) {
+	ctx := context.Background()
+	c, fake, organizationId, boardId, teamId := newTestTeam(t)
+	owner := &auth.CustomClaims{Permissions: fake.UserPermissions("auth0|owner")}
+
+	_, err := c.CreateTeam(ctx, organizationId, "auth0|owner", "design", "")
+	expectCode(t, err, controllers.CodeConflict)
+	other, err := c.CreateTeam(ctx, organizationId, "auth0|owner", "Engineering", "")
+	if err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+	_, err = c.UpdateTeam(ctx, organizationId, other.Id.String(), "DESIGN", "")
+	expectCode(t, err, controllers.CodeConflict)
+
+	fake.AddUser(models.User{UserID: "auth0|stranger", Email: "stranger@example.com"})
+	err = c.AddTeamMember(ctx, organizationId, teamId, "auth0|stranger")
+	expectCode(t, err, controllers.CodeValidationFailed)
+
+	_, err = c.AddTeamToBoard(ctx, owner, organizationId, boardId, teamId, "admin", "auth0|owner")
+	expectCode(t, err, controllers.CodeValidationFailed)
+
+	err = c.DeleteTeam(ctx, organizationId, teamId)
+	if err != nil {
+		t.Fatalf("failed to delete team: %v", err)
+	}
+	_, err = c.GetTeam(ctx, organizationId, teamId)
+	if !errors.Is(err, controllers.ErrNotFound) {
+		t.Fatalf("expected the team to be gone, got %v", err)
+	}
+	teams, err := c.GetTeams(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to get teams: %v", err)
+	}
+	if len(*teams) != 1 || (*teams)[0].Name != "Engineering" {
+		t.Fatalf("expected only Engineering to be left, got %+v", *teams)
+	}
+}
+
+func TestOnlyThoseWhoCanUpdateTheBoardCanMakeATeamItsOwner(t *testing.T) {
+	ctx := context.Background()
+	c, fake, organizationId, boardId, teamId := newTestTeam(t)
+	deleteBoardPerm := fmt.Sprintf("org%s:board%s:delete", organizationId, boardId)
+	err := c.AddTeamMember(ctx, organizationId, teamId, "auth0|bob")
+	if err != nil {
+		t.Fatalf("failed to add team member: %v", err)
+	}
+	recruiter := &auth.CustomClaims{Permissions: []string{
+		fmt.Sprintf("org%s:read", organizationId),
+		fmt.Sprintf("org%s:board%s:read", organizationId, boardId),
+		fmt.Sprintf("org%s:board%s:add_members", organizationId, boardId),
+	}}
+
+	_, err = c.AddTeamToBoard(ctx, recruiter, organizationId, boardId, teamId, models.TeamBoardOwner, "auth0|recruiter")
+	expectCode(t, err, controllers.CodeForbidden)
+	if hasPermission(fake, "auth0|bob", deleteBoardPerm) {
+		t.Fatal("expected the team not to get the owner permissions")
+	}
+}
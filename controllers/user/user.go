@@ -11,7 +11,6 @@ import (
 
 	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/models"
-	"github.com/jmoiron/sqlx"
 )
 
 func (c *Controller) GetUsers() (*[]models.User, error) {
@@ -173,15 +172,15 @@ func (c *Controller) DeleteUserById(ctx context.Context, userId string) error {
 		return fmt.Errorf("failed to delete user: %s", string(body))
 	}
 
-	_, err = c.db.DB.ExecContext(ctx, `DELETE FROM Organizations WHERE owner_id=$1`, userId)
+	err = c.store.Organizations.DeleteByOwner(ctx, userId)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `DELETE FROM Boards WHERE owner_id=$1`, userId)
+	err = c.store.Boards.DeleteByOwner(ctx, userId)
 	if err != nil {
 		return err
 	}
-	_, err = c.db.DB.ExecContext(ctx, `DELETE FROM Assigned_Cards WHERE user_id=$1`, userId)
+	err = c.store.Assignments.DeleteByUser(ctx, userId)
 	if err != nil {
 		return err
 	}
@@ -195,8 +194,9 @@ func (c *Controller) GetUserOrganizationsById(ctx context.Context, userId string
 		return nil, err
 	}
 
+	// Only the member role counts, so organizations the user is just a guest in are left out
 	var orgIds []string
-	findUUIDInRoleRegex := regexp.MustCompile(`org(.*?):`)
+	findUUIDInRoleRegex := regexp.MustCompile(`^org([^:]+):member$`)
 	for _, role := range *usersRoles {
 		matches := findUUIDInRoleRegex.FindStringSubmatch(role.Name)
 		if len(matches) < 2 {
@@ -215,16 +215,7 @@ func (c *Controller) GetUserOrganizationsById(ctx context.Context, userId string
 		}
 	}
 
-	if len(orgIds) == 0 {
-		return &[]models.Organization{}, nil
-	}
-	query, args, err := sqlx.In(`SELECT * FROM Organizations WHERE id IN (?)`, orgIds)
-	if err != nil {
-		return nil, err
-	}
-	query = c.db.DB.Rebind(query)
-	var organizations []models.Organization
-	err = c.db.DB.SelectContext(ctx, &organizations, query, args...)
+	organizations, err := c.store.Organizations.ListByIds(ctx, orgIds)
 	if err != nil {
 		return nil, err
 	}
@@ -232,17 +223,14 @@ func (c *Controller) GetUserOrganizationsById(ctx context.Context, userId string
 }
 
 func (c *Controller) GetUserOwnedOrganizationsById(ctx context.Context, userId string) (*[]models.Organization, error) {
-	var organizations []models.Organization
-	err := c.db.DB.SelectContext(ctx, &organizations, `
-		SELECT * FROM Organizations WHERE owner_id=$1;
-	`, userId)
+	organizations, err := c.store.Organizations.ListByOwner(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
 	return &organizations, nil
 }
 
-func (c *Controller) GetUserBoardsById(ctx context.Context, userId string) (*[]models.Board, error) {
+func (c *Controller) GetUserBoardsById(ctx context.Context, userId string, includeArchived bool) (*[]models.Board, error) {
 	usersRoles, err := auth.GetUserRoles(userId)
 	if err != nil {
 		return nil, err
@@ -268,27 +256,41 @@ func (c *Controller) GetUserBoardsById(ctx context.Context, userId string) (*[]m
 		}
 	}
 
-	if len(boardIds) == 0 {
-		return &[]models.Board{}, nil
+	// Boards the user is on through a team don't show up in their roles
+	var teamIds []string
+	findTeamInRoleRegex := regexp.MustCompile(`^org[^:]+:team([^:]+)$`)
+	for _, role := range *usersRoles {
+		matches := findTeamInRoleRegex.FindStringSubmatch(role.Name)
+		if len(matches) == 2 {
+			teamIds = append(teamIds, matches[1])
+		}
 	}
-	query, args, err := sqlx.In(`SELECT * FROM Boards WHERE id IN (?)`, boardIds)
+	teamBoardIds, err := c.store.Teams.ListBoardIds(ctx, teamIds)
 	if err != nil {
 		return nil, err
 	}
-	query = c.db.DB.Rebind(query)
-	var boards []models.Board
-	err = c.db.DB.SelectContext(ctx, &boards, query, args...)
+	for _, teamBoardId := range teamBoardIds {
+		alreadyFound := false
+		for _, bId := range boardIds {
+			if bId == teamBoardId {
+				alreadyFound = true
+				break
+			}
+		}
+		if !alreadyFound {
+			boardIds = append(boardIds, teamBoardId)
+		}
+	}
+
+	boards, err := c.store.Boards.ListByIds(ctx, boardIds, includeArchived)
 	if err != nil {
 		return nil, err
 	}
 	return &boards, nil
 }
 
-func (c *Controller) GetUserOwnedBoardsById(ctx context.Context, userId string) (*[]models.Board, error) {
-	var boards []models.Board
-	err := c.db.DB.SelectContext(ctx, &boards, `
-		SELECT * FROM Boards WHERE owner_id=$1;
-	`, userId)
+func (c *Controller) GetUserOwnedBoardsById(ctx context.Context, userId string, includeArchived bool) (*[]models.Board, error) {
+	boards, err := c.store.Boards.ListByOwner(ctx, userId, includeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -296,17 +298,7 @@ func (c *Controller) GetUserOwnedBoardsById(ctx context.Context, userId string)
 }
 
 func (c *Controller) GetUserAssignedCardsById(ctx context.Context, userId string) (*[]models.DetailedAssignedCard, error) {
-	var cards []models.DetailedAssignedCard
-	err := c.db.DB.SelectContext(ctx, &cards, `
-	SELECT ac.user_id, c.*, s.id as stack_id, p.id as panel_id, b.id as board_id, o.id as org_id
-	FROM Assigned_Cards ac
-	JOIN Cards c on ac.card_id = c.id
-	JOIN Stacks s ON c.stack_id = s.id
-	JOIN Panels p ON s.panel_id = p.id
-	JOIN Boards b ON p.board_id = b.id
-	JOIN organizations o ON b.organization_id = o.id
-	WHERE ac.user_id = $1;
-	`, userId)
+	cards, err := c.store.Assignments.ListDetailedByUser(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -314,14 +306,7 @@ func (c *Controller) GetUserAssignedCardsById(ctx context.Context, userId string
 }
 
 func (c *Controller) GetFavouriteBoards(ctx context.Context, userId string) (*[]models.Board, error) {
-	var favouriteBoards []models.Board
-	//  This Join will return board objects that are favorited by the user with the given userId
-	err := c.db.DB.SelectContext(ctx, &favouriteBoards, `
-		SELECT b.*
-		FROM favorite_boards AS fb
-		JOIN boards AS b ON fb.board_id = b.id
-		WHERE fb.user_id = $1;
-	`, userId)
+	favouriteBoards, err := c.store.Favourites.ListBoards(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -329,21 +314,9 @@ func (c *Controller) GetFavouriteBoards(ctx context.Context, userId string) (*[]
 }
 
 func (c *Controller) AddFavouriteBoard(ctx context.Context, userId string, boardId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		INSERT INTO favorite_boards (user_id, board_id) VALUES ($1, $2);
-	`, userId, boardId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.store.Favourites.Add(ctx, userId, boardId)
 }
 
 func (c *Controller) RemoveFavouriteBoard(ctx context.Context, userId string, boardId string) error {
-	_, err := c.db.DB.ExecContext(ctx, `
-		DELETE FROM favorite_boards WHERE user_id=$1 AND board_id=$2;
-	`, userId, boardId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.store.Favourites.Remove(ctx, userId, boardId)
 }
@@ -125,6 +125,22 @@ func GetOrgMembers(organizationId string) (*[]models.User, error) {
 	return users, nil
 }
 
+// GetOrgGuests returns the users who are on boards in the organization without being members of it
+func GetOrgGuests(organizationId string) (*[]models.User, error) {
+	orgGuestRoleName := fmt.Sprintf("org%s:guest", organizationId)
+	roles, err := auth.GetRoles(&orgGuestRoleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range *roles {
+		if role.Name == orgGuestRoleName {
+			return GetUsersWithRole(role.Id)
+		}
+	}
+	// The guest role is only created when the first guest is added
+	return &[]models.User{}, nil
+}
+
 func GetOrgOwners(organizationId string) (*[]models.User, error) {
 	orgOwnerRoleName := fmt.Sprintf("org%s:owner", organizationId)
 	roles, err := auth.GetRoles(&orgOwnerRoleName)
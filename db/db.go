@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
@@ -27,3 +28,27 @@ func New(username, password, host, dbName string) (*DB, error) {
 	}
 	return &DB{conn}, nil
 }
+
+// AdvisoryLock waits for the Postgres advisory lock key, which every server connected to the
+// database shares, and returns a function that releases it. It holds a connection until then,
+// since advisory locks belong to the session that took them.
+func (db *DB) AdvisoryLock(ctx context.Context, key int64) (func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1);", key)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() {
+		// Closing the connection returns it to the pool still holding the lock, so release it first
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1);", key)
+		if err != nil {
+			// A connection that can't unlock is thrown away, which ends its session and the lock
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
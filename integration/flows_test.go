@@ -0,0 +1,258 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Sync-Space-49/syncspace-server/client"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func expectForbidden(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, controllers.ErrForbidden) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func containsUser(users []models.User, userId string) bool {
+	for _, user := range users {
+		if user.UserID == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// createOrganization creates an organization and waits for it to be provisioned
+func createOrganization(t *testing.T, env *environment, c *client.Client) *models.Organization {
+	t.Helper()
+	organization, err := c.CreateOrganization(context.Background(), client.OrganizationInput{Title: "Integration Org"})
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	if organization.ProvisioningStatus != models.ProvisioningInProgress {
+		t.Fatalf("expected the new organization to be provisioning, got %s", organization.ProvisioningStatus)
+	}
+	env.runJobs(t)
+	return organization
+}
+
+func TestOrganizationFlow(t *testing.T) {
+	env := newEnvironment(t)
+	ctx := context.Background()
+	alice := env.user(t, "auth0|alice")
+	bob := env.user(t, "auth0|bob")
+
+	organization := createOrganization(t, env, alice)
+	organizationId := organization.Id.String()
+	if organization.OwnerId != "auth0|alice" {
+		t.Fatalf("expected alice to own the organization, got %s", organization.OwnerId)
+	}
+	if _, err := alice.GetOrganization(ctx, organizationId); err != nil {
+		t.Fatalf("owner failed to get organization: %v", err)
+	}
+	members, err := alice.ListOrganizationMembers(organizationId).All(ctx)
+	if err != nil {
+		t.Fatalf("failed to list members: %v", err)
+	}
+	if !containsUser(members, "auth0|alice") {
+		t.Fatalf("expected alice to be a member, got %v", members)
+	}
+
+	_, err = bob.GetOrganization(ctx, organizationId)
+	expectForbidden(t, err)
+
+	if err := alice.AddOrganizationMember(ctx, organizationId, "auth0|bob"); err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	if _, err := bob.GetOrganization(ctx, organizationId); err != nil {
+		t.Fatalf("member failed to get organization: %v", err)
+	}
+	organizations, err := bob.ListUserOrganizations("auth0|bob").All(ctx)
+	if err != nil {
+		t.Fatalf("failed to list user organizations: %v", err)
+	}
+	if len(organizations) != 1 || organizations[0].Id != organization.Id {
+		t.Fatalf("expected bob to be in one organization, got %v", organizations)
+	}
+	expectForbidden(t, bob.DeleteOrganization(ctx, organizationId))
+
+	if err := alice.RemoveOrganizationMember(ctx, organizationId, "auth0|bob"); err != nil {
+		t.Fatalf("failed to remove member: %v", err)
+	}
+	_, err = bob.GetOrganization(ctx, organizationId)
+	expectForbidden(t, err)
+
+	if err := alice.DeleteOrganization(ctx, organizationId); err != nil {
+		t.Fatalf("failed to delete organization: %v", err)
+	}
+	for _, role := range env.auth0.Roles() {
+		if strings.HasPrefix(role.Name, fmt.Sprintf("org%s:", organizationId)) {
+			t.Fatalf("expected organization roles to be deleted, found %s", role.Name)
+		}
+	}
+}
+
+func TestBoardFlow(t *testing.T) {
+	env := newEnvironment(t)
+	ctx := context.Background()
+	alice := env.user(t, "auth0|alice")
+	bob := env.user(t, "auth0|bob")
+
+	organizationId := createOrganization(t, env, alice).Id.String()
+	board, err := alice.CreateBoard(ctx, organizationId, client.BoardInput{Title: "Sprint 1"})
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	env.runJobs(t)
+	boardId := board.Id.String()
+	panel, err := alice.CreatePanel(ctx, organizationId, boardId, "Backlog")
+	if err != nil {
+		t.Fatalf("failed to create panel: %v", err)
+	}
+	panelId := panel.Id.String()
+	stack, err := alice.CreateStack(ctx, organizationId, boardId, panelId, "To Do")
+	if err != nil {
+		t.Fatalf("failed to create stack: %v", err)
+	}
+	stackId := stack.Id.String()
+	card, err := alice.CreateCard(ctx, organizationId, boardId, panelId, stackId, client.CardInput{Title: "Write tests", Points: "3"})
+	if err != nil {
+		t.Fatalf("failed to create card: %v", err)
+	}
+	cardId := card.Id.String()
+
+	completeBoard, err := alice.GetCompleteBoard(ctx, organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to get complete board: %v", err)
+	}
+	if len(completeBoard.Panels) != 1 || len(completeBoard.Panels[0].Stacks) != 1 || len(completeBoard.Panels[0].Stacks[0].Cards) != 1 {
+		t.Fatalf("expected one panel with one stack with one card, got %+v", completeBoard)
+	}
+
+	// updates against a stale version are rejected with the current card
+	err = alice.UpdateCard(ctx, organizationId, boardId, panelId, stackId, cardId, card.Version+1, client.CardUpdate{Title: "Stale"})
+	var apiError *client.Error
+	if !errors.As(err, &apiError) || apiError.Code != controllers.CodeVersionMismatch {
+		t.Fatalf("expected version mismatch, got %v", err)
+	}
+	var current models.Card
+	if err := apiError.Current(&current); err != nil {
+		t.Fatalf("failed to decode current card: %v", err)
+	}
+	if current.Version != card.Version {
+		t.Fatalf("expected current version %d, got %d", card.Version, current.Version)
+	}
+	patched, err := alice.PatchCard(ctx, organizationId, boardId, panelId, stackId, cardId, current.Version, models.CardPatch{Title: models.NewOptional("Write more tests")})
+	if err != nil {
+		t.Fatalf("failed to patch card: %v", err)
+	}
+	if patched.Title != "Write more tests" || patched.Points != "3" {
+		t.Fatalf("unexpected patched card %+v", patched)
+	}
+
+	if _, err := alice.ArchiveBoard(ctx, organizationId, boardId); err != nil {
+		t.Fatalf("failed to archive board: %v", err)
+	}
+	_, err = alice.CreatePanel(ctx, organizationId, boardId, "Done")
+	if !errors.As(err, &apiError) || apiError.Code != controllers.CodeBoardArchived {
+		t.Fatalf("expected board archived error, got %v", err)
+	}
+	board, err = alice.UnarchiveBoard(ctx, organizationId, boardId)
+	if err != nil {
+		t.Fatalf("failed to unarchive board: %v", err)
+	}
+
+	if err := alice.DeleteBoard(ctx, organizationId, boardId, board.Version); err != nil {
+		t.Fatalf("failed to delete board: %v", err)
+	}
+	trash, err := alice.ListOrganizationTrash(organizationId).All(ctx)
+	if err != nil {
+		t.Fatalf("failed to list trash: %v", err)
+	}
+	if len(trash) != 1 || trash[0].Id != board.Id {
+		t.Fatalf("expected the board in the trash, got %v", trash)
+	}
+	if _, err := alice.RestoreBoard(ctx, organizationId, boardId); err != nil {
+		t.Fatalf("failed to restore board: %v", err)
+	}
+
+	// board members can work on cards but not change the board's layout
+	if err := alice.AddOrganizationMember(ctx, organizationId, "auth0|bob"); err != nil {
+		t.Fatalf("failed to add organization member: %v", err)
+	}
+	if err := alice.AddBoardMember(ctx, organizationId, boardId, "auth0|bob"); err != nil {
+		t.Fatalf("failed to add board member: %v", err)
+	}
+	if _, err := bob.CreateCard(ctx, organizationId, boardId, panelId, stackId, client.CardInput{Title: "Review tests"}); err != nil {
+		t.Fatalf("board member failed to create card: %v", err)
+	}
+	_, err = bob.CreatePanel(ctx, organizationId, boardId, "Bob's panel")
+	expectForbidden(t, err)
+}
+
+func TestRoleFlow(t *testing.T) {
+	env := newEnvironment(t)
+	ctx := context.Background()
+	alice := env.user(t, "auth0|alice")
+	bob := env.user(t, "auth0|bob")
+
+	organizationId := createOrganization(t, env, alice).Id.String()
+	readPermission := fmt.Sprintf("org%s:read", organizationId)
+	role, err := alice.CreateRole(ctx, organizationId, client.RoleInput{
+		Name:            "reviewers",
+		Description:     "Can look but not touch",
+		PermissionNames: []string{readPermission},
+	})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	roles, err := alice.ListRoles(organizationId).All(ctx)
+	if err != nil {
+		t.Fatalf("failed to list roles: %v", err)
+	}
+	found := false
+	for _, listed := range roles {
+		found = found || listed.Name == fmt.Sprintf("org%s:reviewers", organizationId)
+	}
+	if !found {
+		t.Fatalf("expected reviewers role, got %v", roles)
+	}
+
+	_, err = bob.GetOrganization(ctx, organizationId)
+	expectForbidden(t, err)
+	if err := alice.AddRoleMember(ctx, organizationId, role.Id, "auth0|bob"); err != nil {
+		t.Fatalf("failed to add role member: %v", err)
+	}
+	if _, err := bob.GetOrganization(ctx, organizationId); err != nil {
+		t.Fatalf("role member failed to get organization: %v", err)
+	}
+	_, err = bob.CreateBoard(ctx, organizationId, client.BoardInput{Title: "Bob's board"})
+	expectForbidden(t, err)
+
+	err = alice.UpdateRole(ctx, organizationId, role.Id, client.RoleInput{
+		Name:            "reviewers",
+		Description:     "Can look and start boards",
+		PermissionNames: []string{readPermission, fmt.Sprintf("org%s:create_boards", organizationId)},
+	})
+	if err != nil {
+		t.Fatalf("failed to update role: %v", err)
+	}
+	if _, err := bob.CreateBoard(ctx, organizationId, client.BoardInput{Title: "Bob's board"}); err != nil {
+		t.Fatalf("role member failed to create board after update: %v", err)
+	}
+
+	if err := alice.RemoveRoleMember(ctx, organizationId, role.Id, "auth0|bob"); err != nil {
+		t.Fatalf("failed to remove role member: %v", err)
+	}
+	_, err = bob.GetOrganization(ctx, organizationId)
+	expectForbidden(t, err)
+	if err := alice.DeleteRole(ctx, organizationId, role.Id); err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/google/uuid"
+)
+
+// newStore returns the Postgres store along with a new organization to keep rows in, for tests
+// that run a repository's queries directly
+func newStore(t *testing.T) (*store.Store, string) {
+	t.Helper()
+	if testDB == nil {
+		t.Skipf("%s is not set", databaseURLEnv)
+	}
+	s := store.NewPostgres(testDB)
+	organization := models.Organization{Id: uuid.New(), OwnerId: "auth0|alice", Name: "Store Org"}
+	err := s.Organizations.Create(context.Background(), organization)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	return s, organization.Id.String()
+}
+
+func TestPostgresDomains(t *testing.T) {
+	ctx := context.Background()
+	s, organizationId := newStore(t)
+	domain := models.OrganizationDomain{
+		OrganizationId:     uuid.MustParse(organizationId),
+		Domain:             "example.com",
+		VerificationRecord: "syncspace-verification=abc",
+		CreatedBy:          "auth0|alice",
+	}
+	err := s.Domains.Add(ctx, domain)
+	if err != nil {
+		t.Fatalf("failed to add domain: %v", err)
+	}
+	domains, err := s.Domains.ListByDomain(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("failed to list domains: %v", err)
+	}
+	if len(domains) != 1 || domains[0].VerificationRecord != domain.VerificationRecord || domains[0].VerifiedAt != nil {
+		t.Fatalf("expected the domain to be unverified with its record, got %+v", domains)
+	}
+
+	err = s.Domains.Verify(ctx, organizationId, "example.com")
+	if err != nil {
+		t.Fatalf("failed to verify domain: %v", err)
+	}
+	// Changing the role of a verified domain keeps it verified
+	roleId := "rol_1"
+	domain.RoleId = &roleId
+	err = s.Domains.Add(ctx, domain)
+	if err != nil {
+		t.Fatalf("failed to add domain again: %v", err)
+	}
+	domains, err = s.Domains.ListByOrganization(ctx, organizationId)
+	if err != nil {
+		t.Fatalf("failed to list domains: %v", err)
+	}
+	if len(domains) != 1 || domains[0].VerifiedAt == nil || domains[0].RoleId == nil || *domains[0].RoleId != roleId {
+		t.Fatalf("expected the domain to stay verified with its new role, got %+v", domains)
+	}
+	err = s.Domains.Verify(ctx, organizationId, "example.org")
+	if err == nil {
+		t.Fatal("expected verifying a domain that wasn't added to fail")
+	}
+}
+
+func TestPostgresInviteLinks(t *testing.T) {
+	ctx := context.Background()
+	s, organizationId := newStore(t)
+	maxUses := 1
+	link := models.InviteLink{
+		Id:             uuid.New(),
+		OrganizationId: uuid.MustParse(organizationId),
+		CreatedBy:      "auth0|alice",
+		TokenHash:      "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		MaxUses:        &maxUses,
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	err := s.InviteLinks.Create(ctx, link, &expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create invite link: %v", err)
+	}
+	found, err := s.InviteLinks.GetByTokenHash(ctx, link.TokenHash, time.Now())
+	if err != nil || found.Id != link.Id {
+		t.Fatalf("expected to find the link by its token, got %+v, %v", found, err)
+	}
+	links, err := s.InviteLinks.ListByOrganization(ctx, organizationId)
+	if err != nil || len(links) != 1 {
+		t.Fatalf("expected the organization to have one link, got %v, %v", links, err)
+	}
+	for _, expected := range []bool{true, false} {
+		used, err := s.InviteLinks.Use(ctx, link.Id.String())
+		if err != nil || used != expected {
+			t.Fatalf("expected using the link to return %v, got %v, %v", expected, used, err)
+		}
+	}
+}
+
+func TestPostgresCredentials(t *testing.T) {
+	ctx := context.Background()
+	s, organizationId := newStore(t)
+	key := models.APIKey{
+		Id:             uuid.New(),
+		OrganizationId: uuid.MustParse(organizationId),
+		CreatedBy:      "auth0|alice",
+		Name:           "ci",
+		TokenHash:      "1123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Permissions:    models.PermissionList{"org" + organizationId + ":read", "org" + organizationId + ":update"},
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	err := s.Credentials.CreateAPIKey(ctx, key, &expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	found, err := s.Credentials.GetAPIKeyByHash(ctx, key.TokenHash, time.Now())
+	if err != nil || found.Id != key.Id || !reflect.DeepEqual(found.Permissions, key.Permissions) {
+		t.Fatalf("expected to find the key with its permissions, got %+v, %v", found, err)
+	}
+	_, err = s.Credentials.GetAPIKeyByHash(ctx, key.TokenHash, expiresAt.Add(time.Second))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected the key not to be found once it expired, got %v", err)
+	}
+
+	// Postgres keeps microseconds
+	usedAt := time.Now().Truncate(time.Microsecond)
+	for _, at := range []time.Time{usedAt, usedAt.Add(30 * time.Second)} {
+		err = s.Credentials.MarkAPIKeyUsed(ctx, key.Id.String(), at)
+		if err != nil {
+			t.Fatalf("failed to mark the key used: %v", err)
+		}
+	}
+	found, err = s.Credentials.GetAPIKey(ctx, organizationId, key.Id.String())
+	if err != nil || found.LastUsedAt == nil {
+		t.Fatalf("expected the key to have been used, got %+v, %v", found, err)
+	}
+	lastUsedAt, err := time.Parse(time.RFC3339Nano, *found.LastUsedAt)
+	if err != nil || !lastUsedAt.Equal(usedAt) {
+		t.Fatalf("expected a use within a minute not to be recorded, got %s, %v", *found.LastUsedAt, err)
+	}
+
+	err = s.Credentials.DeleteAPIKey(ctx, uuid.NewString(), key.Id.String())
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected another organization not to delete the key, got %v", err)
+	}
+	err = s.Credentials.DeleteAPIKey(ctx, organizationId, key.Id.String())
+	if err != nil {
+		t.Fatalf("failed to delete API key: %v", err)
+	}
+	keys, err := s.Credentials.ListAPIKeys(ctx, organizationId)
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys left, got %v, %v", keys, err)
+	}
+}
+
+func TestPostgresPurgeDeletedOnlyReturnsPurgedBoards(t *testing.T) {
+	ctx := context.Background()
+	s, organizationId := newStore(t)
+	createBoard := func(title string) string {
+		board := models.Board{Id: uuid.New(), OrganizationId: uuid.MustParse(organizationId), OwnerId: "auth0|alice", Title: title}
+		err := s.Boards.Create(ctx, board)
+		if err != nil {
+			t.Fatalf("failed to create board: %v", err)
+		}
+		err = s.Boards.SoftDelete(ctx, board.Id.String(), time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("failed to delete board: %v", err)
+		}
+		return board.Id.String()
+	}
+	purgedId := createBoard("Purged")
+	restoredId := createBoard("Restored")
+	err := s.Boards.Restore(ctx, restoredId, time.Now())
+	if err != nil {
+		t.Fatalf("failed to restore board: %v", err)
+	}
+
+	purged, err := s.Boards.PurgeDeleted(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("failed to purge trash: %v", err)
+	}
+	purgedIds := make(map[string]bool)
+	for _, board := range purged {
+		purgedIds[board.Id.String()] = true
+	}
+	if !purgedIds[purgedId] || purgedIds[restoredId] {
+		t.Fatalf("expected only the board still in the trash to be purged, got %v", purged)
+	}
+	_, err = s.Boards.Get(ctx, restoredId)
+	if err != nil {
+		t.Fatalf("expected the restored board to be kept, got %v", err)
+	}
+}
+
+func TestPostgresClaimProvisioning(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newStore(t)
+	organization := models.Organization{Id: uuid.New(), OwnerId: "auth0|alice", Name: "Provisioning Org", ProvisioningStatus: models.ProvisioningInProgress}
+	err := s.Organizations.Create(ctx, organization)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	organizationId := organization.Id.String()
+	cutoff := time.Now().Add(-time.Minute)
+	for _, expected := range []bool{true, false} {
+		claimed, err := s.Organizations.ClaimProvisioning(ctx, organizationId, models.ProvisioningInProgress, cutoff)
+		if err != nil || claimed != expected {
+			t.Fatalf("expected claiming the organization to return %v, got %v, %v", expected, claimed, err)
+		}
+	}
+	claimed, err := s.Organizations.ClaimProvisioning(ctx, organizationId, models.ProvisioningFailed, time.Now().Add(time.Minute))
+	if err != nil || claimed {
+		t.Fatalf("expected an organization that isn't failed not to be claimed, got %v, %v", claimed, err)
+	}
+
+	board := models.Board{Id: uuid.New(), OrganizationId: organization.Id, OwnerId: "auth0|alice", Title: "Provisioning", ProvisioningStatus: models.ProvisioningFailed}
+	err = s.Boards.Create(ctx, board)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	claimed, err = s.Boards.ClaimProvisioning(ctx, board.Id.String(), models.ProvisioningFailed, cutoff)
+	if err != nil || !claimed {
+		t.Fatalf("expected to claim the failed board, got %v, %v", claimed, err)
+	}
+	found, err := s.Boards.Get(ctx, board.Id.String())
+	if err != nil || found.ProvisioningClaimedAt == nil {
+		t.Fatalf("expected the board to be claimed, got %+v, %v", found, err)
+	}
+}
+
+func TestPostgresJobsOnlyRecordTheAttemptThatHoldsThem(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newStore(t)
+	job := models.Job{Id: uuid.New(), Type: "integration_test", Payload: []byte(`{}`), MaxAttempts: 3}
+	err := s.Jobs.Create(ctx, job, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	claim := func(staleBefore time.Time) *models.Job {
+		// Other tests' jobs might be due too, so claim until this one comes up
+		for {
+			claimed, err := s.Jobs.Claim(ctx, time.Now(), staleBefore)
+			if err != nil {
+				t.Fatalf("failed to claim job: %v", err)
+			}
+			if claimed.Id == job.Id {
+				return claimed
+			}
+		}
+	}
+	first := claim(time.Now().Add(-time.Minute))
+	// The first attempt ran too long, so another worker claimed the job again
+	second := claim(time.Now().Add(time.Minute))
+
+	recorded, err := s.Jobs.Succeed(ctx, *first, time.Now())
+	if err != nil || recorded {
+		t.Fatalf("expected the first attempt not to be recorded, got %v, %v", recorded, err)
+	}
+	recorded, err = s.Jobs.Fail(ctx, *second, "still failing", nil, time.Now())
+	if err != nil || !recorded {
+		t.Fatalf("expected the second attempt to be recorded, got %v, %v", recorded, err)
+	}
+	found, err := s.Jobs.Get(ctx, job.Id.String())
+	if err != nil || found.Status != models.JobDead || found.Attempts != 2 {
+		t.Fatalf("expected the job to be dead after its second attempt, got %+v, %v", found, err)
+	}
+}
+
+func TestAdvisoryLockIsHeldUntilUnlocked(t *testing.T) {
+	if testDB == nil {
+		t.Skipf("%s is not set", databaseURLEnv)
+	}
+	ctx := context.Background()
+	unlock, err := testDB.AdvisoryLock(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	locked := make(chan func())
+	go func() {
+		// Another server waiting for the same lock
+		unlock, err := testDB.AdvisoryLock(ctx, 1)
+		if err != nil {
+			t.Errorf("failed to lock again: %v", err)
+			close(locked)
+			return
+		}
+		locked <- unlock
+	}()
+	select {
+	case <-locked:
+		t.Fatal("expected the lock to wait until it was unlocked")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case unlock, ok := <-locked:
+		if ok {
+			unlock()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the lock to be taken once it was unlocked")
+	}
+}
+
+func TestPostgresCreateJobOnce(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newStore(t)
+	job := models.Job{Id: uuid.New(), Type: "integration_test", Payload: []byte(`{}`), MaxAttempts: 1}
+	for _, expected := range []bool{true, false} {
+		created, err := s.Jobs.CreateOnce(ctx, job, time.Now().Add(time.Hour))
+		if err != nil || created != expected {
+			t.Fatalf("expected creating the job to return %v, got %v, %v", expected, created, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/mail"
+)
+
+// SendEmail sends the message with the worker's mail.Sender
+var SendEmail = Type[mail.Message]{Name: "send_email"}
+
+// DeleteAccess deletes the Auth0 roles and permissions starting with Prefix, like the org<id>:
+// ones of a deleted organization
+var DeleteAccess = Type[DeleteAccessPayload]{Name: "delete_access"}
+
+type DeleteAccessPayload struct {
+	Prefix string `json:"prefix"`
+}
+
+// RegisterHandlers makes the worker run every type of job in this package, sending email with
+// sender
+func RegisterHandlers(w *Worker, sender mail.Sender) {
+	Register(w, SendEmail, sender.Send)
+	Register(w, DeleteAccess, func(ctx context.Context, payload DeleteAccessPayload) error {
+		return auth.DeleteAccess(ctx, payload.Prefix)
+	})
+}
+
+// MailSender is a mail.Sender that queues each message as a SendEmail job, so a mail server that
+// is down doesn't fail the request and the message is sent once it is back
+type MailSender struct {
+	queue *Queue
+}
+
+func NewMailSender(queue *Queue) *MailSender {
+	return &MailSender{queue: queue}
+}
+
+func (s *MailSender) Send(ctx context.Context, message mail.Message) error {
+	_, err := SendEmail.Enqueue(ctx, s.queue, message)
+	return err
+}
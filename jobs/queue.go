@@ -0,0 +1,145 @@
+// Package jobs does work in the background instead of in the request that caused it, like sending
+// email or cleaning up Auth0. Jobs are rows in the Jobs table, so they survive restarts, and
+// Workers running in the server claim them with SELECT ... FOR UPDATE SKIP LOCKED, so any number
+// of workers on any number of servers can share the queue.
+//
+// Failed jobs are retried with exponential backoff until they have been tried MaxAttempts times,
+// and are then left dead until someone retries them through the admin routes.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxAttempts is how many times a job is tried unless it is enqueued with MaxAttempts
+const DefaultMaxAttempts = 5
+
+// Type is a kind of job along with the payload its handler is given, so enqueuing and handling it
+// can't disagree on what the payload looks like
+type Type[T any] struct {
+	Name string
+}
+
+// Enqueue adds a job of this type to the queue
+func (t Type[T]) Enqueue(ctx context.Context, queue *Queue, payload T, options ...Option) (*models.Job, error) {
+	return queue.enqueue(ctx, t.Name, payload, options...)
+}
+
+// Active returns the payloads of the jobs of this type that are queued or running, so work they are
+// about to do isn't started a second time somewhere else
+func (t Type[T]) Active(ctx context.Context, queue *Queue) ([]T, error) {
+	jobs, err := queue.store.ListActive(ctx, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	payloads := make([]T, 0, len(jobs))
+	for _, job := range jobs {
+		var payload T
+		err = json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s job %s: %w", t.Name, job.Id, err)
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, nil
+}
+
+type enqueueOptions struct {
+	runAt       time.Time
+	maxAttempts int
+}
+
+type Option func(*enqueueOptions)
+
+// At keeps the job from running before runAt
+func At(runAt time.Time) Option {
+	return func(options *enqueueOptions) {
+		options.runAt = runAt
+	}
+}
+
+// After keeps the job from running until delay has passed
+func After(delay time.Duration) Option {
+	return func(options *enqueueOptions) {
+		options.runAt = time.Now().UTC().Add(delay)
+	}
+}
+
+// MaxAttempts is how many times the job is tried before it is dead
+func MaxAttempts(attempts int) Option {
+	return func(options *enqueueOptions) {
+		options.maxAttempts = attempts
+	}
+}
+
+type Queue struct {
+	store store.JobRepository
+}
+
+func NewQueue(store store.JobRepository) *Queue {
+	return &Queue{store: store}
+}
+
+func (q *Queue) enqueue(ctx context.Context, jobType string, payload any, options ...Option) (*models.Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+	settings := enqueueOptions{runAt: time.Now().UTC(), maxAttempts: DefaultMaxAttempts}
+	for _, option := range options {
+		option(&settings)
+	}
+	job := models.Job{
+		Id:          uuid.New(),
+		Type:        jobType,
+		Payload:     encoded,
+		MaxAttempts: settings.maxAttempts,
+	}
+	err = q.store.Create(ctx, job, settings.runAt)
+	if err != nil {
+		return nil, err
+	}
+	return q.GetJob(ctx, job.Id.String())
+}
+
+func (q *Queue) GetJob(ctx context.Context, jobId string) (*models.Job, error) {
+	job, err := q.store.Get(ctx, jobId)
+	if err != nil {
+		return nil, controllers.NotFound(err, "job", jobId)
+	}
+	return job, nil
+}
+
+// ListJobs lists the newest jobs with status, or with any status if it is empty
+func (q *Queue) ListJobs(ctx context.Context, status string, limit int) ([]models.Job, error) {
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobDead:
+	default:
+		return nil, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("status must be %s, %s, %s or %s", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobDead))
+	}
+	return q.store.List(ctx, status, limit)
+}
+
+// RetryJob queues a dead job to run again now, with all of its attempts back
+func (q *Queue) RetryJob(ctx context.Context, jobId string) (*models.Job, error) {
+	job, err := q.GetJob(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+	retried, err := q.store.Retry(ctx, jobId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !retried {
+		return nil, controllers.NewError(controllers.CodeConflict, fmt.Sprintf("job %s is %s, only dead jobs can be retried", jobId, job.Status))
+	}
+	return q.GetJob(ctx, jobId)
+}
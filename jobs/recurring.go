@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/google/uuid"
+)
+
+// recurringNamespace is what the ids of recurring jobs' runs are derived in
+var recurringNamespace = uuid.MustParse("6f1d8a52-3c47-4b8e-9a0d-2e5b7c914f36")
+
+// RegisterRecurring makes the worker run handle every interval as a job of jobType, and queues its
+// next run. Runs are due on multiples of interval and their ids are derived from when they are
+// due, so every server queues the same run and it is only queued, and done, once. Each run queues
+// the one after it when it starts, so one that fails is retried like any job without holding up
+// the next.
+func RegisterRecurring(ctx context.Context, w *Worker, jobType Type[struct{}], interval time.Duration, handle func(ctx context.Context) error) error {
+	queue := NewQueue(w.store)
+	Register(w, jobType, func(ctx context.Context, payload struct{}) error {
+		err := scheduleNextRun(ctx, queue, jobType, interval)
+		if err != nil {
+			return err
+		}
+		return handle(ctx)
+	})
+	return scheduleNextRun(ctx, queue, jobType, interval)
+}
+
+func scheduleNextRun(ctx context.Context, queue *Queue, jobType Type[struct{}], interval time.Duration) error {
+	runAt := time.Now().UTC().Truncate(interval).Add(interval)
+	job := models.Job{
+		Id:          uuid.NewSHA1(recurringNamespace, []byte(fmt.Sprintf("%s@%s", jobType.Name, runAt.Format(time.RFC3339Nano)))),
+		Type:        jobType.Name,
+		Payload:     []byte("{}"),
+		MaxAttempts: DefaultMaxAttempts,
+	}
+	_, err := queue.store.CreateOnce(ctx, job, runAt)
+	if err != nil {
+		return fmt.Errorf("failed to schedule the next %s job: %w", jobType.Name, err)
+	}
+	return nil
+}
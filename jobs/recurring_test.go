@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+var tick = Type[struct{}]{Name: "tick"}
+
+func TestRecurringJobsRunOnceEveryInterval(t *testing.T) {
+	ctx := context.Background()
+	jobs := store.NewMemory().Jobs
+	interval := 100 * time.Millisecond
+	ticks := 0
+	// Every server registers the job, but each run is only queued and done once
+	workers := make([]*Worker, 0, 2)
+	for i := 0; i < 2; i++ {
+		worker := NewWorker(jobs, 1, time.Second, time.Minute)
+		err := RegisterRecurring(ctx, worker, tick, interval, func(ctx context.Context) error {
+			ticks++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to register recurring job: %v", err)
+		}
+		workers = append(workers, worker)
+	}
+	queue := NewQueue(jobs)
+	active, err := tick.Active(ctx, queue)
+	if err != nil || len(active) != 1 {
+		t.Fatalf("expected one run to be queued, got %v, %v", active, err)
+	}
+
+	time.Sleep(interval)
+	for _, worker := range workers {
+		runPending(t, worker)
+	}
+	if ticks != 1 {
+		t.Fatalf("expected the job to run once, got %d", ticks)
+	}
+	active, err = tick.Active(ctx, queue)
+	if err != nil || len(active) != 1 {
+		t.Fatalf("expected the run to queue the next one, got %v, %v", active, err)
+	}
+}
@@ -0,0 +1,208 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+// Backoff after the first failed attempt, doubling after every one after that up to maxBackoff
+const (
+	firstBackoff = 10 * time.Second
+	maxBackoff   = time.Hour
+)
+
+// Backoff is how long to wait before trying a job again after attempts failed attempts
+func Backoff(attempts int) time.Duration {
+	backoff := firstBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks a handler's error as one that trying again won't fix, so the job goes straight to
+// dead instead of being retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type handler func(ctx context.Context, payload json.RawMessage) error
+
+type Worker struct {
+	store        store.JobRepository
+	handlers     map[string]handler
+	concurrency  int
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// NewWorker returns a worker that runs concurrency jobs at a time, checking for due jobs every
+// pollInterval when there's nothing to do. Each job is stopped after timeout, and a job that has
+// been running for longer than that is picked up again, since its worker must have stopped.
+func NewWorker(store store.JobRepository, concurrency int, pollInterval time.Duration, timeout time.Duration) *Worker {
+	return &Worker{
+		store:        store,
+		handlers:     make(map[string]handler),
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		timeout:      timeout,
+	}
+}
+
+// Register makes the worker run jobs of jobType with handle. Jobs whose payload doesn't decode are
+// dead without being retried.
+func Register[T any](w *Worker, jobType Type[T], handle func(ctx context.Context, payload T) error) {
+	w.handlers[jobType.Name] = func(ctx context.Context, encoded json.RawMessage) error {
+		var payload T
+		err := json.Unmarshal(encoded, &payload)
+		if err != nil {
+			return Permanent(fmt.Errorf("failed to decode payload: %w", err))
+		}
+		return handle(ctx, payload)
+	}
+}
+
+// Run works through jobs until ctx is cancelled, then waits for the jobs that are running to stop
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	for {
+		worked, err := w.work(ctx)
+		if err != nil {
+			log.Printf("Failed to work on jobs: %v", err)
+		}
+		if worked && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// RunPending runs jobs until none are due
+func (w *Worker) RunPending(ctx context.Context) error {
+	for {
+		worked, err := w.work(ctx)
+		if err != nil || !worked {
+			return err
+		}
+	}
+}
+
+// work claims a due job and runs it. It returns false if there was nothing to claim.
+func (w *Worker) work(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	job, err := w.store.Claim(ctx, now, now.Add(-w.timeout))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	err = w.run(ctx, job)
+	recorded, err := w.record(job, err)
+	if err != nil {
+		return true, fmt.Errorf("failed to record job %s: %w", job.Id, err)
+	}
+	if !recorded {
+		log.Printf("Job %s (%s) was claimed again while attempt %d ran, so how it went is dropped", job.Id, job.Type, job.Attempts)
+	}
+	return true, nil
+}
+
+// record saves how the attempt of job went, unless the job has been claimed again since. It is
+// recorded even if the server is shutting down, so the job isn't run again.
+func (w *Worker) record(job *models.Job, err error) (bool, error) {
+	recordCtx := context.Background()
+	finishedAt := time.Now().UTC()
+	if err == nil {
+		return w.store.Succeed(recordCtx, *job, finishedAt)
+	}
+	var permanent *permanentError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		log.Printf("Job %s (%s) is dead after %d attempts: %v", job.Id, job.Type, job.Attempts, err)
+		return w.store.Fail(recordCtx, *job, err.Error(), nil, finishedAt)
+	}
+	retryAt := finishedAt.Add(Backoff(job.Attempts))
+	return w.store.Fail(recordCtx, *job, err.Error(), &retryAt, finishedAt)
+}
+
+// run calls the job's handler, turning a panic into an error so one bad job can't take the server
+// down
+func (w *Worker) run(ctx context.Context, job *models.Job) (err error) {
+	handle, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler is registered for %s jobs", job.Type))
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("handler panicked: %v", recovered)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, runningJobKey{}, runningJob{store: w.store, id: job.Id.String()})
+	return handle(ctx, job.Payload)
+}
+
+type runningJobKey struct{}
+
+type runningJob struct {
+	store store.JobRepository
+	id    string
+}
+
+// SetProgress records how far along the job running in ctx is, so whoever is waiting on it can
+// follow along. It does nothing outside of a job's handler.
+func SetProgress(ctx context.Context, progress any) error {
+	job, ok := ctx.Value(runningJobKey{}).(runningJob)
+	if !ok {
+		return nil
+	}
+	encoded, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+	return job.store.SetProgress(ctx, job.id, encoded)
+}
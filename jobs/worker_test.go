@@ -0,0 +1,226 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+var greet = Type[greeting]{Name: "greet"}
+
+func expectCode(t *testing.T, err error, code controllers.ErrorCode) {
+	t.Helper()
+	var controllerError *controllers.Error
+	if !errors.As(err, &controllerError) || controllerError.Code != code {
+		t.Fatalf("expected %s error, got %v", code, err)
+	}
+}
+
+func newTestQueue(t *testing.T, handle func(ctx context.Context, payload greeting) error) (*Queue, *Worker) {
+	t.Helper()
+	jobs := store.NewMemory().Jobs
+	worker := NewWorker(jobs, 1, time.Second, time.Minute)
+	Register(worker, greet, handle)
+	return NewQueue(jobs), worker
+}
+
+func enqueue(t *testing.T, queue *Queue, options ...Option) *models.Job {
+	t.Helper()
+	job, err := greet.Enqueue(context.Background(), queue, greeting{Name: "Ada"}, options...)
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	return job
+}
+
+func runPending(t *testing.T, worker *Worker) {
+	t.Helper()
+	err := worker.RunPending(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run jobs: %v", err)
+	}
+}
+
+func getJob(t *testing.T, queue *Queue, jobId string) *models.Job {
+	t.Helper()
+	job, err := queue.GetJob(context.Background(), jobId)
+	if err != nil {
+		t.Fatalf("failed to get job: %v", err)
+	}
+	return job
+}
+
+func TestWorkerRunsTypedHandlers(t *testing.T) {
+	greeted := make([]string, 0)
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		greeted = append(greeted, payload.Name)
+		return nil
+	})
+	job := enqueue(t, queue)
+	if job.Status != models.JobQueued {
+		t.Fatalf("expected a new job to be queued, got %s", job.Status)
+	}
+
+	runPending(t, worker)
+	if len(greeted) != 1 || greeted[0] != "Ada" {
+		t.Fatalf("expected the handler to get the payload once, got %v", greeted)
+	}
+	job = getJob(t, queue, job.Id.String())
+	if job.Status != models.JobSucceeded || job.Attempts != 1 || job.FinishedAt == nil {
+		t.Fatalf("expected the job to have succeeded on the first attempt, got %+v", job)
+	}
+}
+
+func TestFailedJobsAreRetriedWithBackoff(t *testing.T) {
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		return errors.New("mail server is down")
+	})
+	job := enqueue(t, queue)
+
+	before := time.Now().UTC()
+	runPending(t, worker)
+	job = getJob(t, queue, job.Id.String())
+	if job.Status != models.JobQueued || job.Attempts != 1 {
+		t.Fatalf("expected the job to be queued again after one attempt, got %+v", job)
+	}
+	if job.LastError == nil || *job.LastError != "mail server is down" {
+		t.Fatalf("expected the job to keep its error, got %v", job.LastError)
+	}
+	runAt, err := time.Parse(time.RFC3339Nano, job.RunAt)
+	if err != nil {
+		t.Fatalf("failed to parse run_at: %v", err)
+	}
+	if runAt.Before(before.Add(Backoff(1))) {
+		t.Fatalf("expected the job to wait at least %s, got run_at %s", Backoff(1), job.RunAt)
+	}
+}
+
+func TestBackoffDoublesUpToAnHour(t *testing.T) {
+	for attempts, expected := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 4: 80 * time.Second, 20: time.Hour} {
+		if backoff := Backoff(attempts); backoff != expected {
+			t.Errorf("expected %s after %d attempts, got %s", expected, attempts, backoff)
+		}
+	}
+}
+
+func TestDeadJobsCanBeRetried(t *testing.T) {
+	fail := true
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		if fail {
+			return errors.New("mail server is down")
+		}
+		return nil
+	})
+	ctx := context.Background()
+	job := enqueue(t, queue, MaxAttempts(1))
+	jobId := job.Id.String()
+
+	_, err := queue.RetryJob(ctx, jobId)
+	expectCode(t, err, controllers.CodeConflict)
+
+	runPending(t, worker)
+	job = getJob(t, queue, jobId)
+	if job.Status != models.JobDead {
+		t.Fatalf("expected the job to be dead after its last attempt, got %s", job.Status)
+	}
+	dead, err := queue.ListJobs(ctx, models.JobDead, 10)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("expected the job to be listed as dead, got %v, %v", dead, err)
+	}
+
+	fail = false
+	job, err = queue.RetryJob(ctx, jobId)
+	if err != nil {
+		t.Fatalf("failed to retry job: %v", err)
+	}
+	if job.Status != models.JobQueued || job.Attempts != 0 {
+		t.Fatalf("expected the retried job to be queued with its attempts back, got %+v", job)
+	}
+	runPending(t, worker)
+	job = getJob(t, queue, jobId)
+	if job.Status != models.JobSucceeded {
+		t.Fatalf("expected the retried job to succeed, got %s", job.Status)
+	}
+}
+
+func TestDelayedJobsWaitUntilTheyAreDue(t *testing.T) {
+	ran := false
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		ran = true
+		return nil
+	})
+	job := enqueue(t, queue, After(time.Hour))
+
+	runPending(t, worker)
+	if ran {
+		t.Fatal("expected the job not to run before it is due")
+	}
+	job = getJob(t, queue, job.Id.String())
+	if job.Status != models.JobQueued || job.Attempts != 0 {
+		t.Fatalf("expected the job to still be queued, got %+v", job)
+	}
+}
+
+func TestJobsThatCantRunAreDead(t *testing.T) {
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		return nil
+	})
+	ctx := context.Background()
+	badPayload, err := queue.enqueue(ctx, greet.Name, "not a greeting")
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	unknown, err := queue.enqueue(ctx, "unknown", greeting{Name: "Ada"})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	permanent, err := Type[greeting]{Name: "permanent"}.Enqueue(ctx, queue, greeting{Name: "Ada"})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	Register(worker, Type[greeting]{Name: "permanent"}, func(ctx context.Context, payload greeting) error {
+		return Permanent(errors.New("no such user"))
+	})
+
+	runPending(t, worker)
+	for _, job := range []*models.Job{badPayload, unknown, permanent} {
+		job = getJob(t, queue, job.Id.String())
+		if job.Status != models.JobDead || job.Attempts != 1 {
+			t.Errorf("expected %s job to be dead without being retried, got %+v", job.Type, job)
+		}
+	}
+}
+
+func TestAttemptsThatLostTheirJobDontRecordIt(t *testing.T) {
+	var worker *Worker
+	queue, worker := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		// The attempt ran for so long that another worker picked the job up again
+		now := time.Now().UTC()
+		_, err := worker.store.Claim(ctx, now, now.Add(time.Minute))
+		return err
+	})
+	job := enqueue(t, queue)
+
+	runPending(t, worker)
+	job = getJob(t, queue, job.Id.String())
+	if job.Status != models.JobRunning || job.Attempts != 2 || job.FinishedAt != nil {
+		t.Fatalf("expected the job to still be running its second attempt, got %+v", job)
+	}
+}
+
+func TestListJobsChecksTheStatus(t *testing.T) {
+	queue, _ := newTestQueue(t, func(ctx context.Context, payload greeting) error {
+		return nil
+	})
+	_, err := queue.ListJobs(context.Background(), "finished", 10)
+	expectCode(t, err, controllers.CodeValidationFailed)
+}
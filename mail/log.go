@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSender doesn't send anything. It appends each message to a file, or writes it to the server
+// log if there's no file, so links in development emails can be copied out by hand.
+type LogSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewLogSender(path string) *LogSender {
+	return &LogSender{path: path}
+}
+
+func (s *LogSender) Send(ctx context.Context, message Message) error {
+	if s.path == "" {
+		log.Printf("mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %w", err)
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), message.To, message.Subject, message.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Package mail sends the emails the server needs to, like organization invitations. Which Sender is
+// used comes from MAIL_SENDER, so development doesn't need a real mail server.
+package mail
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewSender returns the Sender picked by cfg.Mail.Sender, either "smtp" or "log"
+func NewSender(cfg *config.Config) (Sender, error) {
+	switch cfg.Mail.Sender {
+	case "smtp":
+		if cfg.Mail.SMTP.Host == "" {
+			return nil, fmt.Errorf("SMTP_HOST must be set to send mail over SMTP")
+		}
+		return NewSMTPSender(cfg), nil
+	case "log", "":
+		return NewLogSender(cfg.Mail.File), nil
+	}
+	return nil, fmt.Errorf("unknown mail sender %q", cfg.Mail.Sender)
+}
@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+)
+
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPSender(cfg *config.Config) *SMTPSender {
+	sender := &SMTPSender{
+		addr: net.JoinHostPort(cfg.Mail.SMTP.Host, strconv.Itoa(cfg.Mail.SMTP.Port)),
+		from: cfg.Mail.From,
+	}
+	if cfg.Mail.SMTP.Username != "" {
+		sender.auth = smtp.PlainAuth("", cfg.Mail.SMTP.Username, cfg.Mail.SMTP.Password, cfg.Mail.SMTP.Host)
+	}
+	return sender
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM address %q: %w", s.from, err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", message.To, err)
+	}
+	// net/smtp doesn't take a context, so the best we can do is not start sending once it's done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err = smtp.SendMail(s.addr, s.auth, from.Address, []string{to.Address}, format(from.String(), to.String(), message))
+	if err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", to.Address, err)
+	}
+	return nil
+}
+
+// format writes message as a plain text email
+func format(from string, to string, message Message) []byte {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", from)
+	fmt.Fprintf(&builder, "To: %s\r\n", to)
+	// the subject can include an organization's name, so it mustn't be able to add headers
+	fmt.Fprintf(&builder, "Subject: %s\r\n", strings.NewReplacer("\r", "", "\n", " ").Replace(message.Subject))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(builder.String())
+}
@@ -35,17 +35,28 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
 	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/mail"
 	"github.com/Sync-Space-49/syncspace-server/routers"
+	"github.com/Sync-Space-49/syncspace-server/store"
 
 	"github.com/rs/zerolog/log"
 )
 
+// scopesLockKey is the Postgres advisory lock servers take turns changing the Auth0 API's
+// permissions with
+const scopesLockKey = 4901
+
 func main() {
 	if err := run(); err != nil {
 		log.Err(err).Msg("failed to run server")
@@ -63,6 +74,31 @@ func run() error {
 		return err
 	}
 
+	auth.ShareScopesLock(func() (func(), error) {
+		return db.AdvisoryLock(context.Background(), scopesLockKey)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sender, err := mail.NewSender(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up the mail sender: %w", err)
+	}
+	store := store.NewPostgres(db)
+	boards := board.NewController(cfg, store)
+	organizations := organization.NewController(cfg, store)
+	worker := jobs.NewWorker(store.Jobs, cfg.Jobs.Concurrency, cfg.Jobs.PollInterval, cfg.Jobs.Timeout)
+	jobs.RegisterHandlers(worker, sender)
+	jobs.Register(worker, board.GenerateBoard, boards.GenerateBoard)
+	jobs.Register(worker, board.ProvisionBoard, boards.ProvisionQueuedBoard)
+	jobs.Register(worker, organization.ProvisionOrganization, organizations.ProvisionQueuedOrganization)
+	err = scheduleRecurringJobs(ctx, cfg, worker, boards, organizations)
+	if err != nil {
+		return err
+	}
+	go worker.Run(ctx)
+
 	server := &http.Server{
 		Addr:    cfg.APIHost,
 		Handler: routers.NewAPI(cfg, db),
@@ -75,3 +111,32 @@ func run() error {
 
 	return nil
 }
+
+// scheduleRecurringJobs registers the cleanup work that runs every so often, as jobs so only one
+// server does each run
+func scheduleRecurringJobs(ctx context.Context, cfg *config.Config, worker *jobs.Worker, boards *board.Controller, organizations *organization.Controller) error {
+	resumeInterval := cfg.Auth0.ProvisioningResumeInterval
+	err := jobs.RegisterRecurring(ctx, worker, board.PurgeTrash, cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+		return boards.PurgeTrash(ctx, cfg.Trash.RetentionPeriod)
+	})
+	if err != nil {
+		return err
+	}
+	// Resources left provisioning for longer than an interval are picked up by the next run
+	err = jobs.RegisterRecurring(ctx, worker, organization.ResumeProvisioning, resumeInterval, func(ctx context.Context) error {
+		return organizations.ResumeProvisioning(ctx, resumeInterval)
+	})
+	if err != nil {
+		return err
+	}
+	err = jobs.RegisterRecurring(ctx, worker, board.ResumeProvisioning, resumeInterval, func(ctx context.Context) error {
+		return boards.ResumeProvisioning(ctx, resumeInterval)
+	})
+	if err != nil {
+		return err
+	}
+	if cfg.Auth0.ReconcileInterval > 0 {
+		return jobs.RegisterRecurring(ctx, worker, organization.ReconcileAccess, cfg.Auth0.ReconcileInterval, organizations.ReconcileAccess)
+	}
+	return nil
+}
@@ -25,29 +25,48 @@ type Card struct {
 	Points      string    `db:"points" json:"points"`
 	Position    int       `db:"position" json:"position"`
 	StackId     uuid.UUID `db:"stack_id" json:"stack_id"`
+	DeletedAt   *string   `db:"deleted_at" json:"deleted_at"`
+	Version     int       `db:"version" json:"version"`
 }
 
 type Stack struct {
-	Id       uuid.UUID `db:"id" json:"id"`
-	Title    string    `db:"title" json:"title"`
-	Position int       `db:"position" json:"position"`
-	PanelId  uuid.UUID `db:"panel_id" json:"panel_id"`
+	Id        uuid.UUID `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	Position  int       `db:"position" json:"position"`
+	PanelId   uuid.UUID `db:"panel_id" json:"panel_id"`
+	DeletedAt *string   `db:"deleted_at" json:"deleted_at"`
+	Version   int       `db:"version" json:"version"`
 }
 type Panel struct {
-	Id       uuid.UUID `db:"id" json:"id"`
-	Title    string    `db:"title" json:"title"`
-	Position int       `db:"position" json:"position"`
-	BoardId  uuid.UUID `db:"board_id" json:"board_id"`
+	Id        uuid.UUID `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	Position  int       `db:"position" json:"position"`
+	BoardId   uuid.UUID `db:"board_id" json:"board_id"`
+	DeletedAt *string   `db:"deleted_at" json:"deleted_at"`
+	Version   int       `db:"version" json:"version"`
 }
 type Board struct {
-	Id             uuid.UUID `db:"id" json:"id"`
-	OwnerId        string    `db:"owner_id" json:"owner_id"`
-	Title          string    `db:"title" json:"title"`
-	Description    string    `db:"description" json:"description"`
-	CreatedAt      string    `db:"created_at" json:"created_at"`
-	ModifiedAt     string    `db:"modified_at" json:"modified_at"`
-	IsPrivate      bool      `db:"is_private" json:"is_private"`
-	OrganizationId uuid.UUID `db:"organization_id" json:"organization_id"`
+	Id                 uuid.UUID `db:"id" json:"id"`
+	OwnerId            string    `db:"owner_id" json:"owner_id"`
+	Title              string    `db:"title" json:"title"`
+	Description        string    `db:"description" json:"description"`
+	CreatedAt          string    `db:"created_at" json:"created_at"`
+	ModifiedAt         string    `db:"modified_at" json:"modified_at"`
+	IsPrivate          bool      `db:"is_private" json:"is_private"`
+	OrganizationId     uuid.UUID `db:"organization_id" json:"organization_id"`
+	DeletedAt          *string   `db:"deleted_at" json:"deleted_at"`
+	ArchivedAt         *string   `db:"archived_at" json:"archived_at"`
+	Version            int       `db:"version" json:"version"`
+	ProvisioningStatus string    `db:"provisioning_status" json:"provisioning_status"`
+	// ProvisioningClaimedAt is when provisioning was last taken over to be resumed or undone
+	ProvisioningClaimedAt *string `db:"provisioning_claimed_at" json:"-"`
+}
+
+// BoardTrash holds everything on a board that has been soft deleted but not yet purged
+type BoardTrash struct {
+	Panels []Panel `json:"panels"`
+	Stacks []Stack `json:"stacks"`
+	Cards  []Card  `json:"cards"`
 }
 
 type CompleteStack struct {
@@ -55,6 +74,7 @@ type CompleteStack struct {
 	Title    string         `db:"title" json:"title"`
 	Position int            `db:"position" json:"position"`
 	PanelId  uuid.UUID      `db:"panel_id" json:"panel_id"`
+	Version  int            `db:"version" json:"version"`
 	Cards    []CompleteCard `json:"cards"`
 }
 
@@ -63,6 +83,7 @@ type CompletePanel struct {
 	Title    string          `db:"title" json:"title"`
 	Position int             `db:"position" json:"position"`
 	BoardId  uuid.UUID       `db:"board_id" json:"board_id"`
+	Version  int             `db:"version" json:"version"`
 	Stacks   []CompleteStack `json:"stacks"`
 }
 
@@ -74,6 +95,8 @@ type CompleteBoard struct {
 	CreatedAt   string          `db:"created_at" json:"created_at"`
 	ModifiedAt  string          `db:"modified_at" json:"modified_at"`
 	IsPrivate   bool            `db:"is_private" json:"is_private"`
+	ArchivedAt  *string         `db:"archived_at" json:"archived_at"`
+	Version     int             `db:"version" json:"version"`
 	Panels      []CompletePanel `json:"panels"`
 }
 
@@ -84,6 +107,7 @@ type CompleteCard struct {
 	Points      string    `db:"points" json:"points"`
 	Position    int       `db:"position" json:"position"`
 	StackId     uuid.UUID `db:"stack_id" json:"stack_id"`
+	Version     int       `db:"version" json:"version"`
 	Assignments []User    `json:"assignments"`
 }
 
@@ -120,6 +144,8 @@ type DetailedAssignedCard struct {
 	Position    int       `db:"position" json:"position"`
 	StackID     uuid.UUID `db:"stack_id" json:"stack_id"`
 	Points      string    `db:"points" json:"points"`
+	DeletedAt   *string   `db:"deleted_at" json:"deleted_at"`
+	Version     int       `db:"version" json:"version"`
 	PanelID     uuid.UUID `db:"panel_id" json:"panel_id"`
 	BoardID     uuid.UUID `db:"board_id" json:"board_id"`
 	OrgID       uuid.UUID `db:"org_id" json:"org_id"`
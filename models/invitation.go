@@ -0,0 +1,61 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// Invitation asks whoever can read Email to join an organization. The token that answers it is only
+// ever sent in the email; TokenHash changes every time the invitation is resent.
+type Invitation struct {
+	Id             uuid.UUID `db:"id" json:"id"`
+	OrganizationId uuid.UUID `db:"organization_id" json:"organization_id"`
+	Email          string    `db:"email" json:"email"`
+	// RoleId is the role given on top of the organization's member role, if any
+	RoleId      *string `db:"role_id" json:"role_id"`
+	InvitedBy   string  `db:"invited_by" json:"invited_by"`
+	TokenHash   string  `db:"token_hash" json:"-"`
+	Status      string  `db:"status" json:"status"`
+	CreatedAt   string  `db:"created_at" json:"created_at"`
+	ExpiresAt   string  `db:"expires_at" json:"expires_at"`
+	RespondedAt *string `db:"responded_at" json:"responded_at"`
+}
+
+// InviteLink lets anyone with the link join an organization, until it expires, is deleted or has
+// been used MaxUses times
+type InviteLink struct {
+	Id             uuid.UUID `db:"id" json:"id"`
+	OrganizationId uuid.UUID `db:"organization_id" json:"organization_id"`
+	// RoleId is the role given on top of the organization's member role, if any
+	RoleId    *string `db:"role_id" json:"role_id"`
+	CreatedBy string  `db:"created_by" json:"created_by"`
+	TokenHash string  `db:"token_hash" json:"-"`
+	MaxUses   *int    `db:"max_uses" json:"max_uses"`
+	Uses      int     `db:"uses" json:"uses"`
+	CreatedAt string  `db:"created_at" json:"created_at"`
+	ExpiresAt *string `db:"expires_at" json:"expires_at"`
+}
+
+// NewInviteLink is only returned when a link is created, since the raw token isn't stored
+type NewInviteLink struct {
+	InviteLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// OrganizationDomain lets users with a verified email at Domain join the organization without an
+// invitation, once the organization has shown it owns Domain by publishing VerificationRecord as a
+// DNS TXT record
+type OrganizationDomain struct {
+	OrganizationId     uuid.UUID `db:"organization_id" json:"organization_id"`
+	Domain             string    `db:"domain" json:"domain"`
+	RoleId             *string   `db:"role_id" json:"role_id"`
+	VerificationRecord string    `db:"verification_record" json:"verification_record"`
+	VerifiedAt         *string   `db:"verified_at" json:"verified_at"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	CreatedAt          string    `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	// JobDead is for jobs that failed MaxAttempts times. They stay until they are retried by hand.
+	JobDead = "dead"
+)
+
+// Job is work the background workers do outside of a request. Type picks the handler, which is
+// given Payload.
+type Job struct {
+	Id          uuid.UUID       `db:"id" json:"id"`
+	Type        string          `db:"type" json:"type"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	Status      string          `db:"status" json:"status"`
+	Attempts    int             `db:"attempts" json:"attempts"`
+	MaxAttempts int             `db:"max_attempts" json:"max_attempts"`
+	// RunAt is when the job is picked up, moved later after each failed attempt
+	RunAt     string  `db:"run_at" json:"run_at"`
+	LockedAt  *string `db:"locked_at" json:"locked_at"`
+	LastError *string `db:"last_error" json:"last_error"`
+	// Progress is whatever the handler last reported about how far along it is
+	Progress   json.RawMessage `db:"progress" json:"progress"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	FinishedAt *string         `db:"finished_at" json:"finished_at"`
+}
@@ -18,10 +18,23 @@ func NewOrganizationController(cfg *config.Config, db *db.DB) *OrganizationContr
 	}
 }
 
+// Provisioning statuses of organizations and boards. They are provisioning until their Auth0 roles
+// and permissions have been set up, and failed if that couldn't be finished or undone, in which
+// case they are cleaned up later.
+const (
+	ProvisioningInProgress = "provisioning"
+	ProvisioningReady      = "ready"
+	ProvisioningFailed     = "failed"
+)
+
 type Organization struct {
-	Id          uuid.UUID `db:"id" json:"id"`
-	OwnerId     string    `db:"owner_id" json:"owner_id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	AiEnabled   bool      `db:"ai_enabled" json:"ai_enabled"`
+	Id                 uuid.UUID `db:"id" json:"id"`
+	OwnerId            string    `db:"owner_id" json:"owner_id"`
+	Name               string    `db:"name" json:"name"`
+	Description        string    `db:"description" json:"description"`
+	AiEnabled          bool      `db:"ai_enabled" json:"ai_enabled"`
+	CreatedAt          string    `db:"created_at" json:"created_at"`
+	ProvisioningStatus string    `db:"provisioning_status" json:"provisioning_status"`
+	// ProvisioningClaimedAt is when provisioning was last taken over to be resumed or undone
+	ProvisioningClaimedAt *string `db:"provisioning_claimed_at" json:"-"`
 }
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Optional is a field in a PATCH request. Set is false when the field was left out of the request,
+// which is how a PATCH tells "leave this alone" apart from "clear this" (Null, or an empty value).
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{Set: true, Value: value}
+}
+
+// Or returns the patched value if the field was set, otherwise fallback. A field set to null gives
+// the zero value.
+func (o Optional[T]) Or(fallback T) T {
+	if !o.Set {
+		return fallback
+	}
+	return o.Value
+}
+
+// UnmarshalJSON is only called for fields that are in the body, so absent fields stay unset
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// UnmarshalForm sets the field from a form value, for clients that send PATCH requests as forms
+func (o *Optional[T]) UnmarshalForm(value string) error {
+	o.Set = true
+	if stringValue, ok := any(&o.Value).(*string); ok {
+		*stringValue = value
+		return nil
+	}
+	return json.Unmarshal([]byte(value), &o.Value)
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is returned when a request body fails validation, with one entry per bad field
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fmt.Sprintf("%s: %s", fieldError.Field, fieldError.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+func (e *FieldErrors) add(field string, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+func (e *FieldErrors) notNull(field string, set bool, null bool) {
+	if set && null {
+		e.add(field, "can't be null")
+	}
+}
+
+func (e *FieldErrors) title(field string, title Optional[string]) {
+	e.notNull(field, title.Set, title.Null)
+	if title.Set && !title.Null && strings.TrimSpace(title.Value) == "" {
+		e.add(field, "can't be empty")
+	}
+	e.maxLength(field, title, 255)
+}
+
+func (e *FieldErrors) maxLength(field string, value Optional[string], max int) {
+	if value.Set && len(value.Value) > max {
+		e.add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+func (e *FieldErrors) position(field string, position Optional[int]) {
+	e.notNull(field, position.Set, position.Null)
+	if position.Set && position.Value < 0 {
+		e.add(field, "can't be negative")
+	}
+}
+
+func (e *FieldErrors) result() error {
+	if len(*e) == 0 {
+		return nil
+	}
+	return *e
+}
+
+type BoardPatch struct {
+	Title       Optional[string] `json:"title"`
+	Description Optional[string] `json:"description"`
+	IsPrivate   Optional[bool]   `json:"is_private"`
+	OwnerId     Optional[string] `json:"owner_id"`
+}
+
+func (p BoardPatch) Validate() error {
+	errs := FieldErrors{}
+	errs.title("title", p.Title)
+	errs.notNull("is_private", p.IsPrivate.Set, p.IsPrivate.Null)
+	errs.notNull("owner_id", p.OwnerId.Set, p.OwnerId.Null)
+	if p.OwnerId.Set && !p.OwnerId.Null && p.OwnerId.Value == "" {
+		errs.add("owner_id", "can't be empty")
+	}
+	errs.maxLength("owner_id", p.OwnerId, 64)
+	return errs.result()
+}
+
+type PanelPatch struct {
+	Title    Optional[string] `json:"title"`
+	Position Optional[int]    `json:"position"`
+}
+
+func (p PanelPatch) Validate() error {
+	errs := FieldErrors{}
+	errs.title("title", p.Title)
+	errs.position("position", p.Position)
+	return errs.result()
+}
+
+type StackPatch struct {
+	Title    Optional[string] `json:"title"`
+	Position Optional[int]    `json:"position"`
+}
+
+func (p StackPatch) Validate() error {
+	errs := FieldErrors{}
+	errs.title("title", p.Title)
+	errs.position("position", p.Position)
+	return errs.result()
+}
+
+type CardPatch struct {
+	Title       Optional[string] `json:"title"`
+	Description Optional[string] `json:"description"`
+	Points      Optional[string] `json:"points"`
+	Position    Optional[int]    `json:"position"`
+	StackId     Optional[string] `json:"stack_id"`
+}
+
+func (p CardPatch) Validate() error {
+	errs := FieldErrors{}
+	errs.title("title", p.Title)
+	errs.maxLength("points", p.Points, 30)
+	errs.position("position", p.Position)
+	errs.notNull("stack_id", p.StackId.Set, p.StackId.Null)
+	if p.StackId.Set && !p.StackId.Null {
+		if _, err := uuid.Parse(p.StackId.Value); err != nil {
+			errs.add("stack_id", "must be a valid id")
+		}
+	}
+	return errs.result()
+}
+
+type OrganizationPatch struct {
+	Name        Optional[string] `json:"name"`
+	Description Optional[string] `json:"description"`
+	AiEnabled   Optional[bool]   `json:"ai_enabled"`
+}
+
+func (p OrganizationPatch) Validate() error {
+	errs := FieldErrors{}
+	errs.title("name", p.Name)
+	errs.notNull("ai_enabled", p.AiEnabled.Set, p.AiEnabled.Null)
+	return errs.result()
+}
@@ -0,0 +1,26 @@
+package models
+
+import "github.com/google/uuid"
+
+// BoardShare lets anyone with its token view a board without an account, until it expires or is
+// revoked
+type BoardShare struct {
+	Id                uuid.UUID `db:"id" json:"id"`
+	BoardId           uuid.UUID `db:"board_id" json:"board_id"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	TokenHash         string    `db:"token_hash" json:"-"`
+	PasswordHash      *string   `db:"password_hash" json:"-"`
+	PasswordProtected bool      `db:"-" json:"password_protected"`
+	CreatedAt         string    `db:"created_at" json:"created_at"`
+	ExpiresAt         *string   `db:"expires_at" json:"expires_at"`
+	RevokedAt         *string   `db:"revoked_at" json:"revoked_at"`
+	AccessCount       int       `db:"access_count" json:"access_count"`
+	LastAccessedAt    *string   `db:"last_accessed_at" json:"last_accessed_at"`
+}
+
+// NewBoardShare is only returned when a share is created, since the raw token isn't stored
+type NewBoardShare struct {
+	BoardShare
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
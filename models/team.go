@@ -0,0 +1,30 @@
+package models
+
+import "github.com/google/uuid"
+
+// Board roles a team can be given, named after the board roles created with the board
+const (
+	TeamBoardMember = "member"
+	TeamBoardOwner  = "owner"
+)
+
+// Team is a named group of organization members. Its members are the users holding the team's
+// Auth0 role, so adding the team to a board only has to give that role the board's permissions.
+type Team struct {
+	Id             uuid.UUID `db:"id" json:"id"`
+	OrganizationId uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Description    string    `db:"description" json:"description"`
+	RoleId         string    `db:"role_id" json:"role_id"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	CreatedAt      string    `db:"created_at" json:"created_at"`
+}
+
+// TeamBoard gives every member of a team, now or later, one of the board's roles
+type TeamBoard struct {
+	TeamId    uuid.UUID `db:"team_id" json:"team_id"`
+	BoardId   uuid.UUID `db:"board_id" json:"board_id"`
+	Role      string    `db:"role" json:"role"`
+	AddedBy   string    `db:"added_by" json:"added_by"`
+	CreatedAt string    `db:"created_at" json:"created_at"`
+}
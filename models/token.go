@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PermissionList is stored as a single space separated column, the same way scopes are written in
+// an access token
+type PermissionList []string
+
+func (p PermissionList) Value() (driver.Value, error) {
+	return strings.Join(p, " "), nil
+}
+
+func (p *PermissionList) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into PermissionList", src)
+	}
+	*p = strings.Fields(value)
+	return nil
+}
+
+// PersonalAccessToken lets scripts act as the user who created it, limited to Permissions
+type PersonalAccessToken struct {
+	Id          uuid.UUID      `db:"id" json:"id"`
+	UserId      string         `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+	TokenHash   string         `db:"token_hash" json:"-"`
+	Permissions PermissionList `db:"permissions" json:"permissions"`
+	CreatedAt   string         `db:"created_at" json:"created_at"`
+	ExpiresAt   *string        `db:"expires_at" json:"expires_at"`
+	LastUsedAt  *string        `db:"last_used_at" json:"last_used_at"`
+}
+
+// APIKey belongs to an organization rather than a person, so it keeps working when whoever created
+// it leaves
+type APIKey struct {
+	Id             uuid.UUID      `db:"id" json:"id"`
+	OrganizationId uuid.UUID      `db:"organization_id" json:"organization_id"`
+	CreatedBy      string         `db:"created_by" json:"created_by"`
+	Name           string         `db:"name" json:"name"`
+	TokenHash      string         `db:"token_hash" json:"-"`
+	Permissions    PermissionList `db:"permissions" json:"permissions"`
+	CreatedAt      string         `db:"created_at" json:"created_at"`
+	ExpiresAt      *string        `db:"expires_at" json:"expires_at"`
+	LastUsedAt     *string        `db:"last_used_at" json:"last_used_at"`
+}
+
+// NewPersonalAccessToken is only returned when a token is created, since the raw token isn't stored
+type NewPersonalAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
+type NewAPIKey struct {
+	APIKey
+	Token string `json:"token"`
+}
@@ -9,6 +9,8 @@ func CopyToCompleteBoard(source Board) CompleteBoard {
 	dest.CreatedAt = source.CreatedAt
 	dest.ModifiedAt = source.ModifiedAt
 	dest.IsPrivate = source.IsPrivate
+	dest.ArchivedAt = source.ArchivedAt
+	dest.Version = source.Version
 	return dest
 }
 
@@ -18,6 +20,7 @@ func CopyToCompletePanel(source Panel) CompletePanel {
 	dest.Title = source.Title
 	dest.Position = source.Position
 	dest.BoardId = source.BoardId
+	dest.Version = source.Version
 	return dest
 }
 
@@ -27,6 +30,7 @@ func CopyToCompleteStack(source Stack) CompleteStack {
 	dest.Title = source.Title
 	dest.Position = source.Position
 	dest.PanelId = source.PanelId
+	dest.Version = source.Version
 	return dest
 }
 
@@ -38,6 +42,7 @@ func CopyToCompleteCard(source Card) CompleteCard {
 	dest.Points = source.Points
 	dest.Position = source.Position
 	dest.StackId = source.StackId
+	dest.Version = source.Version
 	return dest
 }
 
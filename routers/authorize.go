@@ -0,0 +1,92 @@
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+// permissions is what a route needs, written the same way as x-permissions in openapi.json: the
+// user needs at least one permission from every list, and {organizationId}, {boardId} and any other
+// path variables are filled in from the request.
+type permissions [][]string
+
+// anyOf is a list of permissions where having any one of them is enough
+func anyOf(templates ...string) []string {
+	return templates
+}
+
+// Permissions most board routes start from. Guests can only read the boards they were added to,
+// so reaching a board needs either the organization or the board itself.
+var (
+	readOrg    = anyOf("org{organizationId}:read")
+	reachBoard = anyOf("org{organizationId}:read", "org{organizationId}:board{boardId}:read")
+)
+
+func onBoard(action string) []string {
+	return anyOf(fmt.Sprintf("org{organizationId}:board{boardId}:%s", action))
+}
+
+// securedRoute is a route that needs a valid token with some permissions before its handler runs
+type securedRoute struct {
+	method      string
+	path        string
+	handler     http.HandlerFunc
+	permissions permissions
+}
+
+func handleSecured(router *mux.Router, routes []securedRoute) {
+	for _, route := range routes {
+		router.Handle(route.path, auth.EnsureValidToken()(requirePermissions(route.permissions, route.handler))).Methods(route.method)
+	}
+}
+
+var pathVariablePattern = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+func resolvePermission(template string, params map[string]string) string {
+	return pathVariablePattern.ReplaceAllStringFunc(template, func(variable string) string {
+		return params[strings.Trim(variable, "{}")]
+	})
+}
+
+// allowed reports whether the token has any of the permissions, filled in from the path the same
+// way as the route tables, for checks the tables can't make, like anyone being allowed to remove
+// themselves
+func allowed(request *http.Request, templates ...string) bool {
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	params := mux.Vars(request)
+	permissionNames := make([]string, 0, len(templates))
+	for _, template := range templates {
+		permissionNames = append(permissionNames, resolvePermission(template, params))
+	}
+	return tokenCustomClaims.HasAnyPermissions(permissionNames...)
+}
+
+// requirePermissions only lets the request through to next when the token has the permissions,
+// so handlers behind it don't check them again
+func requirePermissions(required permissions, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+		tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+		params := mux.Vars(request)
+		for _, alternatives := range required {
+			permissionNames := make([]string, 0, len(alternatives))
+			for _, template := range alternatives {
+				permissionNames = append(permissionNames, resolvePermission(template, params))
+			}
+			if !tokenCustomClaims.HasAnyPermissions(permissionNames...) {
+				writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s needs one of these permissions: %s", token.RegisteredClaims.Subject, strings.Join(permissionNames, ", "))))
+				return
+			}
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
@@ -0,0 +1,226 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+)
+
+// Every board, board role, board share and board team route and what it needs. Changing a route's permissions
+// should be a deliberate change to this list as well.
+var expectedBoardRoutePermissions = map[string]permissions{
+	"GET /api/organizations/{organizationId}/trash": {readOrg},
+
+	"GET /api/organizations/{organizationId}/boards":                      {readOrg},
+	"POST /api/organizations/{organizationId}/boards":                     {{"org{organizationId}:create_boards"}},
+	"POST /api/organizations/{organizationId}/boards/ai":                  {{"org{organizationId}:create_boards"}},
+	"GET /api/organizations/{organizationId}/boards/ai/{jobId}":           {{"org{organizationId}:create_boards"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}":            {reachBoard},
+	"PUT /api/organizations/{organizationId}/boards/{boardId}":            {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"PATCH /api/organizations/{organizationId}/boards/{boardId}":          {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}":         {reachBoard, {"org{organizationId}:board{boardId}:delete"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/details":    {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/archive":   {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/unarchive": {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/trash":      {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/restore":   {reachBoard, {"org{organizationId}:board{boardId}:delete"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/members":               {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/members":              {reachBoard, {"org{organizationId}:board{boardId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/members/{memberId}": {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/guests":               {reachBoard, {"org{organizationId}:board{boardId}:add_members"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels":                    {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels":                   {reachBoard, {"org{organizationId}:board{boardId}:create_panel"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}":          {reachBoard},
+	"PUT /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}":          {reachBoard, {"org{organizationId}:board{boardId}:update_panel"}},
+	"PATCH /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}":        {reachBoard, {"org{organizationId}:board{boardId}:update_panel"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}":       {reachBoard, {"org{organizationId}:board{boardId}:delete_panel"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/details":  {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/restore": {reachBoard, {"org{organizationId}:board{boardId}:delete_panel"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks":                    {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks":                   {reachBoard, {"org{organizationId}:board{boardId}:create_stack"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}":          {reachBoard},
+	"PUT /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}":          {reachBoard, {"org{organizationId}:board{boardId}:update_stack"}},
+	"PATCH /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}":        {reachBoard, {"org{organizationId}:board{boardId}:update_stack"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}":       {reachBoard, {"org{organizationId}:board{boardId}:delete_stack"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/details":  {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/restore": {reachBoard, {"org{organizationId}:board{boardId}:delete_stack"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards":                                 {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards":                                {reachBoard, {"org{organizationId}:board{boardId}:create_card"}},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/ai":                             {reachBoard, {"org{organizationId}:board{boardId}:create_card"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}":                        {reachBoard},
+	"PUT /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}":                        {reachBoard, {"org{organizationId}:board{boardId}:update_card"}},
+	"PATCH /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}":                      {reachBoard, {"org{organizationId}:board{boardId}:update_card"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}":                     {reachBoard, {"org{organizationId}:board{boardId}:delete_card"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}/details":                {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}/restore":               {reachBoard, {"org{organizationId}:board{boardId}:delete_card"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}/assigned":               {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}/assigned":              {reachBoard, {"org{organizationId}:board{boardId}:update_card"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks/{stackId}/cards/{cardId}/assigned/{memberId}": {reachBoard, {"org{organizationId}:board{boardId}:update_card"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/roles":                                {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/roles":                               {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/roles/permissions":                    {reachBoard},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}":                       {reachBoard},
+	"PUT /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}":                       {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}":                    {reachBoard, {"org{organizationId}:board{boardId}:update"}},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}/permissions":           {reachBoard},
+	"GET /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}/members":               {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}/members":              {reachBoard, {"org{organizationId}:board{boardId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/roles/{roleId}/members/{memberId}": {reachBoard},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/shares":              {readOrg, {"org{organizationId}:board{boardId}:update"}},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/shares":             {readOrg, {"org{organizationId}:board{boardId}:update"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/shares/{shareId}": {readOrg, {"org{organizationId}:board{boardId}:update"}},
+
+	"GET /api/organizations/{organizationId}/boards/{boardId}/teams":             {reachBoard},
+	"POST /api/organizations/{organizationId}/boards/{boardId}/teams":            {reachBoard, {"org{organizationId}:board{boardId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/boards/{boardId}/teams/{teamId}": {reachBoard, {"org{organizationId}:board{boardId}:remove_members"}},
+}
+
+// Every organization, organization role, team, invitation and credential route and what it needs
+var expectedOrganizationRoutePermissions = map[string]permissions{
+	"POST /api/organizations":                                       {},
+	"GET /api/organizations/{organizationId}":                       {readOrg},
+	"PUT /api/organizations/{organizationId}":                       {{"org{organizationId}:update"}},
+	"PATCH /api/organizations/{organizationId}":                     {{"org{organizationId}:update"}},
+	"DELETE /api/organizations/{organizationId}":                    {{"org{organizationId}:delete"}},
+	"GET /api/organizations/{organizationId}/members":               {readOrg},
+	"POST /api/organizations/{organizationId}/members":              {{"org{organizationId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/members/{memberId}": {readOrg},
+	"GET /api/organizations/{organizationId}/guests":                {readOrg},
+
+	"GET /api/organizations/{organizationId}/roles":                                {readOrg},
+	"GET /api/organizations/{organizationId}/roles/permissions":                    {readOrg},
+	"POST /api/organizations/{organizationId}/roles":                               {{"org{organizationId}:create_roles"}},
+	"GET /api/organizations/{organizationId}/roles/{roleId}":                       {readOrg},
+	"PUT /api/organizations/{organizationId}/roles/{roleId}":                       {{"org{organizationId}:edit_roles"}},
+	"DELETE /api/organizations/{organizationId}/roles/{roleId}":                    {{"org{organizationId}:delete_roles"}},
+	"GET /api/organizations/{organizationId}/roles/{roleId}/permissions":           {readOrg},
+	"GET /api/organizations/{organizationId}/roles/{roleId}/members":               {readOrg},
+	"POST /api/organizations/{organizationId}/roles/{roleId}/members":              {readOrg, {"org{organizationId}:role{roleId}:add_member", "org{organizationId}:add_roles"}},
+	"DELETE /api/organizations/{organizationId}/roles/{roleId}/members/{memberId}": {readOrg, {"org{organizationId}:role{roleId}:remove_member", "org{organizationId}:add_roles"}},
+
+	"GET /api/organizations/{organizationId}/teams":                                {readOrg},
+	"POST /api/organizations/{organizationId}/teams":                               {{"org{organizationId}:create_roles"}},
+	"GET /api/organizations/{organizationId}/teams/{teamId}":                       {readOrg},
+	"PUT /api/organizations/{organizationId}/teams/{teamId}":                       {{"org{organizationId}:edit_roles"}},
+	"DELETE /api/organizations/{organizationId}/teams/{teamId}":                    {{"org{organizationId}:delete_roles"}},
+	"GET /api/organizations/{organizationId}/teams/{teamId}/members":               {readOrg},
+	"POST /api/organizations/{organizationId}/teams/{teamId}/members":              {{"org{organizationId}:add_roles"}},
+	"DELETE /api/organizations/{organizationId}/teams/{teamId}/members/{memberId}": {readOrg},
+	"GET /api/organizations/{organizationId}/teams/{teamId}/boards":                {readOrg},
+
+	"GET /api/organizations/{organizationId}/invitations":                        {{"org{organizationId}:add_members"}},
+	"POST /api/organizations/{organizationId}/invitations":                       {{"org{organizationId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/invitations/{invitationId}":      {{"org{organizationId}:add_members"}},
+	"POST /api/organizations/{organizationId}/invitations/{invitationId}/resend": {{"org{organizationId}:add_members"}},
+	"GET /api/organizations/{organizationId}/invite-links":                       {{"org{organizationId}:add_members"}},
+	"POST /api/organizations/{organizationId}/invite-links":                      {{"org{organizationId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/invite-links/{linkId}":           {{"org{organizationId}:add_members"}},
+	"GET /api/organizations/{organizationId}/domains":                            {{"org{organizationId}:add_members"}},
+	"POST /api/organizations/{organizationId}/domains":                           {{"org{organizationId}:add_members"}},
+	"DELETE /api/organizations/{organizationId}/domains/{domain}":                {{"org{organizationId}:add_members"}},
+	"POST /api/organizations/{organizationId}/domains/{domain}/verify":           {{"org{organizationId}:add_members"}},
+	"POST /api/invitations/accept":                                               {},
+	"POST /api/invitations/links/join":                                           {},
+	"POST /api/invitations/domains/join":                                         {},
+
+	"GET /api/organizations/{organizationId}/api-keys":            {{"org{organizationId}:update"}},
+	"POST /api/organizations/{organizationId}/api-keys":           {{"org{organizationId}:update"}},
+	"DELETE /api/organizations/{organizationId}/api-keys/{keyId}": {{"org{organizationId}:update"}},
+	"GET /api/users/{userId}/tokens":                              {},
+	"POST /api/users/{userId}/tokens":                             {},
+	"DELETE /api/users/{userId}/tokens/{tokenId}":                 {},
+}
+
+func TestBoardRoutePermissions(t *testing.T) {
+	routes := append((&boardHandler{}).routes(), (&boardRoleHandler{}).routes()...)
+	routes = append(routes, (&shareHandler{}).routes()...)
+	routes = append(routes, (&teamHandler{}).boardRoutes()...)
+	checkRoutePermissions(t, routes, expectedBoardRoutePermissions)
+}
+
+func TestOrganizationRoutePermissions(t *testing.T) {
+	routes := append((&organizationHandler{}).routes(), (&roleHandler{}).routes()...)
+	routes = append(routes, (&teamHandler{}).routes()...)
+	routes = append(routes, (&invitationHandler{}).routes()...)
+	routes = append(routes, (&invitationHandler{}).responseRoutes()...)
+	routes = append(routes, (&credentialHandler{}).apiKeyRoutes()...)
+	routes = append(routes, (&credentialHandler{}).personalAccessTokenRoutes()...)
+	checkRoutePermissions(t, routes, expectedOrganizationRoutePermissions)
+}
+
+// checkRoutePermissions makes sure the routes are exactly the expected ones, need what they're
+// expected to, and need what openapi.json says they do
+func checkRoutePermissions(t *testing.T, routes []securedRoute, expectedPermissions map[string]permissions) {
+	t.Helper()
+	spec := loadOpenAPISpec(t)
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		key := route.method + " " + route.path
+		seen[key] = true
+		expected, ok := expectedPermissions[key]
+		if !ok {
+			t.Errorf("%s is not in the expected routes", key)
+			continue
+		}
+		if !reflect.DeepEqual(route.permissions, expected) {
+			t.Errorf("%s needs %v, expected %v", key, route.permissions, expected)
+		}
+		documented := spec.Paths[route.path][strings.ToLower(route.method)].Permissions
+		if !reflect.DeepEqual([][]string(route.permissions), documented) {
+			t.Errorf("%s needs %v but openapi.json says %v", key, route.permissions, documented)
+		}
+	}
+	for key := range expectedPermissions {
+		if !seen[key] {
+			t.Errorf("%s is expected but not registered", key)
+		}
+	}
+}
+
+func TestRequirePermissionsFillsInPathVariables(t *testing.T) {
+	handled := false
+	handler := requirePermissions(permissions{reachBoard, {"org{organizationId}:board{boardId}:update"}}, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handled = true
+	}))
+	serve := func(grantedPermissions ...string) int {
+		handled = false
+		request := httptest.NewRequest("PUT", "/api/organizations/org1/boards/board1", nil)
+		request = mux.SetURLVars(request, map[string]string{"organizationId": "1", "boardId": "2"})
+		claims := &validator.ValidatedClaims{
+			RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|user"},
+			CustomClaims:     &auth.CustomClaims{Permissions: grantedPermissions},
+		}
+		request = request.WithContext(context.WithValue(request.Context(), jwtmiddleware.ContextKey{}, claims))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := serve("org1:board2:read", "org1:board2:update"); code != http.StatusOK || !handled {
+		t.Fatalf("expected a board member who can update to get through, got %d", code)
+	}
+	if code := serve("org1:read", "org1:board3:update"); code != http.StatusForbidden || handled {
+		t.Fatalf("expected updating another board not to count, got %d", code)
+	}
+	if code := serve("org1:board2:update"); code != http.StatusForbidden || handled {
+		t.Fatalf("expected every list of permissions to be needed, got %d", code)
+	}
+	if code := serve("org1:read", "org1:boards_admin"); code != http.StatusOK || !handled {
+		t.Fatalf("expected boards_admin to cover the board, got %d", code)
+	}
+}
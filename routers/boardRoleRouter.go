@@ -0,0 +1,249 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+type boardRoleHandler struct {
+	router     *mux.Router
+	controller *board.Controller
+}
+
+func registerBoardRoleRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &boardRoleHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: board.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handleSecured(handler.router, handler.routes())
+
+	return handler.router
+}
+
+func (handler *boardRoleHandler) routes() []securedRoute {
+	rolesPrefix := fmt.Sprintf("%s/{boardId}/roles", boardsPrefix)
+	return []securedRoute{
+		{"GET", rolesPrefix, handler.resolved(handler.GetBoardRoles), permissions{reachBoard}},
+		{"POST", rolesPrefix, handler.resolved(handler.CreateBoardRole), permissions{reachBoard, onBoard("update")}},
+		{"GET", fmt.Sprintf("%s/permissions", rolesPrefix), handler.resolved(handler.GetBoardPermissions), permissions{reachBoard}},
+		{"GET", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.resolved(handler.GetBoardRole), permissions{reachBoard}},
+		{"PUT", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.resolved(handler.UpdateBoardRole), permissions{reachBoard, onBoard("update")}},
+		{"DELETE", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.resolved(handler.DeleteBoardRole), permissions{reachBoard, onBoard("update")}},
+		{"GET", fmt.Sprintf("%s/{roleId}/permissions", rolesPrefix), handler.resolved(handler.GetBoardRolePermissions), permissions{reachBoard}},
+		{"GET", fmt.Sprintf("%s/{roleId}/members", rolesPrefix), handler.resolved(handler.GetBoardRoleMembers), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{roleId}/members", rolesPrefix), handler.resolved(handler.AddMemberToBoardRole), permissions{reachBoard, onBoard("add_members")}},
+		// Anyone can give up a role, so the handler checks remove_members for everyone else
+		{"DELETE", fmt.Sprintf("%s/{roleId}/members/{memberId}", rolesPrefix), handler.resolved(handler.RemoveMemberFromBoardRole), permissions{reachBoard}},
+	}
+}
+
+func (handler *boardRoleHandler) resolved(next http.HandlerFunc) http.HandlerFunc {
+	return withResources(handler.controller, false, next)
+}
+
+func (handler *boardRoleHandler) GetBoardRoles(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	roles, err := handler.controller.GetBoardRoles(organizationId, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get roles for board %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(roles)
+}
+
+func (handler *boardRoleHandler) GetBoardPermissions(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(board.BoardPermissions(organizationId, boardId))
+}
+
+func (handler *boardRoleHandler) CreateBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	roleName := body.Get("name")
+	if roleName == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No name Found"))
+		return
+	}
+	roleDescription := body.Get("description")
+	if roleDescription == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No description Found"))
+		return
+	}
+	permissionNames := body["permission_names"]
+	if len(permissionNames) == 0 {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Permission Names Found"))
+		return
+	}
+
+	role, err := handler.controller.CreateBoardRole(organizationId, boardId, roleName, roleDescription, permissionNames)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to create role for board %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(role)
+}
+
+func (handler *boardRoleHandler) GetBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	role, err := handler.controller.GetBoardRole(organizationId, boardId, roleId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get role %s: %w", roleId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(role)
+}
+
+func (handler *boardRoleHandler) UpdateBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	role, err := handler.controller.UpdateBoardRole(organizationId, boardId, roleId, body.Get("name"), body.Get("description"), body["permission_names"])
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to update role %s: %w", roleId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(role)
+}
+
+func (handler *boardRoleHandler) DeleteBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	err := handler.controller.DeleteBoardRole(organizationId, boardId, roleId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to delete role %s: %w", roleId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *boardRoleHandler) GetBoardRolePermissions(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	permissions, err := handler.controller.GetBoardRolePermissions(organizationId, boardId, roleId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get permissions for role %s: %w", roleId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(permissions)
+}
+
+func (handler *boardRoleHandler) GetBoardRoleMembers(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	members, err := handler.controller.GetBoardRoleMembers(organizationId, boardId, roleId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get members with role %s: %w", roleId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(members)
+}
+
+func (handler *boardRoleHandler) AddMemberToBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	memberId := body.Get("member_id")
+	if memberId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Member ID Found"))
+		return
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	err = handler.controller.AddMemberToBoardRole(tokenCustomClaims, organizationId, boardId, roleId, memberId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to add user %s to role %s: %w", memberId, roleId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *boardRoleHandler) RemoveMemberFromBoardRole(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	roleId := params["roleId"]
+	memberId := params["memberId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	userId := token.RegisteredClaims.Subject
+	removeMembersPerm := fmt.Sprintf("org%s:board%s:remove_members", organizationId, boardId)
+	if !tokenCustomClaims.HasPermission(removeMembersPerm) && userId != memberId {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to remove members from roles on board with id: %s", userId, boardId)))
+		return
+	}
+
+	err := handler.controller.RemoveMemberFromBoardRole(organizationId, boardId, roleId, memberId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to remove user %s from role %s: %w", memberId, roleId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
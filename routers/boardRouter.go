@@ -1,8 +1,8 @@
 package routers
 
 import (
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -13,8 +13,11 @@ import (
 
 	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/board"
 	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
 )
 
 type boardHandler struct {
@@ -25,7 +28,7 @@ type boardHandler struct {
 func registerBoardRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
 	handler := &boardHandler{
 		router:     parentRouter.NewRoute().Subrouter(),
-		controller: board.NewController(cfg, db),
+		controller: board.NewController(cfg, store.NewPostgres(db)),
 	}
 
 	// TODO: add routes for below
@@ -43,48 +46,81 @@ func registerBoardRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB
 	// // delete a tag from a card
 	// handler.router.HandleFunc(fmt.Sprintf("%s/{OrganizationId}/%s/{BoardID}/{ListID}/{CardID}/{TagID}", organizationsPrefix, boardsPrefix), handler.AddTagToCard).Methods("DELETE")
 
-	handler.router.Handle(boardsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.GetAllBoards))).Methods("GET")
-	handler.router.Handle(boardsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.CreateBoard))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/ai", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.CreateBoardWithAI))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetBoard))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdateBoard))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeleteBoard))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}/details", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetCompleteBoard))).Methods("GET")
-
-	// Because a board member is known through a role, these routes could possilby be removed or refactroed to call the role routes
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}/members", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetBoardMembers))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}/members", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.AddMemberToBoard))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{boardId}/members/{memberId}", boardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.RemoveMemberFromBoard))).Methods("DELETE")
-
-	handler.router.Handle(panelsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.GetPanels))).Methods("GET")
-	handler.router.Handle(panelsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.CreatePanel))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{panelId}", panelsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetPanel))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{panelId}", panelsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdatePanel))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{panelId}", panelsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeletePanel))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{panelId}/details", panelsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetCompletePanel))).Methods("GET")
-
-	handler.router.Handle(stacksPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.GetStacks))).Methods("GET")
-	handler.router.Handle(stacksPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.CreateStack))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{stackId}", stacksPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetStack))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{stackId}", stacksPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdateStack))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{stackId}", stacksPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeleteStack))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{stackId}/details", stacksPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetCompleteStack))).Methods("GET")
-
-	handler.router.Handle(cardsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.GetCards))).Methods("GET")
-	handler.router.Handle(cardsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.CreateCard))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/ai", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.CreateCardWithAI))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetCard))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdateCard))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeleteCard))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}/details", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetCompleteCard))).Methods("GET")
-
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}/assigned", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetAllAssignedUsers))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}/assigned", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.AssignCardToUser))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{cardId}/assigned/{memberId}", cardsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UnassignCardFromUser))).Methods("DELETE")
+	handleSecured(handler.router, handler.routes())
 
 	return handler.router
 }
 
+// routes lists every board route with the permissions it needs. Routes under a board load it and
+// whatever else is in the path first, so handlers get them from resourcesFrom. Reading a private
+// board also needs org<id>:board<id>:read, which the handlers check on the loaded board.
+func (handler *boardHandler) routes() []securedRoute {
+	return []securedRoute{
+		{"GET", fmt.Sprintf("%s/{organizationId}/trash", organizationsPrefix), handler.GetOrganizationTrash, permissions{readOrg}},
+
+		{"GET", boardsPrefix, handler.GetAllBoards, permissions{readOrg}},
+		{"POST", boardsPrefix, handler.CreateBoard, permissions{anyOf("org{organizationId}:create_boards")}},
+		{"POST", fmt.Sprintf("%s/ai", boardsPrefix), handler.CreateBoardWithAI, permissions{anyOf("org{organizationId}:create_boards")}},
+		{"GET", fmt.Sprintf("%s/ai/{jobId}", boardsPrefix), handler.GetBoardGeneration, permissions{anyOf("org{organizationId}:create_boards")}},
+		{"GET", fmt.Sprintf("%s/{boardId}", boardsPrefix), handler.resolved(handler.GetBoard), permissions{reachBoard}},
+		{"PUT", fmt.Sprintf("%s/{boardId}", boardsPrefix), handler.resolved(handler.UpdateBoard), permissions{reachBoard, onBoard("update")}},
+		{"PATCH", fmt.Sprintf("%s/{boardId}", boardsPrefix), handler.resolved(handler.PatchBoard), permissions{reachBoard, onBoard("update")}},
+		{"DELETE", fmt.Sprintf("%s/{boardId}", boardsPrefix), handler.resolved(handler.DeleteBoard), permissions{reachBoard, onBoard("delete")}},
+		{"GET", fmt.Sprintf("%s/{boardId}/details", boardsPrefix), handler.resolved(handler.GetCompleteBoard), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{boardId}/archive", boardsPrefix), handler.resolved(handler.ArchiveBoard), permissions{reachBoard, onBoard("update")}},
+		{"POST", fmt.Sprintf("%s/{boardId}/unarchive", boardsPrefix), handler.resolved(handler.UnarchiveBoard), permissions{reachBoard, onBoard("update")}},
+		{"GET", fmt.Sprintf("%s/{boardId}/trash", boardsPrefix), handler.resolved(handler.GetBoardTrash), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{boardId}/restore", boardsPrefix), handler.resolvedInTrash(handler.RestoreBoard), permissions{reachBoard, onBoard("delete")}},
+
+		// Because a board member is known through a role, these routes could possilby be removed or refactroed to call the role routes
+		{"GET", fmt.Sprintf("%s/{boardId}/members", boardsPrefix), handler.resolved(handler.GetBoardMembers), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{boardId}/members", boardsPrefix), handler.resolved(handler.AddMemberToBoard), permissions{reachBoard, onBoard("add_members")}},
+		// Anyone on the board can leave it, so the handler checks remove_members for everyone else
+		{"DELETE", fmt.Sprintf("%s/{boardId}/members/{memberId}", boardsPrefix), handler.resolved(handler.RemoveMemberFromBoard), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{boardId}/guests", boardsPrefix), handler.resolved(handler.AddGuestToBoard), permissions{reachBoard, onBoard("add_members")}},
+
+		{"GET", panelsPrefix, handler.resolved(handler.GetPanels), permissions{reachBoard}},
+		{"POST", panelsPrefix, handler.resolved(handler.CreatePanel), permissions{reachBoard, onBoard("create_panel")}},
+		{"GET", fmt.Sprintf("%s/{panelId}", panelsPrefix), handler.resolved(handler.GetPanel), permissions{reachBoard}},
+		{"PUT", fmt.Sprintf("%s/{panelId}", panelsPrefix), handler.resolved(handler.UpdatePanel), permissions{reachBoard, onBoard("update_panel")}},
+		{"PATCH", fmt.Sprintf("%s/{panelId}", panelsPrefix), handler.resolved(handler.PatchPanel), permissions{reachBoard, onBoard("update_panel")}},
+		{"DELETE", fmt.Sprintf("%s/{panelId}", panelsPrefix), handler.resolved(handler.DeletePanel), permissions{reachBoard, onBoard("delete_panel")}},
+		{"GET", fmt.Sprintf("%s/{panelId}/details", panelsPrefix), handler.resolved(handler.GetCompletePanel), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{panelId}/restore", panelsPrefix), handler.resolvedInTrash(handler.RestorePanel), permissions{reachBoard, onBoard("delete_panel")}},
+
+		{"GET", stacksPrefix, handler.resolved(handler.GetStacks), permissions{reachBoard}},
+		{"POST", stacksPrefix, handler.resolved(handler.CreateStack), permissions{reachBoard, onBoard("create_stack")}},
+		{"GET", fmt.Sprintf("%s/{stackId}", stacksPrefix), handler.resolved(handler.GetStack), permissions{reachBoard}},
+		{"PUT", fmt.Sprintf("%s/{stackId}", stacksPrefix), handler.resolved(handler.UpdateStack), permissions{reachBoard, onBoard("update_stack")}},
+		{"PATCH", fmt.Sprintf("%s/{stackId}", stacksPrefix), handler.resolved(handler.PatchStack), permissions{reachBoard, onBoard("update_stack")}},
+		{"DELETE", fmt.Sprintf("%s/{stackId}", stacksPrefix), handler.resolved(handler.DeleteStack), permissions{reachBoard, onBoard("delete_stack")}},
+		{"GET", fmt.Sprintf("%s/{stackId}/details", stacksPrefix), handler.resolved(handler.GetCompleteStack), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{stackId}/restore", stacksPrefix), handler.resolvedInTrash(handler.RestoreStack), permissions{reachBoard, onBoard("delete_stack")}},
+
+		{"GET", cardsPrefix, handler.resolved(handler.GetCards), permissions{reachBoard}},
+		{"POST", cardsPrefix, handler.resolved(handler.CreateCard), permissions{reachBoard, onBoard("create_card")}},
+		{"POST", fmt.Sprintf("%s/ai", cardsPrefix), handler.resolved(handler.CreateCardWithAI), permissions{reachBoard, onBoard("create_card")}},
+		{"GET", fmt.Sprintf("%s/{cardId}", cardsPrefix), handler.resolved(handler.GetCard), permissions{reachBoard}},
+		{"PUT", fmt.Sprintf("%s/{cardId}", cardsPrefix), handler.resolved(handler.UpdateCard), permissions{reachBoard, onBoard("update_card")}},
+		{"PATCH", fmt.Sprintf("%s/{cardId}", cardsPrefix), handler.resolved(handler.PatchCard), permissions{reachBoard, onBoard("update_card")}},
+		{"DELETE", fmt.Sprintf("%s/{cardId}", cardsPrefix), handler.resolved(handler.DeleteCard), permissions{reachBoard, onBoard("delete_card")}},
+		{"GET", fmt.Sprintf("%s/{cardId}/details", cardsPrefix), handler.resolved(handler.GetCompleteCard), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{cardId}/restore", cardsPrefix), handler.resolvedInTrash(handler.RestoreCard), permissions{reachBoard, onBoard("delete_card")}},
+
+		{"GET", fmt.Sprintf("%s/{cardId}/assigned", cardsPrefix), handler.resolved(handler.GetAllAssignedUsers), permissions{reachBoard}},
+		{"POST", fmt.Sprintf("%s/{cardId}/assigned", cardsPrefix), handler.resolved(handler.AssignCardToUser), permissions{reachBoard, onBoard("update_card")}},
+		{"DELETE", fmt.Sprintf("%s/{cardId}/assigned/{memberId}", cardsPrefix), handler.resolved(handler.UnassignCardFromUser), permissions{reachBoard, onBoard("update_card")}},
+	}
+}
+
+func (handler *boardHandler) resolved(next http.HandlerFunc) http.HandlerFunc {
+	return withResources(handler.controller, false, next)
+}
+
+func (handler *boardHandler) resolvedInTrash(next http.HandlerFunc) http.HandlerFunc {
+	return withResources(handler.controller, true, next)
+}
+
 func (handler *boardHandler) GetAllBoards(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
@@ -92,21 +128,21 @@ func (handler *boardHandler) GetAllBoards(writer http.ResponseWriter, request *h
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
+
+	includeArchived := false
+	if request.FormValue("include_archived") != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(request.FormValue("include_archived"))
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse include_archived: %s", err.Error())))
+			return
+		}
 	}
 
 	ctx := request.Context()
-	visibleBoards, err := handler.controller.GetViewableBoardsInOrg(ctx, tokenCustomClaims, organizationId, userId)
+	visibleBoards, err := handler.controller.GetViewableBoardsInOrg(ctx, tokenCustomClaims, organizationId, userId, includeArchived)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No organization found with id %s", organizationId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get viewable boards: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get viewable boards: %w", err))
 		return
 	}
 
@@ -118,50 +154,40 @@ func (handler *boardHandler) GetAllBoards(writer http.ResponseWriter, request *h
 func (handler *boardHandler) CreateBoard(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	orgId := params["organizationId"]
-	title := request.FormValue("title")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
 	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
-	description := request.FormValue("description")
+	description := body.Get("description")
 
-	isPrivateString := request.FormValue("isPrivate")
+	isPrivateString := body.Get("isPrivate")
 	isPrivate := false
-	var err error
 	if isPrivateString != "" {
-		isPrivate, err = strconv.ParseBool(request.FormValue("isPrivate"))
+		isPrivate, err = strconv.ParseBool(body.Get("isPrivate"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error())))
 			return
 		}
 	}
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", orgId)
-	createBoardsPerm := fmt.Sprintf("%s:create_boards", orgPrefix)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canCreateBoards := tokenCustomClaims.HasAnyPermissions(createBoardsPerm, boardsAdminPerm)
-	if !canCreateBoards {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create board in org with id: %s", token.RegisteredClaims.Subject, orgId), http.StatusForbidden)
-		return
-	}
 	ctx := request.Context()
 	board, err := handler.controller.CreateBoard(ctx, userId, title, description, isPrivate, orgId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create board: %s", err.Error()), http.StatusInternalServerError)
-		return
-	}
-
-	err = handler.controller.InitializeBoard(userId, board.Id.String(), orgId)
-	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to initialize board: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create board: %w", err))
 		return
 	}
 
+	// The board's roles are set up by a job, so it is still provisioning
 	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusCreated)
+	writer.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(writer).Encode(board)
 }
 
@@ -173,35 +199,19 @@ func (handler *boardHandler) GetBoard(writer http.ResponseWriter, request *http.
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
-	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
 
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		orgPrefix := fmt.Sprintf("org%s", organizationId)
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
+	setETag(writer, board.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(board)
@@ -212,42 +222,46 @@ func (handler *boardHandler) UpdateBoard(writer http.ResponseWriter, request *ht
 	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 
-	title := request.FormValue("title")
-	ownerId := request.FormValue("ownerId")
-	description := request.FormValue("description")
-	isPrivateString := request.FormValue("isPrivate")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
+	ownerId := body.Get("ownerId")
+	description := body.Get("description")
+	isPrivateString := body.Get("isPrivate")
 	isPrivate := false
-	var err error
 	if isPrivateString != "" {
-		isPrivate, err = strconv.ParseBool(request.FormValue("isPrivate"))
+		isPrivate, err = strconv.ParseBool(body.Get("isPrivate"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error())))
 			return
 		}
 	}
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-	updateBoardPerm := fmt.Sprintf("%s:board%s:update", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdateBoard := tokenCustomClaims.HasAnyPermissions(updateBoardPerm, boardsAdminPerm)
-	if !canUpdateBoard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update board with id: %s", userId, boardId), http.StatusForbidden)
+
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err = handler.controller.UpdateBoardById(ctx, organizationId, boardId, title, description, isPrivate, ownerId, userId)
+	err = handler.controller.UpdateBoardById(ctx, organizationId, boardId, version, title, description, isPrivate, ownerId, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update board with id %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentBoard, err := handler.controller.GetBoardById(ctx, boardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get board with id %s: %w", boardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentBoard, currentBoard.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update board with id %s: %w", organizationId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -256,31 +270,27 @@ func (handler *boardHandler) UpdateBoard(writer http.ResponseWriter, request *ht
 
 func (handler *boardHandler) DeleteBoard(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read org with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-	deleteBoardPerm := fmt.Sprintf("%s:board%s:delete", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canDeleteBoard := tokenCustomClaims.HasAnyPermissions(deleteBoardPerm, boardsAdminPerm)
-	if !canDeleteBoard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to delete board with id: %s", userId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.DeleteBoardById(ctx, boardId)
+	err = handler.controller.DeleteBoardById(ctx, boardId, version)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete board with id %s: %s", boardId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentBoard, err := handler.controller.GetBoardById(ctx, boardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get board with id %s: %w", boardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentBoard, currentBoard.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to delete board with id %s: %w", boardId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -296,34 +306,24 @@ func (handler *boardHandler) GetCompleteBoard(writer http.ResponseWriter, reques
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
 	board, err := handler.controller.GetCompleteBoardById(ctx, boardId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get board: %w", err))
 		return
 	}
 
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
+	setETag(writer, board.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(board)
@@ -338,36 +338,20 @@ func (handler *boardHandler) GetBoardMembers(writer http.ResponseWriter, request
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
-	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	members, err := handler.controller.GetMembersByBoardId(boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users in board with id %s: %s", boardId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get users in board with id %s: %w", boardId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -380,31 +364,49 @@ func (handler *boardHandler) AddMemberToBoard(writer http.ResponseWriter, reques
 	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 
-	memberId := request.FormValue("user_id")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	memberId := body.Get("user_id")
 	if memberId == "" {
-		http.Error(writer, "No Member ID Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Member ID Found"))
 		return
 	}
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	addUsersPerm := fmt.Sprintf("%s:board%s:add_members", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canAddUsers := tokenCustomClaims.HasAnyPermissions(addUsersPerm, boardsAdminPerm)
-	if !canAddUsers {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add users to org %s board with id %s", userId, organizationId, boardId), http.StatusForbidden)
+	ctx := request.Context()
+	err = handler.controller.AddMemberToBoard(ctx, memberId, organizationId, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get users in board with id %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *boardHandler) AddGuestToBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	guestId := body.Get("user_id")
+	if guestId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Guest ID Found"))
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.AddMemberToBoard(ctx, memberId, organizationId, boardId)
+	err = handler.controller.AddGuestToBoard(ctx, guestId, organizationId, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users in board with id %s: %s", boardId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add guest to board with id %s: %w", boardId, err))
 		return
 	}
-	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusNoContent)
 }
 
@@ -419,16 +421,15 @@ func (handler *boardHandler) RemoveMemberFromBoard(writer http.ResponseWriter, r
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
 	removeUsersPerm := fmt.Sprintf("%s:board%s:remove_members", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canRemoveUsers := tokenCustomClaims.HasAnyPermissions(removeUsersPerm, boardsAdminPerm)
+	canRemoveUsers := tokenCustomClaims.HasPermission(removeUsersPerm)
 	if !canRemoveUsers && userId != memberId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add users to org %s board with id %s", userId, organizationId, boardId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to add users to org %s board with id %s", userId, organizationId, boardId)))
 		return
 	}
 	ctx := request.Context()
 	err := handler.controller.RemoveMemberFromBoard(ctx, memberId, organizationId, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users in board with id %s: %s", boardId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get users in board with id %s: %w", boardId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -444,36 +445,21 @@ func (handler *boardHandler) GetPanels(writer http.ResponseWriter, request *http
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	panels, err := handler.controller.GetPanelsByBoardId(ctx, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get panels from board with id %s: %s", boardId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get panels from board with id %s: %w", boardId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -483,36 +469,22 @@ func (handler *boardHandler) GetPanels(writer http.ResponseWriter, request *http
 
 func (handler *boardHandler) CreatePanel(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 
-	title := request.FormValue("title")
-	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
-		return
-	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-	createPanelPerm := fmt.Sprintf("%s:board%s:create_panel", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canCreatePanel := tokenCustomClaims.HasAnyPermissions(createPanelPerm, boardsAdminPerm)
-	if !canCreatePanel {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create panel on board with id: %s", userId, boardId), http.StatusForbidden)
+	title := body.Get("title")
+	if title == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
 
 	panel, err := handler.controller.CreatePanel(request.Context(), title, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create panel: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create panel: %w", err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -524,48 +496,24 @@ func (handler *boardHandler) GetPanel(writer http.ResponseWriter, request *http.
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 	boardId := params["boardId"]
-	panelId := params["panelId"]
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
-	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
-	if board.IsPrivate {
+	resources := resourcesFrom(request)
+	if resources.Board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
-	panel, err := handler.controller.GetPanelById(ctx, panelId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No panel with id %s found", panelId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get panel: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	panel := resources.Panel
+	setETag(writer, panel.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(panel)
@@ -573,38 +521,45 @@ func (handler *boardHandler) GetPanel(writer http.ResponseWriter, request *http.
 
 func (handler *boardHandler) UpdatePanel(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	panelId := params["panelId"]
 
-	title := request.FormValue("title")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
 	var position *int
-	if request.FormValue("position") != "" {
+	if body.Get("position") != "" {
 		var err error
-		tempPosition, err := strconv.Atoi(request.FormValue("position"))
+		tempPosition, err := strconv.Atoi(body.Get("position"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse position: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse position: %s", err.Error())))
 			return
 		}
 		position = &tempPosition
 	}
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	updatePanelPerm := fmt.Sprintf("%s:board%s:update_panel", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdatePanel := tokenCustomClaims.HasAnyPermissions(updatePanelPerm, boardsAdminPerm)
-	if !canUpdatePanel {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update panel %s on board with id: %s", userId, panelId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.UpdatePanelById(ctx, boardId, panelId, title, position)
+	err = handler.controller.UpdatePanelById(ctx, boardId, panelId, version, title, position)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update panel with id %s: %s", panelId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentPanel, err := handler.controller.GetPanelById(ctx, panelId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get panel with id %s: %w", panelId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentPanel, currentPanel.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update panel with id %s: %w", panelId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -613,26 +568,28 @@ func (handler *boardHandler) UpdatePanel(writer http.ResponseWriter, request *ht
 
 func (handler *boardHandler) DeletePanel(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	panelId := params["panelId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	deletePanelPerm := fmt.Sprintf("%s:board%s:delete_panel", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canDeletePanel := tokenCustomClaims.HasAnyPermissions(deletePanelPerm, boardsAdminPerm)
-	if !canDeletePanel {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to delete panel %s on board with id: %s", userId, panelId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.DeletePanelById(ctx, boardId, panelId)
+	err = handler.controller.DeletePanelById(ctx, boardId, panelId, version)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete panel with id %s: %s", panelId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentPanel, err := handler.controller.GetPanelById(ctx, panelId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get panel with id %s: %w", panelId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentPanel, currentPanel.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to delete panel with id %s: %w", panelId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -649,41 +606,24 @@ func (handler *boardHandler) GetCompletePanel(writer http.ResponseWriter, reques
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	panel, err := handler.controller.GetCompletePanelById(ctx, panelId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No panel with id %s found", panelId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get panel: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get panel: %w", err))
+		return
 	}
+	setETag(writer, panel.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(panel)
@@ -699,36 +639,21 @@ func (handler *boardHandler) GetStacks(writer http.ResponseWriter, request *http
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	stacks, err := handler.controller.GetStacksByPanelId(ctx, panelId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get stacks from panel with id %s: %s", panelId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get stacks from panel with id %s: %w", panelId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -738,37 +663,23 @@ func (handler *boardHandler) GetStacks(writer http.ResponseWriter, request *http
 
 func (handler *boardHandler) CreateStack(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	panelId := params["panelId"]
 
-	title := request.FormValue("title")
-	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
-		return
-	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-	createStackPerm := fmt.Sprintf("org%s:board%s:create_stack", organizationId, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canCreateStack := tokenCustomClaims.HasAnyPermissions(createStackPerm, boardsAdminPerm)
-	if !canCreateStack {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create stack on board with id: %s", userId, boardId), http.StatusForbidden)
+	title := body.Get("title")
+	if title == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
 
 	stack, err := handler.controller.CreateStack(request.Context(), title, boardId, panelId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create stack: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create stack: %w", err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -780,50 +691,25 @@ func (handler *boardHandler) GetStack(writer http.ResponseWriter, request *http.
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 	boardId := params["boardId"]
-	stackId := params["stackId"]
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
-	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
 
-	if board.IsPrivate {
+	resources := resourcesFrom(request)
+	if resources.Board.IsPrivate {
 		orgPrefix := fmt.Sprintf("org%s", organizationId)
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
-	stack, err := handler.controller.GetStackById(ctx, stackId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No stack found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get stack: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	stack := resources.Stack
 
+	setETag(writer, stack.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(stack)
@@ -831,39 +717,46 @@ func (handler *boardHandler) GetStack(writer http.ResponseWriter, request *http.
 
 func (handler *boardHandler) UpdateStack(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	panelId := params["panelId"]
 	stackId := params["stackId"]
 
-	title := request.FormValue("title")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
 	var position *int
-	if request.FormValue("position") != "" {
+	if body.Get("position") != "" {
 		var err error
-		tempPosition, err := strconv.Atoi(request.FormValue("position"))
+		tempPosition, err := strconv.Atoi(body.Get("position"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse position: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse position: %s", err.Error())))
 			return
 		}
 		position = &tempPosition
 	}
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	updateStackPerm := fmt.Sprintf("%s:board%s:update_stack", userId, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdateStack := tokenCustomClaims.HasAnyPermissions(updateStackPerm, boardsAdminPerm)
-	if !canUpdateStack {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update stack %s in board with id: %s", userId, stackId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.UpdateStackById(ctx, boardId, panelId, stackId, title, position)
+	err = handler.controller.UpdateStackById(ctx, boardId, panelId, stackId, version, title, position)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update stack with id %s: %s", stackId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentStack, err := handler.controller.GetStackById(ctx, stackId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get stack with id %s: %w", stackId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentStack, currentStack.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update stack with id %s: %w", stackId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -872,27 +765,29 @@ func (handler *boardHandler) UpdateStack(writer http.ResponseWriter, request *ht
 
 func (handler *boardHandler) DeleteStack(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	panelId := params["panelId"]
 	stackId := params["stackId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	deleteStackPerm := fmt.Sprintf("%s:board%s:delete_stack", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canDeleteStack := tokenCustomClaims.HasAnyPermissions(deleteStackPerm, boardsAdminPerm)
-	if !canDeleteStack {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to delete stack %s on board with id: %s", userId, panelId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.DeleteStackById(ctx, boardId, panelId, stackId)
+	err = handler.controller.DeleteStackById(ctx, boardId, panelId, stackId, version)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete stack with id %s: %s", stackId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentStack, err := handler.controller.GetStackById(ctx, stackId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get stack with id %s: %w", stackId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentStack, currentStack.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to delete stack with id %s: %w", stackId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -909,41 +804,24 @@ func (handler *boardHandler) GetCompleteStack(writer http.ResponseWriter, reques
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	stack, err := handler.controller.GetCompleteStackById(ctx, stackId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No stack with id %s found", stackId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get stack: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get stack: %w", err))
+		return
 	}
+	setETag(writer, stack.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
 	json.NewEncoder(writer).Encode(stack)
@@ -959,36 +837,21 @@ func (handler *boardHandler) GetCards(writer http.ResponseWriter, request *http.
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
 	cards, err := handler.controller.GetCardsByStackId(ctx, stackId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get cards from stack with id %s: %s", stackId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get cards from stack with id %s: %w", stackId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -998,39 +861,25 @@ func (handler *boardHandler) GetCards(writer http.ResponseWriter, request *http.
 
 func (handler *boardHandler) CreateCard(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	stackId := params["stackId"]
 
-	title := request.FormValue("title")
-	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
-		return
-	}
-	description := request.FormValue("description")
-	points := request.FormValue("points")
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-	createCardPerm := fmt.Sprintf("%s:board%s:create_card", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canCreateCard := tokenCustomClaims.HasAnyPermissions(createCardPerm, boardsAdminPerm)
-	if !canCreateCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create a card on board with id: %s", userId, boardId), http.StatusForbidden)
+	title := body.Get("title")
+	if title == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
+	description := body.Get("description")
+	points := body.Get("points")
 
 	card, err := handler.controller.CreateCard(request.Context(), title, description, points, boardId, stackId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create card: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create card: %w", err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1046,18 +895,18 @@ func (handler *boardHandler) CreateCardWithAI(writer http.ResponseWriter, reques
 
 	aiEnabled, err := handler.controller.CanUseAI(request.Context(), organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to check if AI is enabled: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to check if AI is enabled: %w", err))
 		return
 	}
 	if !aiEnabled {
-		http.Error(writer, fmt.Sprintf("AI is not enabled for organization with id %s", organizationId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("AI is not enabled for organization with id %s", organizationId)))
 		return
 	}
 
 	ctx := request.Context()
 	card, err := handler.controller.CreateCardWithAI(ctx, boardId, stackId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create card: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create card: %w", err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1069,93 +918,75 @@ func (handler *boardHandler) GetCard(writer http.ResponseWriter, request *http.R
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 	boardId := params["boardId"]
-	cardId := params["cardId"]
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
-	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
 
-	if board.IsPrivate {
+	resources := resourcesFrom(request)
+	if resources.Board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
-	stack, err := handler.controller.GetCardById(ctx, cardId)
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No card found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get card: %s", err.Error()), http.StatusInternalServerError)
-		}
-		return
-	}
+	card := resources.Card
 
+	setETag(writer, card.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
-	json.NewEncoder(writer).Encode(stack)
+	json.NewEncoder(writer).Encode(card)
 }
 
 func (handler *boardHandler) UpdateCard(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	stackId := params["stackId"]
 	cardId := params["cardId"]
 
-	title := request.FormValue("title")
-	description := request.FormValue("description")
-	points := request.FormValue("points")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
+	description := body.Get("description")
+	points := body.Get("points")
 	var position *int
-	if request.FormValue("position") != "" {
+	if body.Get("position") != "" {
 		var err error
-		tempPosition, err := strconv.Atoi(request.FormValue("position"))
+		tempPosition, err := strconv.Atoi(body.Get("position"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse position: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse position: %s", err.Error())))
 			return
 		}
 		position = &tempPosition
 	}
-	newStackId := request.FormValue("stack_id")
+	newStackId := body.Get("stack_id")
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	updateCardPerm := fmt.Sprintf("%s:board%s:update_card", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdateCard := tokenCustomClaims.HasAnyPermissions(updateCardPerm, boardsAdminPerm)
-	if !canUpdateCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update card %s in board with id: %s", userId, cardId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.UpdateCardById(ctx, boardId, stackId, cardId, newStackId, title, description, points, position)
+	err = handler.controller.UpdateCardById(ctx, boardId, stackId, cardId, version, newStackId, title, description, points, position)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update card with id %s: %s", cardId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentCard, err := handler.controller.GetCardById(ctx, cardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get card with id %s: %w", cardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentCard, currentCard.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update card with id %s: %w", cardId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1164,27 +995,29 @@ func (handler *boardHandler) UpdateCard(writer http.ResponseWriter, request *htt
 
 func (handler *boardHandler) DeleteCard(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	stackId := params["stackId"]
 	cardId := params["cardId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	deleteCardPerm := fmt.Sprintf("%s:board%s:delete_card", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canDeleteCard := tokenCustomClaims.HasAnyPermissions(deleteCardPerm, boardsAdminPerm)
-	if !canDeleteCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to delete card %s on board with id: %s", userId, cardId, boardId), http.StatusForbidden)
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.DeleteCardById(ctx, boardId, stackId, cardId)
+	err = handler.controller.DeleteCardById(ctx, boardId, stackId, cardId, version)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete card with id %s: %s", cardId, err.Error()), http.StatusInternalServerError)
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentCard, err := handler.controller.GetCardById(ctx, cardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get card with id %s: %w", cardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentCard, currentCard.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to delete card with id %s: %w", cardId, err))
+		}
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1226,28 +1059,14 @@ func (handler *boardHandler) DeleteCard(writer http.ResponseWriter, request *htt
 
 func (handler *boardHandler) GetAllAssignedUsers(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
-	boardId := params["boardId"]
 	// stackId := params["stackId"]
 	cardId := params["cardId"]
 	// memberId := params["memberId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readCardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canReadCard := tokenCustomClaims.HasAnyPermissions(readCardPerm, boardsAdminPerm)
-	if !canReadCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read card %s on board with id: %s", userId, cardId, boardId), http.StatusForbidden)
-		return
-	}
-
 	ctx := request.Context()
 	cards, err := handler.controller.GetAssignedUsersByCardId(ctx, cardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get assigned users for card with id %s: %s", cardId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get assigned users for card with id %s: %w", cardId, err))
 		return
 	}
 	if cards == nil {
@@ -1260,33 +1079,25 @@ func (handler *boardHandler) GetAllAssignedUsers(writer http.ResponseWriter, req
 
 func (handler *boardHandler) AssignCardToUser(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	// stackId := params["stackId"]
 	cardId := params["cardId"]
 
-	memberId := request.FormValue("user_id")
-	if memberId == "" {
-		http.Error(writer, "No User Id Found", http.StatusBadRequest)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	updateCardPerm := fmt.Sprintf("%s:board%s:update", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdateCard := tokenCustomClaims.HasAnyPermissions(updateCardPerm, boardsAdminPerm)
-	if !canUpdateCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update card %s on board with id: %s", userId, cardId, boardId), http.StatusForbidden)
+	memberId := body.Get("user_id")
+	if memberId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No User Id Found"))
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.AssignCardToUser(ctx, boardId, cardId, memberId)
+	err = handler.controller.AssignCardToUser(ctx, boardId, cardId, memberId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to assign card with id %s to user with id %s: %s", cardId, memberId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to assign card with id %s to user with id %s: %w", cardId, memberId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1295,28 +1106,15 @@ func (handler *boardHandler) AssignCardToUser(writer http.ResponseWriter, reques
 
 func (handler *boardHandler) UnassignCardFromUser(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	boardId := params["boardId"]
 	// stackId := params["stackId"]
 	cardId := params["cardId"]
 	memberId := params["memberId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	updateCardPerm := fmt.Sprintf("%s:board%s:update", orgPrefix, boardId)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canUpdateCard := tokenCustomClaims.HasAnyPermissions(updateCardPerm, boardsAdminPerm)
-	if !canUpdateCard {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to update card %s on board with id: %s", userId, cardId, boardId), http.StatusForbidden)
-		return
-	}
-
 	ctx := request.Context()
 	err := handler.controller.UnassignCardFromUser(ctx, boardId, cardId, memberId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to unassign card with id %s to user with id %s: %s", cardId, memberId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to unassign card with id %s to user with id %s: %w", cardId, memberId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -1326,25 +1124,29 @@ func (handler *boardHandler) UnassignCardFromUser(writer http.ResponseWriter, re
 func (handler *boardHandler) CreateBoardWithAI(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
-	title := request.FormValue("title")
-	description := request.FormValue("description")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
+	description := body.Get("description")
 
-	detailLevel := request.FormValue("detail_level")
-	storyPointType := request.FormValue("story_point_type")
-	storyPointExamples := request.FormValue("story_point_examples")
+	detailLevel := body.Get("detail_level")
+	storyPointType := body.Get("story_point_type")
+	storyPointExamples := body.Get("story_point_examples")
 
 	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
 
-	isPrivateString := request.FormValue("isPrivate")
+	isPrivateString := body.Get("isPrivate")
 	isPrivate := false
-	var err error
 	if isPrivateString != "" {
-		isPrivate, err = strconv.ParseBool(request.FormValue("isPrivate"))
+		isPrivate, err = strconv.ParseBool(body.Get("isPrivate"))
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error()), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse isPrivate: %s", err.Error())))
 			return
 		}
 	}
@@ -1352,41 +1154,43 @@ func (handler *boardHandler) CreateBoardWithAI(writer http.ResponseWriter, reque
 	// Check if AI is enabled
 	aiEnabled, err := handler.controller.CanUseAI(request.Context(), organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to check if AI is enabled: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to check if AI is enabled: %w", err))
 		return
 	}
 	if !aiEnabled {
-		http.Error(writer, fmt.Sprintf("AI is not enabled for organization with id %s", organizationId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("AI is not enabled for organization with id %s", organizationId)))
 		return
 	}
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	createBoardsPerm := fmt.Sprintf("%s:create_boards", orgPrefix)
-	boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-	canCreateBoards := tokenCustomClaims.HasAnyPermissions(createBoardsPerm, boardsAdminPerm)
-	if !canCreateBoards {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create board in org with id: %s", token.RegisteredClaims.Subject, organizationId), http.StatusForbidden)
-		return
-	}
 
-	ctx := request.Context()
-	board, err := handler.controller.CreateBoardWithAI(ctx, userId, title, description, isPrivate, organizationId, detailLevel, storyPointType, storyPointExamples)
+	// The board is generated by a job, which the caller follows with GetBoardGeneration
+	job, err := handler.controller.CreateBoardWithAI(request.Context(), userId, title, description, isPrivate, organizationId, detailLevel, storyPointType, storyPointExamples)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create board: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to queue board generation: %w", err))
 		return
 	}
-	err = handler.controller.InitializeBoard(userId, board.Id.String(), organizationId)
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Location", fmt.Sprintf("/api/organizations/%s/boards/ai/%s", organizationId, job.Id))
+	writer.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(writer).Encode(job)
+}
+
+func (handler *boardHandler) GetBoardGeneration(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	jobId := params["jobId"]
+
+	job, err := handler.controller.GetBoardGeneration(request.Context(), organizationId, jobId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to initialize board: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get board generation %s: %w", jobId, err))
 		return
 	}
-
 	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusCreated)
-	json.NewEncoder(writer).Encode(board)
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(job)
 }
 
 func (handler *boardHandler) GetCompleteCard(writer http.ResponseWriter, request *http.Request) {
@@ -1399,45 +1203,334 @@ func (handler *boardHandler) GetCompleteCard(writer http.ResponseWriter, request
 	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
+
+	ctx := request.Context()
+	board := resourcesFrom(request).Board
+	if board.IsPrivate {
+		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
+		if !canReadBoard {
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
+			return
+		}
+	}
+
+	stack, err := handler.controller.GetCompleteCardById(ctx, cardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get card: %w", err))
 		return
 	}
 
+	setETag(writer, stack.Version)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(stack)
+}
+
+func (handler *boardHandler) GetOrganizationTrash(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	orgPrefix := fmt.Sprintf("org%s", organizationId)
+
 	ctx := request.Context()
-	board, err := handler.controller.GetBoardById(ctx, boardId)
+	deletedBoards, err := handler.controller.GetDeletedBoardsInOrg(ctx, organizationId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No board found with id %s", boardId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get board: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get deleted boards: %w", err))
 		return
 	}
 
+	// Only show boards the user would be allowed to restore
+	restorableBoards := make([]models.Board, 0)
+	for _, board := range *deletedBoards {
+		deleteBoardPerm := fmt.Sprintf("%s:board%s:delete", orgPrefix, board.Id)
+		if tokenCustomClaims.HasPermission(deleteBoardPerm) {
+			restorableBoards = append(restorableBoards, board)
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(restorableBoards)
+}
+
+func (handler *boardHandler) GetBoardTrash(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	userId := token.RegisteredClaims.Subject
+	orgPrefix := fmt.Sprintf("org%s", organizationId)
+
+	ctx := request.Context()
+	board := resourcesFrom(request).Board
 	if board.IsPrivate {
 		readBoardPerm := fmt.Sprintf("%s:board%s:read", orgPrefix, boardId)
-		boardsAdminPerm := fmt.Sprintf("%s:boards_admin", orgPrefix)
-		canReadBoard := tokenCustomClaims.HasAnyPermissions(readBoardPerm, boardsAdminPerm)
+		canReadBoard := tokenCustomClaims.HasPermission(readBoardPerm)
 		if !canReadBoard {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId), http.StatusForbidden)
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
 			return
 		}
 	}
 
-	stack, err := handler.controller.GetCompleteCardById(ctx, cardId)
+	trash, err := handler.controller.GetBoardTrash(ctx, boardId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No card found with id %s", boardId), http.StatusNotFound)
+		writeError(writer, request, fmt.Errorf("Failed to get trash for board with id %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(trash)
+}
+
+func (handler *boardHandler) RestoreBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	ctx := request.Context()
+	board, err := handler.controller.RestoreBoardById(ctx, organizationId, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to restore board with id %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(board)
+}
+
+func (handler *boardHandler) RestorePanel(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	panelId := params["panelId"]
+
+	ctx := request.Context()
+	panel, err := handler.controller.RestorePanelById(ctx, boardId, panelId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to restore panel with id %s: %w", panelId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(panel)
+}
+
+func (handler *boardHandler) RestoreStack(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	stackId := params["stackId"]
+
+	ctx := request.Context()
+	stack, err := handler.controller.RestoreStackById(ctx, boardId, stackId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to restore stack with id %s: %w", stackId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(stack)
+}
+
+func (handler *boardHandler) RestoreCard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	cardId := params["cardId"]
+
+	ctx := request.Context()
+	card, err := handler.controller.RestoreCardById(ctx, boardId, cardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to restore card with id %s: %w", cardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(card)
+}
+
+func (handler *boardHandler) ArchiveBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+
+	ctx := request.Context()
+	board, err := handler.controller.ArchiveBoardById(ctx, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to archive board with id %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(board)
+}
+
+func (handler *boardHandler) UnarchiveBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+
+	ctx := request.Context()
+	board, err := handler.controller.UnarchiveBoardById(ctx, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to unarchive board with id %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(board)
+}
+
+func (handler *boardHandler) PatchBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	var patch models.BoardPatch
+	err = decodePatch(request, &patch)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	patchedBoard, err := handler.controller.PatchBoardById(ctx, organizationId, boardId, version, patch)
+	if err != nil {
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentBoard, err := handler.controller.GetBoardById(ctx, boardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get board with id %s: %w", boardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentBoard, currentBoard.Version)
 		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get card: %s", err.Error()), http.StatusInternalServerError)
+			writeError(writer, request, fmt.Errorf("Failed to update board with id %s: %w", organizationId, err))
 		}
 		return
 	}
+	setETag(writer, patchedBoard.Version)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(patchedBoard)
+}
 
+func (handler *boardHandler) PatchPanel(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	panelId := params["panelId"]
+
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	var patch models.PanelPatch
+	err = decodePatch(request, &patch)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	patchedPanel, err := handler.controller.PatchPanelById(ctx, boardId, panelId, version, patch)
+	if err != nil {
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentPanel, err := handler.controller.GetPanelById(ctx, panelId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get panel with id %s: %w", panelId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentPanel, currentPanel.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update panel with id %s: %w", panelId, err))
+		}
+		return
+	}
+	setETag(writer, patchedPanel.Version)
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
-	json.NewEncoder(writer).Encode(stack)
+	json.NewEncoder(writer).Encode(patchedPanel)
+}
+
+func (handler *boardHandler) PatchStack(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	panelId := params["panelId"]
+	stackId := params["stackId"]
+
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	var patch models.StackPatch
+	err = decodePatch(request, &patch)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	patchedStack, err := handler.controller.PatchStackById(ctx, boardId, panelId, stackId, version, patch)
+	if err != nil {
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentStack, err := handler.controller.GetStackById(ctx, stackId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get stack with id %s: %w", stackId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentStack, currentStack.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update stack with id %s: %w", stackId, err))
+		}
+		return
+	}
+	setETag(writer, patchedStack.Version)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(patchedStack)
+}
+
+func (handler *boardHandler) PatchCard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	stackId := params["stackId"]
+	cardId := params["cardId"]
+
+	version, err := parseIfMatch(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	var patch models.CardPatch
+	err = decodePatch(request, &patch)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	patchedCard, err := handler.controller.PatchCardById(ctx, boardId, stackId, cardId, version, patch)
+	if err != nil {
+		if errors.Is(err, board.ErrVersionMismatch) {
+			currentCard, err := handler.controller.GetCardById(ctx, cardId)
+			if err != nil {
+				writeError(writer, request, fmt.Errorf("Failed to get card with id %s: %w", cardId, err))
+				return
+			}
+			writePreconditionFailed(writer, request, currentCard, currentCard.Version)
+		} else {
+			writeError(writer, request, fmt.Errorf("Failed to update card with id %s: %w", cardId, err))
+		}
+		return
+	}
+	setETag(writer, patchedCard.Version)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(patchedCard)
 }
@@ -0,0 +1,153 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+func isJSONRequest(request *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// readBody returns the fields sent with a POST or PUT request. JSON bodies are flattened into
+// the same shape as form values so handlers work the same for both; query parameters are
+// included just like with request.FormValue.
+func readBody(request *http.Request) (url.Values, error) {
+	if !isJSONRequest(request) {
+		err := request.ParseMultipartForm(32 << 20)
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, invalidBody(err)
+		}
+		return request.Form, nil
+	}
+
+	fields := make(map[string]interface{})
+	decoder := json.NewDecoder(request.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&fields)
+	if err != nil && err != io.EOF {
+		return nil, invalidBody(err)
+	}
+	values := request.URL.Query()
+	for key, value := range fields {
+		values.Del(key)
+		// arrays become repeated values, like a form field sent more than once
+		items, isArray := value.([]interface{})
+		if !isArray {
+			items = []interface{}{value}
+		}
+		for _, item := range items {
+			itemValue, ok := formValue(item)
+			if !ok {
+				return nil, models.FieldErrors{{Field: key, Message: "must be a string, number, boolean, null or an array of them"}}
+			}
+			values.Add(key, itemValue)
+		}
+	}
+	return values, nil
+}
+
+func formValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+type formUnmarshaler interface {
+	UnmarshalForm(value string) error
+}
+
+// decodePatch fills patch from the request body and validates it. Fields left out of the body are
+// left unset. Problems with individual fields are returned together as models.FieldErrors.
+func decodePatch(request *http.Request, patch interface{ Validate() error }) error {
+	fieldErrors := models.FieldErrors{}
+	patchValue := reflect.ValueOf(patch).Elem()
+
+	if isJSONRequest(request) {
+		fields := make(map[string]json.RawMessage)
+		err := json.NewDecoder(request.Body).Decode(&fields)
+		if err != nil && err != io.EOF {
+			return invalidBody(err)
+		}
+		for i := 0; i < patchValue.NumField(); i++ {
+			field := patchValue.Type().Field(i).Tag.Get("json")
+			data, ok := fields[field]
+			if !ok {
+				continue
+			}
+			delete(fields, field)
+			err := json.Unmarshal(data, patchValue.Field(i).Addr().Interface())
+			if err != nil {
+				var typeError *json.UnmarshalTypeError
+				if errors.As(err, &typeError) {
+					fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: fmt.Sprintf("must be of type %s", typeError.Type.String())})
+				} else {
+					fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "has an invalid value"})
+				}
+			}
+		}
+		unknownFields := make([]string, 0, len(fields))
+		for field := range fields {
+			unknownFields = append(unknownFields, field)
+		}
+		sort.Strings(unknownFields)
+		for _, field := range unknownFields {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "is not a known field"})
+		}
+	} else {
+		err := request.ParseMultipartForm(32 << 20)
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return invalidBody(err)
+		}
+		for i := 0; i < patchValue.NumField(); i++ {
+			field := patchValue.Type().Field(i).Tag.Get("json")
+			if _, ok := request.PostForm[field]; !ok {
+				continue
+			}
+			err := patchValue.Field(i).Addr().Interface().(formUnmarshaler).UnmarshalForm(request.PostForm.Get(field))
+			if err != nil {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "has an invalid value"})
+			}
+		}
+	}
+
+	err := patch.Validate()
+	var validationErrors models.FieldErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors = append(fieldErrors, validationErrors...)
+	} else if err != nil {
+		return err
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	return nil
+}
+
+func invalidBody(err error) error {
+	return &controllers.Error{
+		Code:    controllers.CodeBadRequest,
+		Message: fmt.Sprintf("invalid request body: %s", err.Error()),
+		Err:     err,
+	}
+}
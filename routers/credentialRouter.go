@@ -0,0 +1,259 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/credential"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+type credentialHandler struct {
+	router     *mux.Router
+	controller *credential.Controller
+}
+
+func registerPersonalAccessTokenRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &credentialHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: credential.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handleSecured(handler.router, handler.personalAccessTokenRoutes())
+
+	return handler.router
+}
+
+func registerAPIKeyRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &credentialHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: credential.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handleSecured(handler.router, handler.apiKeyRoutes())
+
+	return handler.router
+}
+
+// personalAccessTokenRoutes don't need any permissions, since users can only manage their own
+// tokens, which the handlers check against the path
+func (handler *credentialHandler) personalAccessTokenRoutes() []securedRoute {
+	tokensPrefix := fmt.Sprintf("%s/{userId}/tokens", usersPrefix)
+	return []securedRoute{
+		{"GET", tokensPrefix, handler.GetPersonalAccessTokens, permissions{}},
+		{"POST", tokensPrefix, handler.CreatePersonalAccessToken, permissions{}},
+		{"DELETE", fmt.Sprintf("%s/{tokenId}", tokensPrefix), handler.DeletePersonalAccessToken, permissions{}},
+	}
+}
+
+// apiKeyRoutes are managed by whoever can update the organization
+func (handler *credentialHandler) apiKeyRoutes() []securedRoute {
+	manageKeys := permissions{anyOf("org{organizationId}:update")}
+	apiKeysPrefix := fmt.Sprintf("%s/{organizationId}/api-keys", organizationsPrefix)
+	return []securedRoute{
+		{"GET", apiKeysPrefix, handler.GetAPIKeys, manageKeys},
+		{"POST", apiKeysPrefix, handler.CreateAPIKey, manageKeys},
+		{"DELETE", fmt.Sprintf("%s/{keyId}", apiKeysPrefix), handler.DeleteAPIKey, manageKeys},
+	}
+}
+
+// credentialInput is what is sent to create a personal access token or API key
+type credentialInput struct {
+	name        string
+	permissions []string
+	expiresAt   *time.Time
+}
+
+func readCredentialInput(request *http.Request) (*credentialInput, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return nil, err
+	}
+	input := &credentialInput{
+		name:        body.Get("name"),
+		permissions: body["permissions"],
+	}
+	if input.name == "" {
+		return nil, controllers.NewError(controllers.CodeBadRequest, "No name Found")
+	}
+	if len(input.permissions) == 0 {
+		return nil, controllers.NewError(controllers.CodeBadRequest, "No permissions Found")
+	}
+	if expiresAtString := body.Get("expires_at"); expiresAtString != "" {
+		expiresAt, err := time.Parse(time.RFC3339, expiresAtString)
+		if err != nil {
+			return nil, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse expires_at: %s", err.Error()))
+		}
+		if !expiresAt.After(time.Now()) {
+			return nil, controllers.NewError(controllers.CodeBadRequest, "expires_at must be in the future")
+		}
+		input.expiresAt = &expiresAt
+	}
+	return input, nil
+}
+
+// checkCanIssue makes sure a token or key is only created by someone signed in with a JWT, and only
+// with permissions they have themselves
+func checkCanIssue(token *validator.ValidatedClaims, permissions []string) error {
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	if tokenCustomClaims.TokenId != "" {
+		return controllers.NewError(controllers.CodeForbidden, "Personal access tokens and API keys can't be used to create other tokens")
+	}
+	for _, permission := range permissions {
+		if !tokenCustomClaims.HasPermission(permission) {
+			return controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s can't give a token the permission %s since they don't have it", token.RegisteredClaims.Subject, permission))
+		}
+	}
+	return nil
+}
+
+func (handler *credentialHandler) GetPersonalAccessTokens(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	userId := params["userId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	signedInUserId := token.RegisteredClaims.Subject
+	if signedInUserId != userId {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get tokens for user with id %s", signedInUserId, userId)))
+		return
+	}
+
+	ctx := request.Context()
+	tokens, err := handler.controller.GetPersonalAccessTokens(ctx, userId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get tokens for user with id %s: %w", userId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(tokens)
+}
+
+func (handler *credentialHandler) CreatePersonalAccessToken(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	userId := params["userId"]
+
+	input, err := readCredentialInput(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	signedInUserId := token.RegisteredClaims.Subject
+	if signedInUserId != userId {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to create tokens for user with id %s", signedInUserId, userId)))
+		return
+	}
+	err = checkCanIssue(token, input.permissions)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	created, err := handler.controller.CreatePersonalAccessToken(ctx, userId, input.name, input.permissions, input.expiresAt)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to create token for user with id %s: %w", userId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *credentialHandler) DeletePersonalAccessToken(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	userId := params["userId"]
+	tokenId := params["tokenId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	signedInUserId := token.RegisteredClaims.Subject
+	if signedInUserId != userId {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to delete tokens for user with id %s", signedInUserId, userId)))
+		return
+	}
+
+	ctx := request.Context()
+	err := handler.controller.DeletePersonalAccessToken(ctx, userId, tokenId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to delete token with id %s: %w", tokenId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *credentialHandler) GetAPIKeys(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	keys, err := handler.controller.GetAPIKeys(ctx, organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get API keys for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(keys)
+}
+
+func (handler *credentialHandler) CreateAPIKey(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	input, err := readCredentialInput(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	orgPrefix := fmt.Sprintf("org%s:", organizationId)
+	for _, permission := range input.permissions {
+		if !strings.HasPrefix(permission, orgPrefix) {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("API keys for organization %s can only have its permissions, not %s", organizationId, permission)))
+			return
+		}
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	err = checkCanIssue(token, input.permissions)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	ctx := request.Context()
+	created, err := handler.controller.CreateAPIKey(ctx, organizationId, userId, input.name, input.permissions, input.expiresAt)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to create API key for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *credentialHandler) DeleteAPIKey(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	keyId := params["keyId"]
+
+	ctx := request.Context()
+	err := handler.controller.DeleteAPIKey(ctx, organizationId, keyId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to delete API key with id %s: %w", keyId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+type requestIdKey struct{}
+
+type errorResponse struct {
+	Code      controllers.ErrorCode `json:"code"`
+	Message   string                `json:"message"`
+	Details   interface{}           `json:"details"`
+	RequestId string                `json:"request_id"`
+}
+
+var errorStatuses = map[controllers.ErrorCode]int{
+	controllers.CodeBadRequest:           http.StatusBadRequest,
+	controllers.CodeValidationFailed:     http.StatusUnprocessableEntity,
+	controllers.CodeUnauthorized:         http.StatusUnauthorized,
+	controllers.CodeForbidden:            http.StatusForbidden,
+	controllers.CodeNotFound:             http.StatusNotFound,
+	controllers.CodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	controllers.CodeConflict:             http.StatusConflict,
+	controllers.CodeBoardArchived:        http.StatusConflict,
+	controllers.CodeParentInTrash:        http.StatusConflict,
+	controllers.CodeVersionMismatch:      http.StatusPreconditionFailed,
+	controllers.CodePreconditionRequired: http.StatusPreconditionRequired,
+	controllers.CodeInternal:             http.StatusInternalServerError,
+}
+
+// withRequestId tags every request with an id, reusing the caller's X-Request-Id if they sent one,
+// so that error responses can be matched up with the server logs
+func withRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestId := request.Header.Get(requestIdHeader)
+		if requestId == "" || len(requestId) > 128 {
+			requestId = uuid.New().String()
+		}
+		writer.Header().Set(requestIdHeader, requestId)
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), requestIdKey{}, requestId)))
+	})
+}
+
+func requestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// writeError is the only place errors are written to clients. The status and code come from the
+// first controllers.Error in err's chain; anything else is an internal error.
+func writeError(writer http.ResponseWriter, request *http.Request, err error) {
+	response := errorResponse{
+		Code:      controllers.CodeInternal,
+		Message:   err.Error(),
+		RequestId: requestIdFromContext(request.Context()),
+	}
+	var fieldErrors models.FieldErrors
+	var domainError *controllers.Error
+	if errors.As(err, &fieldErrors) {
+		response.Code = controllers.CodeValidationFailed
+		response.Details = fieldErrors
+	} else if errors.As(err, &domainError) {
+		response.Code = domainError.Code
+		response.Details = domainError.Details
+	}
+	status, ok := errorStatuses[response.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("Request %s failed: %v", response.RequestId, err)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+)
+
+var errMissingIfMatch = controllers.NewError(controllers.CodePreconditionRequired, "If-Match header is required")
+
+// setETag sets the ETag header to the version of the board, panel, stack or card being returned
+func setETag(writer http.ResponseWriter, version int) {
+	writer.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseIfMatch reads the version the client last saw from the If-Match header. PUT and DELETE
+// on boards, panels, stacks and cards require it so edits can't silently overwrite each other.
+func parseIfMatch(request *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(request.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return 0, errMissingIfMatch
+	}
+	ifMatch = strings.TrimPrefix(ifMatch, "W/")
+	version, err := strconv.Atoi(strings.Trim(ifMatch, `"`))
+	if err != nil {
+		return 0, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("invalid If-Match header %s", ifMatch))
+	}
+	return version, nil
+}
+
+// writePreconditionFailed responds with the current representation of something that was changed
+// after the client fetched it, so the client can merge their edit and try again
+func writePreconditionFailed(writer http.ResponseWriter, request *http.Request, current interface{}, version int) {
+	setETag(writer, version)
+	writeError(writer, request, &controllers.Error{
+		Code:    controllers.CodeVersionMismatch,
+		Message: "version does not match the current version, which is in details",
+		Details: current,
+	})
+}
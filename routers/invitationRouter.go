@@ -0,0 +1,442 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/invitation"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+type invitationHandler struct {
+	router     *mux.Router
+	controller *invitation.Controller
+}
+
+func newInvitationHandler(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *invitationHandler {
+	store := store.NewPostgres(db)
+	// Invitations are sent by the job workers, so a mail server being down doesn't fail the request
+	sender := jobs.NewMailSender(jobs.NewQueue(store.Jobs))
+	return &invitationHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: invitation.NewController(cfg, store, sender),
+	}
+}
+
+func registerInvitationRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := newInvitationHandler(parentRouter, cfg, db)
+
+	handleSecured(handler.router, handler.routes())
+
+	return handler.router
+}
+
+// registerInvitationResponseRoutes are for invitees, who only have the token from their email or
+// invite link and aren't members of the organization yet
+func registerInvitationResponseRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := newInvitationHandler(parentRouter, cfg, db)
+
+	handleSecured(handler.router, handler.responseRoutes())
+	// declining doesn't need an account, since the invitee might not want one
+	handler.router.HandleFunc(fmt.Sprintf("%s/decline", invitationsPrefix), handler.DeclineInvitation).Methods("POST")
+
+	return handler.router
+}
+
+// routes lists the organization's invitation routes with the permissions they need. Inviting
+// someone straight into a role also needs the permissions for adding members to it, which
+// checkCanGrantRole checks once the role is read from the body.
+func (handler *invitationHandler) routes() []securedRoute {
+	addMembers := permissions{anyOf("org{organizationId}:add_members")}
+	organizationPrefix := fmt.Sprintf("%s/{organizationId}", organizationsPrefix)
+	return []securedRoute{
+		{"GET", fmt.Sprintf("%s/invitations", organizationPrefix), handler.GetInvitations, addMembers},
+		{"POST", fmt.Sprintf("%s/invitations", organizationPrefix), handler.CreateInvitation, addMembers},
+		{"DELETE", fmt.Sprintf("%s/invitations/{invitationId}", organizationPrefix), handler.RevokeInvitation, addMembers},
+		{"POST", fmt.Sprintf("%s/invitations/{invitationId}/resend", organizationPrefix), handler.ResendInvitation, addMembers},
+		{"GET", fmt.Sprintf("%s/invite-links", organizationPrefix), handler.GetInviteLinks, addMembers},
+		{"POST", fmt.Sprintf("%s/invite-links", organizationPrefix), handler.CreateInviteLink, addMembers},
+		{"DELETE", fmt.Sprintf("%s/invite-links/{linkId}", organizationPrefix), handler.DeleteInviteLink, addMembers},
+		{"GET", fmt.Sprintf("%s/domains", organizationPrefix), handler.GetDomains, addMembers},
+		{"POST", fmt.Sprintf("%s/domains", organizationPrefix), handler.AddDomain, addMembers},
+		{"DELETE", fmt.Sprintf("%s/domains/{domain}", organizationPrefix), handler.RemoveDomain, addMembers},
+		{"POST", fmt.Sprintf("%s/domains/{domain}/verify", organizationPrefix), handler.VerifyDomain, addMembers},
+	}
+}
+
+// responseRoutes only need a signed in user, since the token they're given is what lets them in
+func (handler *invitationHandler) responseRoutes() []securedRoute {
+	return []securedRoute{
+		{"POST", fmt.Sprintf("%s/accept", invitationsPrefix), handler.AcceptInvitation, permissions{}},
+		{"POST", fmt.Sprintf("%s/links/join", invitationsPrefix), handler.JoinWithInviteLink, permissions{}},
+		{"POST", fmt.Sprintf("%s/domains/join", invitationsPrefix), handler.JoinByDomain, permissions{}},
+	}
+}
+
+// checkCanGrantRole makes sure the caller can hand out roleId, if one is given, the same as adding
+// a member to the role directly. roleId comes from the body rather than the path, so the
+// permissions are built here instead of being filled in like the route tables'.
+func checkCanGrantRole(request *http.Request, roleId string) error {
+	if roleId == "" {
+		return nil
+	}
+	organizationId := mux.Vars(request)["organizationId"]
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	userId := token.RegisteredClaims.Subject
+	addRolePerm := fmt.Sprintf("org%s:role%s:add_member", organizationId, roleId)
+	addRolesPerm := fmt.Sprintf("org%s:add_roles", organizationId)
+	if !tokenCustomClaims.HasAnyPermissions(addRolePerm, addRolesPerm) {
+		return controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to invite users to role with id: %s", userId, roleId))
+	}
+	return nil
+}
+
+func (handler *invitationHandler) GetInvitations(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	invitations, err := handler.controller.GetPendingInvitations(ctx, organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get invitations for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(invitations)
+}
+
+func (handler *invitationHandler) CreateInvitation(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	email := body.Get("email")
+	if email == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Email Found"))
+		return
+	}
+	roleId := body.Get("role_id")
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	err = checkCanGrantRole(request, roleId)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	var role *string
+	if roleId != "" {
+		role = &roleId
+	}
+	ctx := request.Context()
+	created, err := handler.controller.CreateInvitation(ctx, organizationId, userId, email, role)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to invite %s to organization %s: %w", email, organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *invitationHandler) ResendInvitation(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	invitationId := params["invitationId"]
+
+	ctx := request.Context()
+	resent, err := handler.controller.ResendInvitation(ctx, organizationId, invitationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to resend invitation with id %s: %w", invitationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(resent)
+}
+
+func (handler *invitationHandler) RevokeInvitation(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	invitationId := params["invitationId"]
+
+	ctx := request.Context()
+	err := handler.controller.RevokeInvitation(ctx, organizationId, invitationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to revoke invitation with id %s: %w", invitationId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *invitationHandler) AcceptInvitation(writer http.ResponseWriter, request *http.Request) {
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	invitationToken := body.Get("token")
+	if invitationToken == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Token Found"))
+		return
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	ctx := request.Context()
+	accepted, err := handler.controller.AcceptInvitation(ctx, invitationToken, userId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to accept invitation: %w", err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(accepted)
+}
+
+func (handler *invitationHandler) DeclineInvitation(writer http.ResponseWriter, request *http.Request) {
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	invitationToken := body.Get("token")
+	if invitationToken == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Token Found"))
+		return
+	}
+
+	ctx := request.Context()
+	err = handler.controller.DeclineInvitation(ctx, invitationToken)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to decline invitation: %w", err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *invitationHandler) GetInviteLinks(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	links, err := handler.controller.GetInviteLinks(ctx, organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get invite links for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(links)
+}
+
+func (handler *invitationHandler) CreateInviteLink(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	roleId := body.Get("role_id")
+	var maxUses *int
+	if maxUsesString := body.Get("max_uses"); maxUsesString != "" {
+		parsed, err := strconv.Atoi(maxUsesString)
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse max_uses: %s", err.Error())))
+			return
+		}
+		maxUses = &parsed
+	}
+	var expiresAt *time.Time
+	if expiresAtString := body.Get("expires_at"); expiresAtString != "" {
+		parsed, err := time.Parse(time.RFC3339, expiresAtString)
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse expires_at: %s", err.Error())))
+			return
+		}
+		if !parsed.After(time.Now()) {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "expires_at must be in the future"))
+			return
+		}
+		expiresAt = &parsed
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	err = checkCanGrantRole(request, roleId)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	var role *string
+	if roleId != "" {
+		role = &roleId
+	}
+	ctx := request.Context()
+	created, err := handler.controller.CreateInviteLink(ctx, organizationId, userId, role, maxUses, expiresAt)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to create invite link for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *invitationHandler) DeleteInviteLink(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	linkId := params["linkId"]
+
+	ctx := request.Context()
+	err := handler.controller.DeleteInviteLink(ctx, organizationId, linkId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to delete invite link with id %s: %w", linkId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *invitationHandler) GetDomains(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	domains, err := handler.controller.GetDomains(ctx, organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get domains for organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(domains)
+}
+
+func (handler *invitationHandler) AddDomain(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	domain := body.Get("domain")
+	if domain == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Domain Found"))
+		return
+	}
+	roleId := body.Get("role_id")
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	err = checkCanGrantRole(request, roleId)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+
+	var role *string
+	if roleId != "" {
+		role = &roleId
+	}
+	ctx := request.Context()
+	added, err := handler.controller.AddDomain(ctx, organizationId, userId, domain, role)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to add domain %s to organization %s: %w", domain, organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(added)
+}
+
+func (handler *invitationHandler) RemoveDomain(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	domain := params["domain"]
+
+	ctx := request.Context()
+	err := handler.controller.RemoveDomain(ctx, organizationId, domain)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to remove domain %s from organization %s: %w", domain, organizationId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *invitationHandler) VerifyDomain(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	domain := params["domain"]
+
+	ctx := request.Context()
+	verified, err := handler.controller.VerifyDomain(ctx, organizationId, domain)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to verify domain %s of organization %s: %w", domain, organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(verified)
+}
+
+func (handler *invitationHandler) JoinWithInviteLink(writer http.ResponseWriter, request *http.Request) {
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	linkToken := body.Get("token")
+	if linkToken == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Token Found"))
+		return
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	ctx := request.Context()
+	organization, err := handler.controller.JoinWithInviteLink(ctx, linkToken, userId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to join with invite link: %w", err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(organization)
+}
+
+func (handler *invitationHandler) JoinByDomain(writer http.ResponseWriter, request *http.Request) {
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	ctx := request.Context()
+	organizations, err := handler.controller.JoinByDomain(ctx, userId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to join organizations by email domain: %w", err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(organizations)
+}
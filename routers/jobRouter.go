@@ -0,0 +1,95 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/jobs"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	"github.com/gorilla/mux"
+)
+
+// How many jobs are listed when the request doesn't say, and the most it can ask for
+const (
+	defaultJobLimit = 50
+	maxJobLimit     = 200
+)
+
+type jobHandler struct {
+	router     *mux.Router
+	controller *jobs.Queue
+}
+
+func registerJobRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &jobHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: jobs.NewQueue(store.NewPostgres(db).Jobs),
+	}
+
+	handleSecured(handler.router, handler.routes())
+
+	return handler.router
+}
+
+// Jobs aren't tied to an organization, so these routes need permissions that are only given to the
+// people running the server
+func (handler *jobHandler) routes() []securedRoute {
+	return []securedRoute{
+		{"GET", jobsPrefix, handler.ListJobs, permissions{anyOf("admin:read_jobs")}},
+		{"GET", fmt.Sprintf("%s/{jobId}", jobsPrefix), handler.GetJob, permissions{anyOf("admin:read_jobs")}},
+		{"POST", fmt.Sprintf("%s/{jobId}/retry", jobsPrefix), handler.RetryJob, permissions{anyOf("admin:retry_jobs")}},
+	}
+}
+
+func (handler *jobHandler) ListJobs(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	limit := defaultJobLimit
+	if limitString := query.Get("limit"); limitString != "" {
+		parsed, err := strconv.Atoi(limitString)
+		if err != nil || parsed < 1 || parsed > maxJobLimit {
+			writeError(writer, request, controllers.NewError(controllers.CodeValidationFailed, fmt.Sprintf("limit must be a number from 1 to %d", maxJobLimit)))
+			return
+		}
+		limit = parsed
+	}
+
+	jobList, err := handler.controller.ListJobs(request.Context(), query.Get("status"), limit)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to list jobs: %w", err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(jobList)
+}
+
+func (handler *jobHandler) GetJob(writer http.ResponseWriter, request *http.Request) {
+	jobId := mux.Vars(request)["jobId"]
+
+	job, err := handler.controller.GetJob(request.Context(), jobId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get job %s: %w", jobId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(job)
+}
+
+func (handler *jobHandler) RetryJob(writer http.ResponseWriter, request *http.Request) {
+	jobId := mux.Vars(request)["jobId"]
+
+	job, err := handler.controller.RetryJob(request.Context(), jobId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to retry job %s: %w", jobId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(job)
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+const openAPIPath = "/api/openapi.json"
+
+// openAPISpec describes every route in the API along with the permissions each one needs. It has to
+// be kept up to date by hand when routes change; TestOpenAPISpecMatchesRoutes fails when it isn't.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func getOpenAPISpec(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(openAPISpec)
+}
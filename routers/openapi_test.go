@@ -0,0 +1,124 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+)
+
+type openAPIDocument struct {
+	Paths map[string]map[string]struct {
+		OperationId string          `json:"operationId"`
+		Security    *[]interface{}  `json:"security"`
+		Permissions [][]string      `json:"x-permissions"`
+		Responses   json.RawMessage `json:"responses"`
+	} `json:"paths"`
+}
+
+func loadOpenAPISpec(t *testing.T) openAPIDocument {
+	t.Helper()
+	var spec openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return spec
+}
+
+func testRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	return newRouter(cfg, nil)
+}
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	router := testRouter(t)
+
+	seen := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// subrouters and path prefixes have no methods of their own
+			return nil
+		}
+		for _, method := range methods {
+			if seen[method+" "+path] {
+				continue
+			}
+			seen[method+" "+path] = true
+			operation, ok := spec.Paths[path][strings.ToLower(method)]
+			if !ok {
+				t.Errorf("%s %s is not in openapi.json", method, path)
+				continue
+			}
+			if operation.OperationId == "" {
+				t.Errorf("%s %s has no operationId in openapi.json", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk routes: %v", err)
+	}
+	if len(seen) == 0 {
+		t.Fatal("no routes were found")
+	}
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestOpenAPISpecHasNoStaleRoutes(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	router := testRouter(t)
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			request := httptest.NewRequest(strings.ToUpper(method), pathParam.ReplaceAllString(path, "id"), nil)
+			var match mux.RouteMatch
+			if !router.Match(request, &match) || match.MatchErr != nil {
+				t.Errorf("%s %s is in openapi.json but no route handles it", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPISpecListsPermissions(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			public := operation.Security != nil && len(*operation.Security) == 0
+			if !public && operation.Permissions == nil {
+				t.Errorf("%s %s needs a token but has no x-permissions in openapi.json", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPISpecIsServed(t *testing.T) {
+	router := testRouter(t)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", contentType)
+	}
+	if !json.Valid(recorder.Body.Bytes()) {
+		t.Error("response is not valid JSON")
+	}
+}
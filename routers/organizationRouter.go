@@ -1,7 +1,6 @@
 package routers
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,11 +10,13 @@ import (
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/gorilla/mux"
 
-	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/organization"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/Sync-Space-49/syncspace-server/store"
 )
 
 type organizationHandler struct {
@@ -26,35 +27,57 @@ type organizationHandler struct {
 func registerOrganizationRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
 	handler := &organizationHandler{
 		router:     parentRouter.NewRoute().Subrouter(),
-		controller: organization.NewController(cfg, db),
+		controller: organization.NewController(cfg, store.NewPostgres(db)),
 	}
-	handler.router.Handle(organizationsPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.CreateOrganization))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetOrganization))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdateOrganization))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeleteOrganization))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/members", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetOrganizationMembers))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/members", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.AddMemberToOrganization))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/members/{memberId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.RemoveMemberFromOrganization))).Methods("DELETE")
+	handleSecured(handler.router, handler.routes())
 	handler.router.PathPrefix("{organizationId}/roles").Handler(registerRoleRoutes(handler.router, cfg, db))
 	handler.router.PathPrefix("{organizationId}/boards").Handler(registerBoardRoutes(handler.router, cfg, db))
+	handler.router.PathPrefix("{organizationId}/boards/{boardId}/shares").Handler(registerShareRoutes(handler.router, cfg, db))
+	handler.router.PathPrefix("{organizationId}/api-keys").Handler(registerAPIKeyRoutes(handler.router, cfg, db))
+	handler.router.PathPrefix("{organizationId}/invitations").Handler(registerInvitationRoutes(handler.router, cfg, db))
+	handler.router.PathPrefix("{organizationId}/teams").Handler(registerTeamRoutes(handler.router, cfg, db))
+	handler.router.PathPrefix("{organizationId}/boards/{boardId}/roles").Handler(registerBoardRoleRoutes(handler.router, cfg, db))
 	return handler.router
 }
 
+// routes lists every organization route with the permissions it needs. Anyone signed in can create
+// an organization.
+func (handler *organizationHandler) routes() []securedRoute {
+	organizationPrefix := fmt.Sprintf("%s/{organizationId}", organizationsPrefix)
+	return []securedRoute{
+		{"POST", organizationsPrefix, handler.CreateOrganization, permissions{}},
+		{"GET", organizationPrefix, handler.GetOrganization, permissions{readOrg}},
+		{"PUT", organizationPrefix, handler.UpdateOrganization, permissions{anyOf("org{organizationId}:update")}},
+		{"PATCH", organizationPrefix, handler.PatchOrganization, permissions{anyOf("org{organizationId}:update")}},
+		{"DELETE", organizationPrefix, handler.DeleteOrganization, permissions{anyOf("org{organizationId}:delete")}},
+		{"GET", fmt.Sprintf("%s/members", organizationPrefix), handler.GetOrganizationMembers, permissions{readOrg}},
+		{"POST", fmt.Sprintf("%s/members", organizationPrefix), handler.AddMemberToOrganization, permissions{anyOf("org{organizationId}:add_members")}},
+		// Anyone in the organization can leave it, so the handler checks remove_members for everyone else
+		{"DELETE", fmt.Sprintf("%s/members/{memberId}", organizationPrefix), handler.RemoveMemberFromOrganization, permissions{readOrg}},
+		{"GET", fmt.Sprintf("%s/guests", organizationPrefix), handler.GetOrganizationGuests, permissions{readOrg}},
+	}
+}
+
 func (handler *organizationHandler) CreateOrganization(writer http.ResponseWriter, request *http.Request) {
-	title := request.FormValue("title")
-	description := request.FormValue("description")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
+	description := body.Get("description")
 	if title == "" {
-		http.Error(writer, "No Title Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Title Found"))
 		return
 	}
 	// addition of aiEnabledString variable allows for us to default to 'false' if '' is passed
-	aiEnabledString := request.FormValue("ai_enabled")
+	aiEnabledString := body.Get("ai_enabled")
 	if aiEnabledString == "" {
 		aiEnabledString = "false"
 	}
 	aiEnabled, err := strconv.ParseBool(aiEnabledString)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to parse aiEnabledString: %s", err.Error()), http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse aiEnabledString: %s", err.Error())))
 		return
 	}
 
@@ -63,18 +86,13 @@ func (handler *organizationHandler) CreateOrganization(writer http.ResponseWrite
 	ctx := request.Context()
 	org, err := handler.controller.CreateOrganization(ctx, userId, title, &description, aiEnabled)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create organization: %s", err.Error()), http.StatusInternalServerError)
-		return
-	}
-
-	err = handler.controller.InitializeOrganization(userId, org.Id.String())
-	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to initialize organization: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create organization: %w", err))
 		return
 	}
 
+	// The organization's roles are set up by a job, so it is still provisioning
 	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusCreated)
+	writer.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(writer).Encode(org)
 }
 
@@ -82,23 +100,10 @@ func (handler *organizationHandler) GetOrganization(writer http.ResponseWriter,
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User does not have permission to read organization with id: %s", organizationId), http.StatusForbidden)
-		return
-	}
-
 	ctx := request.Context()
 	org, err := handler.controller.GetOrganizationById(ctx, organizationId)
 	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			http.Error(writer, fmt.Sprintf("No organization found with id %s", organizationId), http.StatusNotFound)
-		} else {
-			http.Error(writer, fmt.Sprintf("Failed to get organization: %s", err.Error()), http.StatusInternalServerError)
-		}
+		writeError(writer, request, fmt.Errorf("Failed to get organization: %w", err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -110,32 +115,28 @@ func (handler *organizationHandler) UpdateOrganization(writer http.ResponseWrite
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	title := request.FormValue("title")
-	description := request.FormValue("description")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	title := body.Get("title")
+	description := body.Get("description")
 	// addition of aiEnabledString variable allows for us to default to 'false' if '' is passed
-	aiEnabledString := request.FormValue("ai_enabled")
+	aiEnabledString := body.Get("ai_enabled")
 	if aiEnabledString == "" {
 		aiEnabledString = "false"
 	}
 	aiEnabled, err := strconv.ParseBool(aiEnabledString)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to parse aiEnabledString: %s", err.Error()), http.StatusBadRequest)
-		return
-	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	updateOrgPerm := fmt.Sprintf("org%s:update", organizationId)
-	canUpdateOrg := tokenCustomClaims.HasPermission(updateOrgPerm)
-	if !canUpdateOrg {
-		http.Error(writer, fmt.Sprintf("User does not have permission to update organization with id: %s", organizationId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse aiEnabledString: %s", err.Error())))
 		return
 	}
 
 	ctx := request.Context()
 	err = handler.controller.UpdateOrganizationById(ctx, organizationId, title, description, aiEnabled)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update organization with id %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to update organization with id %s: %w", organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -143,55 +144,38 @@ func (handler *organizationHandler) UpdateOrganization(writer http.ResponseWrite
 
 }
 
-func (handler *organizationHandler) DeleteOrganization(writer http.ResponseWriter, request *http.Request) {
+func (handler *organizationHandler) PatchOrganization(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	deleteOrgPerm := fmt.Sprintf("org%s:delete", organizationId)
-	canDeleteOrg := tokenCustomClaims.HasPermission(deleteOrgPerm)
-	if !canDeleteOrg {
-		http.Error(writer, fmt.Sprintf("User does not have permission to delete organization with id: %s", organizationId), http.StatusForbidden)
+	var patch models.OrganizationPatch
+	err := decodePatch(request, &patch)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
 
 	ctx := request.Context()
-	err := handler.controller.DeleteOrganizationById(ctx, organizationId)
+	org, err := handler.controller.PatchOrganizationById(ctx, organizationId, patch)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete organization with id %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to update organization with id %s: %w", organizationId, err))
 		return
 	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(org)
+}
 
-	orgRolePrefix := fmt.Sprintf("org%s:", organizationId)
-	orgRoles, err := auth.GetRoles(&orgRolePrefix)
+func (handler *organizationHandler) DeleteOrganization(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	err := handler.controller.DeleteOrganizationById(ctx, organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get roles for organization %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
-		return
-	}
-	if len(*orgRoles) == 0 {
-		http.Error(writer, fmt.Sprintf("No roles found for organization %s", organizationId), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to delete organization with id %s: %w", organizationId, err))
 		return
 	}
-	for _, role := range *orgRoles {
-		permissions, err := auth.GetRolePermissions(role.Id)
-		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to get permissions for role %s: %s", role.Id, err.Error()), http.StatusInternalServerError)
-			return
-		}
-		if len(*permissions) != 0 {
-			err = auth.DeletePermissions(*permissions)
-			if err != nil {
-				http.Error(writer, fmt.Sprintf("Failed to delete permissions for role %s: %s", role.Id, err.Error()), http.StatusInternalServerError)
-				return
-			}
-		}
-		err = auth.DeleteRole(role.Id)
-		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to delete role %s: %s", role.Id, err.Error()), http.StatusInternalServerError)
-			return
-		}
-	}
 
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusNoContent)
@@ -201,18 +185,23 @@ func (handler *organizationHandler) GetOrganizationMembers(writer http.ResponseW
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User does not have permission to read organization with id: %s", organizationId), http.StatusForbidden)
+	users, err := user.GetOrgMembers(organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get users in org with id %s: %w", organizationId, err))
 		return
 	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(users)
+}
 
-	users, err := user.GetOrgMembers(organizationId)
+func (handler *organizationHandler) GetOrganizationGuests(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	users, err := user.GetOrgGuests(organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users in org with id %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get guests in org with id %s: %w", organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -224,24 +213,20 @@ func (handler *organizationHandler) AddMemberToOrganization(writer http.Response
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	newMemberId := request.FormValue("user_id")
-	if newMemberId == "" {
-		http.Error(writer, "No User ID Found", http.StatusBadRequest)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	addUsersPerm := fmt.Sprintf("org%s:add_members", organizationId)
-	canAddUsers := tokenCustomClaims.HasPermission(addUsersPerm)
-	if !canAddUsers {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add users to organization with id: %s", newMemberId, organizationId), http.StatusForbidden)
+	newMemberId := body.Get("user_id")
+	if newMemberId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No User ID Found"))
 		return
 	}
 
-	err := handler.controller.AddMember(newMemberId, organizationId)
+	err = handler.controller.AddMember(newMemberId, organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to add user with id %s to org with id %s: %s", newMemberId, organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add user with id %s to org with id %s: %w", newMemberId, organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -254,18 +239,15 @@ func (handler *organizationHandler) RemoveMemberFromOrganization(writer http.Res
 	memberId := params["memberId"]
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
-	removeUsersPerm := fmt.Sprintf("org%s:remove_members", organizationId)
-	canRemoveUsers := tokenCustomClaims.HasPermission(removeUsersPerm)
-	if !canRemoveUsers && userId != memberId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to remove users from organization with id: %s", userId, organizationId), http.StatusForbidden)
+	if userId != memberId && !allowed(request, "org{organizationId}:remove_members") {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to remove users from organization with id: %s", userId, organizationId)))
 		return
 	}
 
 	err := handler.controller.RemoveMember(memberId, organizationId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to remove user with id %s from org with id %s: %s", memberId, organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to remove user with id %s from org with id %s: %w", memberId, organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+)
+
+type resourcesKey struct{}
+
+// withResources loads the board, panel, stack and card in the path before next runs and stops the
+// request with a 404 when any of them isn't in the one before it. It goes after the permission
+// check so nobody learns what exists on a board they can't reach. includeTrash is for the restore
+// routes, where the thing being restored is in the trash.
+func withResources(controller *board.Controller, includeTrash bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		params := mux.Vars(request)
+		ids := board.ResourceIds{
+			OrganizationId: params["organizationId"],
+			BoardId:        params["boardId"],
+			PanelId:        params["panelId"],
+			StackId:        params["stackId"],
+			CardId:         params["cardId"],
+		}
+		resources, err := controller.ResolveResources(request.Context(), ids, includeTrash)
+		if err != nil {
+			writeError(writer, request, err)
+			return
+		}
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), resourcesKey{}, resources)))
+	}
+}
+
+// resourcesFrom returns what withResources loaded for the request
+func resourcesFrom(request *http.Request) *board.Resources {
+	resources, _ := request.Context().Value(resourcesKey{}).(*board.Resources)
+	return resources
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/db"
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
@@ -24,38 +25,38 @@ func registerRoleRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB)
 		router: parentRouter.NewRoute().Subrouter(),
 	}
 
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetOrganizationRoles))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/permissions", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetOrganizationPermissions))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.CreateRole))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetRole))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.UpdateRole))).Methods("PUT")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.DeleteRole))).Methods("DELETE")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}/permissions", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetRolePermissions))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}/members", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetMembersWithRole))).Methods("GET")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}/members", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.AddMemberToRole))).Methods("POST")
-	handler.router.Handle(fmt.Sprintf("%s/{organizationId}/roles/{roleId}/members/{memberId}", organizationsPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.RemoveMemberFromRole))).Methods("DELETE")
+	handleSecured(handler.router, handler.routes())
 
 	return handler.router
 }
 
+// routes lists every organization role route with the permissions it needs. A role's members can
+// be changed with org<id>:add_roles or the role's own permissions, which CreateRole sets up.
+func (handler *roleHandler) routes() []securedRoute {
+	rolesPrefix := fmt.Sprintf("%s/{organizationId}/roles", organizationsPrefix)
+	return []securedRoute{
+		{"GET", rolesPrefix, handler.GetOrganizationRoles, permissions{readOrg}},
+		{"GET", fmt.Sprintf("%s/permissions", rolesPrefix), handler.GetOrganizationPermissions, permissions{readOrg}},
+		{"POST", rolesPrefix, handler.CreateRole, permissions{anyOf("org{organizationId}:create_roles")}},
+		{"GET", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.GetRole, permissions{readOrg}},
+		{"PUT", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.UpdateRole, permissions{anyOf("org{organizationId}:edit_roles")}},
+		{"DELETE", fmt.Sprintf("%s/{roleId}", rolesPrefix), handler.DeleteRole, permissions{anyOf("org{organizationId}:delete_roles")}},
+		{"GET", fmt.Sprintf("%s/{roleId}/permissions", rolesPrefix), handler.GetRolePermissions, permissions{readOrg}},
+		{"GET", fmt.Sprintf("%s/{roleId}/members", rolesPrefix), handler.GetMembersWithRole, permissions{readOrg}},
+		{"POST", fmt.Sprintf("%s/{roleId}/members", rolesPrefix), handler.AddMemberToRole, permissions{readOrg, anyOf("org{organizationId}:role{roleId}:add_member", "org{organizationId}:add_roles")}},
+		{"DELETE", fmt.Sprintf("%s/{roleId}/members/{memberId}", rolesPrefix), handler.RemoveMemberFromRole, permissions{readOrg, anyOf("org{organizationId}:role{roleId}:remove_member", "org{organizationId}:add_roles")}},
+	}
+}
+
 func (handler *roleHandler) GetOrganizationRoles(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
 	orgRolePrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgRolePrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	roles, err := auth.GetRoles(&orgRolePrefix)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get roles for organization %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get roles for organization %s: %w", organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -67,27 +68,18 @@ func (handler *roleHandler) GetOrganizationPermissions(writer http.ResponseWrite
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
 	orgRolePrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgRolePrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	roles, err := auth.GetRoles(&orgRolePrefix)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get roles for organization %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get roles for organization %s: %w", organizationId, err))
 		return
 	}
 	permissions := make([]auth.Permission, 0)
 	for _, role := range *roles {
 		rolePermissions, err := auth.GetRolePermissions(role.Id)
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to get permissions for role %s: %s", role.Id, err.Error()), http.StatusInternalServerError)
+			writeError(writer, request, fmt.Errorf("Failed to get permissions for role %s: %w", role.Id, err))
 			return
 		}
 		permissions = append(permissions, *rolePermissions...)
@@ -101,32 +93,30 @@ func (handler *roleHandler) CreateRole(writer http.ResponseWriter, request *http
 	params := mux.Vars(request)
 	organizationId := params["organizationId"]
 
-	roleName := request.FormValue("name")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	roleName := body.Get("name")
 	if roleName == "" {
-		http.Error(writer, "No name Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No name Found"))
 		return
 	}
-	roleDescription := request.FormValue("description")
+	roleDescription := body.Get("description")
 	if roleDescription == "" {
-		http.Error(writer, "No description Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No description Found"))
 		return
 	}
-	permissionNames := request.Form["permission_names"]
+	permissionNames := body["permission_names"]
 	if len(permissionNames) == 0 {
-		http.Error(writer, "No Permission Names Found", http.StatusBadRequest)
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Permission Names Found"))
 		return
 	}
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	userId := token.RegisteredClaims.Subject
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	creatRolesPerm := fmt.Sprintf("%s:create_roles", orgPrefix)
-	canCreateRoles := tokenCustomClaims.HasPermission(creatRolesPerm)
-	if !canCreateRoles {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to create roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 
 	roleName = fmt.Sprintf("%s:%s", orgPrefix, roleName)
 	auth.CreateRole(roleName, roleDescription)
@@ -134,7 +124,7 @@ func (handler *roleHandler) CreateRole(writer http.ResponseWriter, request *http
 	role := (*roles)[0]
 	roleId := role.Id
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get role with query string %s: %s", roleName, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get role with query string %s: %w", roleName, err))
 		return
 	}
 
@@ -149,26 +139,26 @@ func (handler *roleHandler) CreateRole(writer http.ResponseWriter, request *http
 	permissions := []auth.Permission{addMemberToSpecificRolePermission, removeMemberFromSpecificRolePermission}
 	err = auth.CreatePermissions(permissions)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to create permissions for role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to create permissions for role %s: %w", roleId, err))
 		return
 	}
 
 	permissionNames = append(permissionNames, addMemberToSpecificRolePermission.Name, removeMemberFromSpecificRolePermission.Name)
 	for _, permissionName := range permissionNames {
 		if !strings.Contains(permissionName, orgPrefix) {
-			http.Error(writer, fmt.Sprintf("Permission %s does not have org prefix %s (maybe meant for another org?)", permissionName, orgPrefix), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Permission %s does not have org prefix %s (maybe meant for another org?)", permissionName, orgPrefix)))
 			return
 		}
 	}
 	err = auth.AddPermissionsToRole(roleId, permissionNames)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to add permissions to role %s: %s", roleName, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add permissions to role %s: %w", roleName, err))
 		return
 	}
 
 	err = auth.AddUserToRole(userId, roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to add user %s to role %s: %s", userId, roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add user %s to role %s: %w", userId, roleId, err))
 		return
 	}
 
@@ -182,19 +172,9 @@ func (handler *roleHandler) GetRole(writer http.ResponseWriter, request *http.Re
 	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
 	role, err := auth.GetRoleById(roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get role for organization %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get role for organization %s: %w", organizationId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -207,48 +187,43 @@ func (handler *roleHandler) UpdateRole(writer http.ResponseWriter, request *http
 	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	userId := token.RegisteredClaims.Subject
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
 	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	editRolesPerm := fmt.Sprintf("%s:edit_roles", orgPrefix)
-	canEditRoles := tokenCustomClaims.HasPermission(editRolesPerm)
-	if !canEditRoles {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to edit roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
+	role, err := auth.GetRoleById(roleId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get role for organization %s: %w", organizationId, err))
 		return
 	}
-
-	role, err := auth.GetRoleById(roleId)
+	body, err := readBody(request)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get role for organization %s: %s", organizationId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, err)
 		return
 	}
-	roleName := request.FormValue("name")
+	roleName := body.Get("name")
 	if roleName == "" {
 		roleName = role.Name
 	} else {
 		roleName = fmt.Sprintf("%s:%s", orgPrefix, roleName)
 	}
-	roleDescription := request.FormValue("description")
+	roleDescription := body.Get("description")
 	if roleDescription == "" {
 		roleDescription = role.Description
 	}
 
 	err = auth.UpdateRole(role.Id, roleName, roleDescription)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to update role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to update role %s: %w", roleId, err))
 		return
 	}
 	currentRolePermissions, err := auth.GetRolePermissions(roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get role permissions for role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get role permissions for role %s: %w", roleId, err))
 		return
 	}
 
-	permissionNames := request.Form["permission_names"]
+	permissionNames := body["permission_names"]
 	for _, permissionName := range permissionNames {
 		if !strings.Contains(permissionName, orgPrefix) {
-			http.Error(writer, fmt.Sprintf("Permission %s does not have org prefix %s (maybe meant for another org?)", permissionName, orgPrefix), http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Permission %s does not have org prefix %s (maybe meant for another org?)", permissionName, orgPrefix)))
 			return
 		}
 	}
@@ -288,14 +263,14 @@ func (handler *roleHandler) UpdateRole(writer http.ResponseWriter, request *http
 	if len(addPermissionNames) > 0 {
 		err = auth.AddPermissionsToRole(roleId, addPermissionNames)
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to add permissions %v to role %s: %s", addPermissionNames, roleId, err.Error()), http.StatusInternalServerError)
+			writeError(writer, request, fmt.Errorf("Failed to add permissions %v to role %s: %w", addPermissionNames, roleId, err))
 			return
 		}
 	}
 	if len(deletePermissionNames) > 0 {
 		err = auth.RemovePermissionsFromRole(roleId, deletePermissionNames)
 		if err != nil {
-			http.Error(writer, fmt.Sprintf("Failed to remove permissions %v from role %s: %s", deletePermissionNames, roleId, err.Error()), http.StatusInternalServerError)
+			writeError(writer, request, fmt.Errorf("Failed to remove permissions %v from role %s: %w", deletePermissionNames, roleId, err))
 			return
 		}
 	}
@@ -306,21 +281,11 @@ func (handler *roleHandler) UpdateRole(writer http.ResponseWriter, request *http
 
 func (handler *roleHandler) DeleteRole(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	deleteRolesPerm := fmt.Sprintf("org%s:delete_roles", organizationId)
-	canDeleteRoles := tokenCustomClaims.HasPermission(deleteRolesPerm)
-	if !canDeleteRoles {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to edit roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
 	err := auth.DeleteRole(roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to delete role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to delete role %s: %w", roleId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -329,22 +294,11 @@ func (handler *roleHandler) DeleteRole(writer http.ResponseWriter, request *http
 
 func (handler *roleHandler) GetRolePermissions(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
 	permissions, err := auth.GetRolePermissions(roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get permissions for role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get permissions for role %s: %w", roleId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -354,22 +308,11 @@ func (handler *roleHandler) GetRolePermissions(writer http.ResponseWriter, reque
 
 func (handler *roleHandler) GetMembersWithRole(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	readOrgPerm := fmt.Sprintf("org%s:read", organizationId)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to read roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-		return
-	}
-
 	members, err := user.GetUsersWithRole(roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users with role %s: %s", roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get users with role %s: %w", roleId, err))
 		return
 	}
 
@@ -380,40 +323,22 @@ func (handler *roleHandler) GetMembersWithRole(writer http.ResponseWriter, reque
 
 func (handler *roleHandler) AddMemberToRole(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 
-	memberId := request.FormValue("member_id")
-	if memberId == "" {
-		http.Error(writer, "No Member ID Found", http.StatusBadRequest)
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
 		return
 	}
-
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg {
-		http.Error(writer, fmt.Sprintf("User you're trying to give role to (%s) does not have permission to read organization with id: %s", memberId, organizationId), http.StatusForbidden)
+	memberId := body.Get("member_id")
+	if memberId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Member ID Found"))
 		return
 	}
 
-	addMemberToSpecificRole := fmt.Sprintf("org%s:role%s:add_member", organizationId, roleId)
-	canAddMemberToSpecificRole := tokenCustomClaims.HasPermission(addMemberToSpecificRole)
-	if !canAddMemberToSpecificRole {
-		addToRolesPerm := fmt.Sprintf("%s:add_roles", orgPrefix)
-		canAddToRoles := tokenCustomClaims.HasPermission(addToRolesPerm)
-		if !canAddToRoles {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-			return
-		}
-	}
-
-	err := auth.AddUserToRole(memberId, roleId)
+	err = auth.AddUserToRole(memberId, roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to add member %s to role %s: %s", memberId, roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add member %s to role %s: %w", memberId, roleId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -422,35 +347,12 @@ func (handler *roleHandler) AddMemberToRole(writer http.ResponseWriter, request
 
 func (handler *roleHandler) RemoveMemberFromRole(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
-	organizationId := params["organizationId"]
 	roleId := params["roleId"]
 	memberId := params["memberId"]
 
-	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
-	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
-	userId := token.RegisteredClaims.Subject
-	orgPrefix := fmt.Sprintf("org%s", organizationId)
-	readOrgPerm := fmt.Sprintf("%s:read", orgPrefix)
-	canReadOrg := tokenCustomClaims.HasPermission(readOrgPerm)
-	if !canReadOrg && userId != memberId {
-		http.Error(writer, fmt.Sprintf("User you're trying to remove role from (%s) does not have permission to read organization with id: %s", memberId, organizationId), http.StatusForbidden)
-		return
-	}
-
-	removeMemberFromSpecificRole := fmt.Sprintf("org%s:role%s:remove_member", organizationId, roleId)
-	canRemoveMemberFromSpecificRole := tokenCustomClaims.HasPermission(removeMemberFromSpecificRole)
-	if !canRemoveMemberFromSpecificRole {
-		removeFromRolesPerm := fmt.Sprintf("%s:add_roles", orgPrefix)
-		canRemoveFromRoles := tokenCustomClaims.HasPermission(removeFromRolesPerm)
-		if !canRemoveFromRoles {
-			http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add roles to organization with id: %s", userId, organizationId), http.StatusForbidden)
-			return
-		}
-	}
-
 	err := auth.RemoveUserFromRole(memberId, roleId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to remove member %s from role %s: %s", memberId, roleId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to remove member %s from role %s: %w", memberId, roleId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
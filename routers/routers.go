@@ -2,18 +2,26 @@ package routers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 
+	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/credential"
 	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
 )
 
 const (
 	usersPrefix         = "/api/users"
 	organizationsPrefix = "/api/organizations"
+	invitationsPrefix   = "/api/invitations"
+	sharedBoardsPrefix  = "/api/shared-boards"
+	jobsPrefix          = "/api/admin/jobs"
 	boardsPrefix        = "/api/organizations/{organizationId}/boards"
 	panelsPrefix        = "/api/organizations/{organizationId}/boards/{boardId}/panels"
 	stacksPrefix        = "/api/organizations/{organizationId}/boards/{boardId}/panels/{panelId}/stacks"
@@ -22,13 +30,34 @@ const (
 
 func NewAPI(cfg *config.Config, db *db.DB) http.Handler {
 	corsWrapper := cors.New(cors.Options{
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders: []string{"Content-Type", "Origin", "Accept", "*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders: []string{"Content-Type", "Origin", "Accept", "If-Match", sharePasswordHeader, "*"},
+		ExposedHeaders: []string{"ETag", requestIdHeader},
 	})
 
+	return corsWrapper.Handler(withRequestId(newRouter(cfg, db)))
+}
+
+func newRouter(cfg *config.Config, db *db.DB) *mux.Router {
+	auth.ErrorHandler = func(writer http.ResponseWriter, request *http.Request, err error) {
+		writeError(writer, request, controllers.NewError(controllers.CodeUnauthorized, "Failed to validate JWT."))
+	}
+
+	auth.TokenAuthenticator = credential.NewController(cfg, store.NewPostgres(db)).Authenticate
+
 	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writeError(writer, request, controllers.NewError(controllers.CodeNotFound, fmt.Sprintf("No route found for %s %s", request.Method, request.URL.Path)))
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writeError(writer, request, controllers.NewError(controllers.CodeMethodNotAllowed, fmt.Sprintf("Method %s is not allowed for %s", request.Method, request.URL.Path)))
+	})
+	router.HandleFunc(openAPIPath, getOpenAPISpec).Methods("GET")
 	router.PathPrefix(usersPrefix).Handler(registerUserRoutes(router, cfg, db))
 	router.PathPrefix(organizationsPrefix).Handler(registerOrganizationRoutes(router, cfg, db))
+	router.PathPrefix(invitationsPrefix).Handler(registerInvitationResponseRoutes(router, cfg, db))
+	router.PathPrefix(sharedBoardsPrefix).Handler(registerSharedBoardRoutes(router, cfg, db))
+	router.PathPrefix(jobsPrefix).Handler(registerJobRoutes(router, cfg, db))
 
 	// send hello world as json in temp route
 	router.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
@@ -36,5 +65,5 @@ func NewAPI(cfg *config.Config, db *db.DB) http.Handler {
 		json.NewEncoder(writer).Encode(map[string]string{"message": "Hello World!"})
 	})
 
-	return corsWrapper.Handler(router)
+	return router
 }
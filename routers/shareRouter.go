@@ -0,0 +1,147 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+// sharePasswordHeader carries the password of a password protected share, so it doesn't end up in
+// logs the way a query parameter would
+const sharePasswordHeader = "X-Share-Password"
+
+type shareHandler struct {
+	router     *mux.Router
+	controller *board.Controller
+}
+
+func registerShareRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &shareHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: board.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handleSecured(handler.router, handler.routes())
+
+	return handler.router
+}
+
+// routes need the caller to be able to change the board, since a share shows it to anyone
+func (handler *shareHandler) routes() []securedRoute {
+	sharesPrefix := fmt.Sprintf("%s/{boardId}/shares", boardsPrefix)
+	return []securedRoute{
+		{"GET", sharesPrefix, handler.resolved(handler.GetBoardShares), permissions{readOrg, onBoard("update")}},
+		{"POST", sharesPrefix, handler.resolved(handler.CreateBoardShare), permissions{readOrg, onBoard("update")}},
+		{"DELETE", fmt.Sprintf("%s/{shareId}", sharesPrefix), handler.resolved(handler.RevokeBoardShare), permissions{readOrg, onBoard("update")}},
+	}
+}
+
+func (handler *shareHandler) resolved(next http.HandlerFunc) http.HandlerFunc {
+	return withResources(handler.controller, false, next)
+}
+
+// registerSharedBoardRoutes serves shared boards to anyone with the token, without signing in
+func registerSharedBoardRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &shareHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: board.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handler.router.HandleFunc(fmt.Sprintf("%s/{token}", sharedBoardsPrefix), handler.GetSharedBoard).Methods("GET")
+
+	return handler.router
+}
+
+func (handler *shareHandler) GetBoardShares(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+
+	ctx := request.Context()
+	shares, err := handler.controller.GetBoardShares(ctx, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get shares for board %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(shares)
+}
+
+func (handler *shareHandler) CreateBoardShare(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	password := body.Get("password")
+	var expiresAt *time.Time
+	if expiresAtString := body.Get("expires_at"); expiresAtString != "" {
+		parsed, err := time.Parse(time.RFC3339, expiresAtString)
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse expires_at: %s", err.Error())))
+			return
+		}
+		if !parsed.After(time.Now()) {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "expires_at must be in the future"))
+			return
+		}
+		expiresAt = &parsed
+	}
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+
+	ctx := request.Context()
+	created, err := handler.controller.CreateBoardShare(ctx, boardId, userId, password, expiresAt)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to share board %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *shareHandler) RevokeBoardShare(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	boardId := params["boardId"]
+	shareId := params["shareId"]
+
+	ctx := request.Context()
+	err := handler.controller.RevokeBoardShare(ctx, boardId, shareId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to revoke share with id %s: %w", shareId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *shareHandler) GetSharedBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	shareToken := params["token"]
+
+	ctx := request.Context()
+	board, err := handler.controller.GetSharedBoard(ctx, shareToken, request.Header.Get(sharePasswordHeader))
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get shared board: %w", err))
+		return
+	}
+	// shared boards can be revoked at any time, so don't let anything in between keep a copy
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(board)
+}
@@ -0,0 +1,51 @@
+package routers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+func TestShareRoutesOnlyFindBoardsInTheOrganization(t *testing.T) {
+	controller := board.NewController(&config.Config{}, store.NewMemory())
+	organizationId := uuid.New().String()
+	created, err := controller.CreateBoard(context.Background(), "auth0|owner", "Board", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	handler := &shareHandler{router: mux.NewRouter(), controller: controller}
+	for _, route := range handler.routes() {
+		handler.router.Handle(route.path, requirePermissions(route.permissions, route.handler)).Methods(route.method)
+	}
+	// boards_admin covers every board of the admin's own organization, whatever its id
+	serve := func(organizationId string, boardId string) int {
+		request := httptest.NewRequest("POST", fmt.Sprintf("/api/organizations/%s/boards/%s/shares", organizationId, boardId), nil)
+		claims := &validator.ValidatedClaims{
+			RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|admin"},
+			CustomClaims:     &auth.CustomClaims{Permissions: []string{fmt.Sprintf("org%s:read", organizationId), fmt.Sprintf("org%s:boards_admin", organizationId)}},
+		}
+		request = request.WithContext(context.WithValue(request.Context(), jwtmiddleware.ContextKey{}, claims))
+		recorder := httptest.NewRecorder()
+		handler.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := serve(uuid.New().String(), created.Id.String()); code != http.StatusNotFound {
+		t.Fatalf("expected another organization's admin not to find the board, got %d", code)
+	}
+	if code := serve(organizationId, created.Id.String()); code != http.StatusCreated {
+		t.Fatalf("expected the organization's admin to share the board, got %d", code)
+	}
+}
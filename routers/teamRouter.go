@@ -0,0 +1,329 @@
+package routers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/controllers/team"
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gorilla/mux"
+)
+
+type teamHandler struct {
+	router     *mux.Router
+	controller *team.Controller
+	boards     *board.Controller
+}
+
+func registerTeamRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
+	handler := &teamHandler{
+		router:     parentRouter.NewRoute().Subrouter(),
+		controller: team.NewController(cfg, store.NewPostgres(db)),
+		boards:     board.NewController(cfg, store.NewPostgres(db)),
+	}
+
+	handleSecured(handler.router, handler.routes())
+	handleSecured(handler.router, handler.boardRoutes())
+
+	return handler.router
+}
+
+// routes are the team routes under an organization. Teams are managed with the same permissions
+// as roles, and anyone in the organization can leave a team, so RemoveTeamMember checks
+// org<id>:remove_roles for removing anyone else.
+func (handler *teamHandler) routes() []securedRoute {
+	teamsPrefix := fmt.Sprintf("%s/{organizationId}/teams", organizationsPrefix)
+	return []securedRoute{
+		{"GET", teamsPrefix, handler.GetTeams, permissions{readOrg}},
+		{"POST", teamsPrefix, handler.CreateTeam, permissions{anyOf("org{organizationId}:create_roles")}},
+		{"GET", fmt.Sprintf("%s/{teamId}", teamsPrefix), handler.GetTeam, permissions{readOrg}},
+		{"PUT", fmt.Sprintf("%s/{teamId}", teamsPrefix), handler.UpdateTeam, permissions{anyOf("org{organizationId}:edit_roles")}},
+		{"DELETE", fmt.Sprintf("%s/{teamId}", teamsPrefix), handler.DeleteTeam, permissions{anyOf("org{organizationId}:delete_roles")}},
+		{"GET", fmt.Sprintf("%s/{teamId}/members", teamsPrefix), handler.GetTeamMembers, permissions{readOrg}},
+		{"POST", fmt.Sprintf("%s/{teamId}/members", teamsPrefix), handler.AddTeamMember, permissions{anyOf("org{organizationId}:add_roles")}},
+		{"DELETE", fmt.Sprintf("%s/{teamId}/members/{memberId}", teamsPrefix), handler.RemoveTeamMember, permissions{readOrg}},
+		{"GET", fmt.Sprintf("%s/{teamId}/boards", teamsPrefix), handler.GetTeamBoards, permissions{readOrg}},
+	}
+}
+
+// boardRoutes are the team routes under a board, which load the board first so it has to be in
+// the organization. Reading a private board's teams also needs org<id>:board<id>:read, which
+// GetBoardTeams checks on the loaded board.
+func (handler *teamHandler) boardRoutes() []securedRoute {
+	boardTeamsPrefix := fmt.Sprintf("%s/{boardId}/teams", boardsPrefix)
+	return []securedRoute{
+		{"GET", boardTeamsPrefix, handler.resolved(handler.GetBoardTeams), permissions{reachBoard}},
+		{"POST", boardTeamsPrefix, handler.resolved(handler.AddTeamToBoard), permissions{reachBoard, onBoard("add_members")}},
+		{"DELETE", fmt.Sprintf("%s/{teamId}", boardTeamsPrefix), handler.resolved(handler.RemoveTeamFromBoard), permissions{reachBoard, onBoard("remove_members")}},
+	}
+}
+
+func (handler *teamHandler) resolved(next http.HandlerFunc) http.HandlerFunc {
+	return withResources(handler.boards, false, next)
+}
+
+func (handler *teamHandler) GetTeams(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	ctx := request.Context()
+	teams, err := handler.controller.GetTeams(ctx, organizationId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get teams in organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(teams)
+}
+
+func (handler *teamHandler) CreateTeam(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	name := body.Get("name")
+	if name == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Name Found"))
+		return
+	}
+	description := body.Get("description")
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+
+	ctx := request.Context()
+	created, err := handler.controller.CreateTeam(ctx, organizationId, userId, name, description)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to create team in organization %s: %w", organizationId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(created)
+}
+
+func (handler *teamHandler) GetTeam(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	ctx := request.Context()
+	found, err := handler.controller.GetTeam(ctx, organizationId, teamId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get team %s: %w", teamId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(found)
+}
+
+func (handler *teamHandler) UpdateTeam(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	name := body.Get("name")
+	if name == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Name Found"))
+		return
+	}
+	description := body.Get("description")
+
+	ctx := request.Context()
+	updated, err := handler.controller.UpdateTeam(ctx, organizationId, teamId, name, description)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to update team %s: %w", teamId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(updated)
+}
+
+func (handler *teamHandler) DeleteTeam(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	ctx := request.Context()
+	err := handler.controller.DeleteTeam(ctx, organizationId, teamId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to delete team %s: %w", teamId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *teamHandler) GetTeamMembers(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	ctx := request.Context()
+	members, err := handler.controller.GetTeamMembers(ctx, organizationId, teamId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get members of team %s: %w", teamId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(members)
+}
+
+func (handler *teamHandler) AddTeamMember(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	memberId := body.Get("user_id")
+	if memberId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Member ID Found"))
+		return
+	}
+
+	ctx := request.Context()
+	err = handler.controller.AddTeamMember(ctx, organizationId, teamId, memberId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to add user %s to team %s: %w", memberId, teamId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *teamHandler) RemoveTeamMember(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+	memberId := params["memberId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	userId := token.RegisteredClaims.Subject
+	// Anyone can leave a team themselves
+	if userId != memberId && !allowed(request, "org{organizationId}:remove_roles") {
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to remove users from teams in organization with id: %s", userId, organizationId)))
+		return
+	}
+
+	ctx := request.Context()
+	err := handler.controller.RemoveTeamMember(ctx, organizationId, teamId, memberId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to remove user %s from team %s: %w", memberId, teamId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *teamHandler) GetTeamBoards(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	teamId := params["teamId"]
+
+	ctx := request.Context()
+	teamBoards, err := handler.controller.GetTeamBoards(ctx, organizationId, teamId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get boards of team %s: %w", teamId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(teamBoards)
+}
+
+func (handler *teamHandler) GetBoardTeams(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	userId := token.RegisteredClaims.Subject
+
+	if resourcesFrom(request).Board.IsPrivate {
+		readBoardPerm := fmt.Sprintf("org%s:board%s:read", organizationId, boardId)
+		if !tokenCustomClaims.HasPermission(readBoardPerm) {
+			writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to read board with id: %s", userId, boardId)))
+			return
+		}
+	}
+
+	ctx := request.Context()
+	teamBoards, err := handler.controller.GetBoardTeams(ctx, boardId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to get teams on board %s: %w", boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(teamBoards)
+}
+
+func (handler *teamHandler) AddTeamToBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	teamId := body.Get("team_id")
+	if teamId == "" {
+		writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "No Team ID Found"))
+		return
+	}
+	role := body.Get("role")
+
+	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	tokenCustomClaims := token.CustomClaims.(*auth.CustomClaims)
+	userId := token.RegisteredClaims.Subject
+
+	ctx := request.Context()
+	teamBoard, err := handler.controller.AddTeamToBoard(ctx, tokenCustomClaims, organizationId, boardId, teamId, role, userId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to add team %s to board %s: %w", teamId, boardId, err))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(teamBoard)
+}
+
+func (handler *teamHandler) RemoveTeamFromBoard(writer http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	organizationId := params["organizationId"]
+	boardId := params["boardId"]
+	teamId := params["teamId"]
+
+	ctx := request.Context()
+	err := handler.controller.RemoveTeamFromBoard(ctx, organizationId, boardId, teamId)
+	if err != nil {
+		writeError(writer, request, fmt.Errorf("Failed to remove team %s from board %s: %w", teamId, boardId, err))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package routers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/Sync-Space-49/syncspace-server/auth"
+	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers/board"
+	"github.com/Sync-Space-49/syncspace-server/controllers/team"
+	"github.com/Sync-Space-49/syncspace-server/store"
+)
+
+func TestBoardTeamRoutesOnlyFindBoardsInTheOrganization(t *testing.T) {
+	cfg := &config.Config{}
+	s := store.NewMemory()
+	boards := board.NewController(cfg, s)
+	organizationId := uuid.New().String()
+	public, err := boards.CreateBoard(context.Background(), "auth0|owner", "Public", "", false, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	private, err := boards.CreateBoard(context.Background(), "auth0|owner", "Private", "", true, organizationId)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	handler := &teamHandler{router: mux.NewRouter(), controller: team.NewController(cfg, s), boards: boards}
+	for _, route := range handler.boardRoutes() {
+		handler.router.Handle(route.path, requirePermissions(route.permissions, route.handler)).Methods(route.method)
+	}
+	// Every member can read their own organization, whatever its id
+	serve := func(organizationId string, boardId string) int {
+		request := httptest.NewRequest("GET", fmt.Sprintf("/api/organizations/%s/boards/%s/teams", organizationId, boardId), nil)
+		claims := &validator.ValidatedClaims{
+			RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|member"},
+			CustomClaims:     &auth.CustomClaims{Permissions: []string{fmt.Sprintf("org%s:read", organizationId)}},
+		}
+		request = request.WithContext(context.WithValue(request.Context(), jwtmiddleware.ContextKey{}, claims))
+		recorder := httptest.NewRecorder()
+		handler.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := serve(uuid.New().String(), public.Id.String()); code != http.StatusNotFound {
+		t.Fatalf("expected another organization's member not to find the board, got %d", code)
+	}
+	if code := serve(organizationId, public.Id.String()); code != http.StatusOK {
+		t.Fatalf("expected the organization's member to list the board's teams, got %d", code)
+	}
+	if code := serve(organizationId, private.Id.String()); code != http.StatusForbidden {
+		t.Fatalf("expected a private board's teams to need its read permission, got %d", code)
+	}
+}
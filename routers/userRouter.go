@@ -8,6 +8,7 @@ import (
 	_ "image/jpeg"
 	"image/png"
 	"net/http"
+	"strconv"
 
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
@@ -17,8 +18,10 @@ import (
 	"github.com/Sync-Space-49/syncspace-server/auth"
 	"github.com/Sync-Space-49/syncspace-server/aws"
 	"github.com/Sync-Space-49/syncspace-server/config"
+	"github.com/Sync-Space-49/syncspace-server/controllers"
 	"github.com/Sync-Space-49/syncspace-server/controllers/user"
 	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/store"
 )
 
 type userHandler struct {
@@ -29,7 +32,7 @@ type userHandler struct {
 func registerUserRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB) *mux.Router {
 	handler := &userHandler{
 		router:     parentRouter.NewRoute().Subrouter(),
-		controller: user.NewController(cfg, db),
+		controller: user.NewController(cfg, store.NewPostgres(db)),
 	}
 
 	handler.router.Handle(usersPrefix, auth.EnsureValidToken()(http.HandlerFunc(handler.GetAllUsers))).Methods("GET")
@@ -44,6 +47,7 @@ func registerUserRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB)
 	handler.router.Handle(fmt.Sprintf("%s/{userId}/boards/favourite", usersPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.GetUserFavouriteBoards))).Methods("GET")
 	handler.router.Handle(fmt.Sprintf("%s/{userId}/boards/favourite/{boardId}", usersPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.AddUserFavouriteBoard))).Methods("POST")
 	handler.router.Handle(fmt.Sprintf("%s/{userId}/boards/favourite/{boardId}", usersPrefix), auth.EnsureValidToken()(http.HandlerFunc(handler.RemoveUserFavouriteBoard))).Methods("DELETE")
+	handler.router.PathPrefix("{userId}/tokens").Handler(registerPersonalAccessTokenRoutes(handler.router, cfg, db))
 
 	return handler.router
 }
@@ -51,7 +55,7 @@ func registerUserRoutes(parentRouter *mux.Router, cfg *config.Config, db *db.DB)
 func (handler *userHandler) GetAllUsers(writer http.ResponseWriter, request *http.Request) {
 	users, err := handler.controller.GetUsers()
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get users: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get users: %w", err))
 		return
 	}
 
@@ -66,7 +70,7 @@ func (handler *userHandler) GetUser(writer http.ResponseWriter, request *http.Re
 
 	user, err := handler.controller.GetUserById(userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get user: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get user: %w", err))
 		return
 	}
 
@@ -78,14 +82,19 @@ func (handler *userHandler) GetUser(writer http.ResponseWriter, request *http.Re
 func (handler *userHandler) UpdateUser(writer http.ResponseWriter, request *http.Request) {
 	params := mux.Vars(request)
 	userId := params["userId"]
-	email := request.FormValue("email")
-	username := request.FormValue("username")
-	password := request.FormValue("password")
+	body, err := readBody(request)
+	if err != nil {
+		writeError(writer, request, err)
+		return
+	}
+	email := body.Get("email")
+	username := body.Get("username")
+	password := body.Get("password")
 
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenUserId := token.RegisteredClaims.Subject
 	if tokenUserId != userId {
-		http.Error(writer, "Unauthorized to Update This User", http.StatusUnauthorized)
+		writeError(writer, request, controllers.NewError(controllers.CodeUnauthorized, "Unauthorized to Update This User"))
 		return
 	}
 
@@ -100,7 +109,7 @@ func (handler *userHandler) UpdateUser(writer http.ResponseWriter, request *http
 		decodedPfp, _, err := image.Decode(pfpFile)
 		defer pfpFile.Close()
 		if err != nil {
-			http.Error(writer, "Unable to decode image", http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "Unable to decode image"))
 			return
 		}
 
@@ -126,7 +135,7 @@ func (handler *userHandler) UpdateUser(writer http.ResponseWriter, request *http
 		pfpBuffer := new(bytes.Buffer)
 		err = png.Encode(pfpBuffer, croppedPfp)
 		if err != nil {
-			http.Error(writer, "Unable to rescale image", http.StatusBadRequest)
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, "Unable to rescale image"))
 			return
 		}
 
@@ -134,14 +143,14 @@ func (handler *userHandler) UpdateUser(writer http.ResponseWriter, request *http
 		filename := fmt.Sprintf("%s-pfp%s", userId, fileExtension)
 		pfpUrl, err = aws.UploadPfp(bytes.NewReader(pfpBuffer.Bytes()), filename)
 		if err != nil {
-			http.Error(writer, "Unable to upload file", http.StatusInternalServerError)
+			writeError(writer, request, controllers.NewError(controllers.CodeInternal, "Unable to upload file"))
 			return
 		}
 	}
 
 	err = handler.controller.UpdateUserById(userId, email, username, password, pfpUrl)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to Update User: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to Update User: %w", err))
 		return
 	}
 
@@ -156,13 +165,13 @@ func (handler *userHandler) DeleteUser(writer http.ResponseWriter, request *http
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	tokenUserId := token.RegisteredClaims.Subject
 	if tokenUserId != userId {
-		http.Error(writer, "Unauthorized to Delete This User", http.StatusUnauthorized)
+		writeError(writer, request, controllers.NewError(controllers.CodeUnauthorized, "Unauthorized to Delete This User"))
 		return
 	}
 	ctx := request.Context()
 	err := handler.controller.DeleteUserById(ctx, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to Delete User: %s", err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to Delete User: %w", err))
 		return
 	}
 
@@ -177,14 +186,14 @@ func (handler *userHandler) GetUserOrganizations(writer http.ResponseWriter, req
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	organizations, err := handler.controller.GetUserOrganizationsById(ctx, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get organizations for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get organizations for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -199,14 +208,14 @@ func (handler *userHandler) GetUserOwnedOrganizations(writer http.ResponseWriter
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	organizations, err := handler.controller.GetUserOwnedOrganizationsById(ctx, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get organizations for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get organizations for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -221,14 +230,24 @@ func (handler *userHandler) GetUserBoards(writer http.ResponseWriter, request *h
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId)))
 		return
 	}
 
+	includeArchived := false
+	if request.FormValue("include_archived") != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(request.FormValue("include_archived"))
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse include_archived: %s", err.Error())))
+			return
+		}
+	}
+
 	ctx := request.Context()
-	cards, err := handler.controller.GetUserBoardsById(ctx, userId)
+	cards, err := handler.controller.GetUserBoardsById(ctx, userId, includeArchived)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get organizations for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get organizations for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -243,14 +262,24 @@ func (handler *userHandler) GetUserOwnerBoards(writer http.ResponseWriter, reque
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId)))
 		return
 	}
 
+	includeArchived := false
+	if request.FormValue("include_archived") != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(request.FormValue("include_archived"))
+		if err != nil {
+			writeError(writer, request, controllers.NewError(controllers.CodeBadRequest, fmt.Sprintf("Failed to parse include_archived: %s", err.Error())))
+			return
+		}
+	}
+
 	ctx := request.Context()
-	cards, err := handler.controller.GetUserOwnedBoardsById(ctx, userId)
+	cards, err := handler.controller.GetUserOwnedBoardsById(ctx, userId, includeArchived)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get organizations for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get organizations for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -265,14 +294,14 @@ func (handler *userHandler) GetUserAssignedCards(writer http.ResponseWriter, req
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get organizations for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	cards, err := handler.controller.GetUserAssignedCardsById(ctx, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get organizations for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get organizations for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -287,14 +316,14 @@ func (handler *userHandler) GetUserFavouriteBoards(writer http.ResponseWriter, r
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to get favourite boards for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to get favourite boards for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	boards, err := handler.controller.GetFavouriteBoards(ctx, userId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to get favourite boards for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to get favourite boards for user with id %s: %w", userId, err))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
@@ -310,14 +339,14 @@ func (handler *userHandler) AddUserFavouriteBoard(writer http.ResponseWriter, re
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to add favourite board for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to add favourite board for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	err := handler.controller.AddFavouriteBoard(ctx, userId, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to add favourite board for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to add favourite board for user with id %s: %w", userId, err))
 		return
 	}
 
@@ -332,14 +361,14 @@ func (handler *userHandler) RemoveUserFavouriteBoard(writer http.ResponseWriter,
 	token := request.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
 	signedInUserId := token.RegisteredClaims.Subject
 	if signedInUserId != userId {
-		http.Error(writer, fmt.Sprintf("User with id %s does not have permission to remove favourite board for user with id %s", signedInUserId, userId), http.StatusForbidden)
+		writeError(writer, request, controllers.NewError(controllers.CodeForbidden, fmt.Sprintf("User with id %s does not have permission to remove favourite board for user with id %s", signedInUserId, userId)))
 		return
 	}
 
 	ctx := request.Context()
 	err := handler.controller.RemoveFavouriteBoard(ctx, userId, boardId)
 	if err != nil {
-		http.Error(writer, fmt.Sprintf("Failed to remove favourite board for user with id %s: %s", userId, err.Error()), http.StatusInternalServerError)
+		writeError(writer, request, fmt.Errorf("Failed to remove favourite board for user with id %s: %w", userId, err))
 		return
 	}
 
@@ -13,7 +13,10 @@ CREATE TABLE IF NOT EXISTS Organizations (
     owner_id        VARCHAR(64) NOT NULL,
     name            VARCHAR(255) NOT NULL,
     description     TEXT, 
-    ai_enabled      BOOLEAN DEFAULT FALSE
+    ai_enabled      BOOLEAN DEFAULT FALSE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    provisioning_status VARCHAR(16) NOT NULL DEFAULT 'ready', -- provisioning or failed until its Auth0 roles are set up
+    provisioning_claimed_at TIMESTAMPTZ -- when provisioning was last taken over to be resumed or undone
 );
 
 CREATE TABLE IF NOT EXISTS Boards (
@@ -24,30 +27,41 @@ CREATE TABLE IF NOT EXISTS Boards (
     modified_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     is_private      BOOLEAN DEFAULT FALSE,                  -- defaults to public
     organization_id UUID, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
-    owner_id        VARCHAR(64) NOT NULL
+    owner_id        VARCHAR(64) NOT NULL,
+    deleted_at      TIMESTAMPTZ,                            -- NULL unless the board is in the trash
+    archived_at     TIMESTAMPTZ,                            -- archived boards are read-only
+    version         INT NOT NULL DEFAULT 1,                 -- bumped on every write, sent to clients as the ETag
+    provisioning_status VARCHAR(16) NOT NULL DEFAULT 'ready', -- provisioning or failed until its Auth0 roles are set up
+    provisioning_claimed_at TIMESTAMPTZ -- when provisioning was last taken over to be resumed or undone
 );
 
 CREATE TABLE IF NOT EXISTS Panels (
     id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
     title           VARCHAR(255) NOT NULL,
     position        SMALLINT,
-    board_id        UUID, FOREIGN KEY (board_id) REFERENCES Boards(id) ON DELETE CASCADE
+    board_id        UUID, FOREIGN KEY (board_id) REFERENCES Boards(id) ON DELETE CASCADE,
+    deleted_at      TIMESTAMPTZ,
+    version         INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE IF NOT EXISTS Stacks (
     id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
     title           VARCHAR(255) NOT NULL,
     position        SMALLINT,
-    panel_id        UUID, FOREIGN KEY (panel_id) REFERENCES Panels(id) ON DELETE CASCADE
+    panel_id        UUID, FOREIGN KEY (panel_id) REFERENCES Panels(id) ON DELETE CASCADE,
+    deleted_at      TIMESTAMPTZ,
+    version         INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE IF NOT EXISTS Cards (
     id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
     title           VARCHAR(255) NOT NULL,
     description     TEXT,
-    points          VARCHAR(30) NOT NULL DEFAULT 0;
+    points          VARCHAR(30) NOT NULL DEFAULT '0',
     position        SMALLINT,
-    stack_id        UUID, FOREIGN KEY (stack_id) REFERENCES Stacks(id) ON DELETE CASCADE
+    stack_id        UUID, FOREIGN KEY (stack_id) REFERENCES Stacks(id) ON DELETE CASCADE,
+    deleted_at      TIMESTAMPTZ,
+    version         INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE IF NOT EXISTS Assigned_Cards (
@@ -71,4 +85,129 @@ CREATE TABLE IF NOT EXISTS favorite_boards (
     id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
     user_id varchar(64) NOT NULL,
     board_id varchar(64) NOT NULL
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS Personal_Access_Tokens (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id         VARCHAR(64) NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    token_hash      CHAR(64) NOT NULL UNIQUE, -- sha256 of the token, the token itself is never stored
+    permissions     TEXT NOT NULL DEFAULT '', -- space separated
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,
+    last_used_at    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS API_Keys (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    created_by      VARCHAR(64) NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    token_hash      CHAR(64) NOT NULL UNIQUE, -- sha256 of the key, the key itself is never stored
+    permissions     TEXT NOT NULL DEFAULT '', -- space separated
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,
+    last_used_at    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS Invitations (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    email           VARCHAR(255) NOT NULL,
+    role_id         VARCHAR(64), -- Auth0 role given on top of the member role
+    invited_by      VARCHAR(64) NOT NULL,
+    token_hash      CHAR(64) NOT NULL UNIQUE, -- sha256 of the token, the token itself is only emailed
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending, accepted, declined or revoked
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    responded_at    TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS invitations_pending_email ON Invitations (organization_id, LOWER(email)) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS Invite_Links (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    role_id         VARCHAR(64), -- Auth0 role given on top of the member role
+    created_by      VARCHAR(64) NOT NULL,
+    token_hash      CHAR(64) NOT NULL UNIQUE, -- sha256 of the token, the token itself is never stored
+    max_uses        INT, -- unlimited if null
+    uses            INT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS Organization_Domains (
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    domain          VARCHAR(255) NOT NULL, -- lowercase, without the @
+    role_id         VARCHAR(64), -- Auth0 role given on top of the member role
+    verification_record VARCHAR(128) NOT NULL, -- TXT record the domain must have to be verified
+    verified_at     TIMESTAMPTZ, -- users only join by domain once it is verified
+    created_by      VARCHAR(64) NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, domain)
+);
+
+-- Domain_Joins remembers who joined through a domain, so leaving isn't undone on the next sign in
+CREATE TABLE IF NOT EXISTS Domain_Joins (
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    user_id         VARCHAR(64) NOT NULL,
+    joined_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS Board_Shares (
+    id               UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    board_id         UUID NOT NULL, FOREIGN KEY (board_id) REFERENCES Boards(id) ON DELETE CASCADE,
+    created_by       VARCHAR(64) NOT NULL,
+    token_hash       CHAR(64) NOT NULL UNIQUE, -- sha256 of the token, the token itself is never stored
+    password_hash    VARCHAR(60), -- bcrypt, no password is needed if null
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at       TIMESTAMPTZ,
+    revoked_at       TIMESTAMPTZ,
+    access_count     INT NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS Teams (
+    id              UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    organization_id UUID NOT NULL, FOREIGN KEY (organization_id) REFERENCES Organizations(id) ON DELETE CASCADE,
+    name            VARCHAR(255) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    role_id         VARCHAR(64) NOT NULL, -- Auth0 role held by the team's members
+    created_by      VARCHAR(64) NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS teams_name ON Teams (organization_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS Team_Boards (
+    team_id    UUID NOT NULL, FOREIGN KEY (team_id) REFERENCES Teams(id) ON DELETE CASCADE,
+    board_id   UUID NOT NULL, FOREIGN KEY (board_id) REFERENCES Boards(id) ON DELETE CASCADE,
+    role       VARCHAR(16) NOT NULL, -- member or owner, the board role whose permissions the team gets
+    added_by   VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, board_id)
+);
+
+CREATE TABLE IF NOT EXISTS Provisioning_Steps (
+    resource_id  UUID NOT NULL, -- the organization or board being provisioned, no foreign key since it can be either
+    step         VARCHAR(64) NOT NULL,
+    result       TEXT NOT NULL DEFAULT '', -- what the step made, like the id of the role it created, for later steps and undoing it
+    completed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (resource_id, step)
+);
+
+CREATE TABLE IF NOT EXISTS Jobs (
+    id           UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    type         VARCHAR(64) NOT NULL,
+    payload      JSONB NOT NULL DEFAULT '{}',
+    status       VARCHAR(16) NOT NULL DEFAULT 'queued', -- queued, running, succeeded, or dead after failing max_attempts times
+    attempts     INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- not picked up before this, moved later after each failure
+    locked_at    TIMESTAMPTZ, -- when a worker picked the job up
+    last_error   TEXT,
+    progress     JSONB NOT NULL DEFAULT '{}', -- reported by the handler, so clients can follow long jobs
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS jobs_due ON Jobs (run_at) WHERE status IN ('queued', 'running');
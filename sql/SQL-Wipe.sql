@@ -16,3 +16,14 @@ DROP TABLE IF EXISTS Cards CASCADE;
 DROP TABLE IF EXISTS Assigned_Cards CASCADE;
 DROP TABLE IF EXISTS Tags CASCADE;
 DROP TABLE IF EXISTS Card_Tags CASCADE;
+DROP TABLE IF EXISTS Personal_Access_Tokens CASCADE;
+DROP TABLE IF EXISTS API_Keys CASCADE;
+DROP TABLE IF EXISTS Invitations CASCADE;
+DROP TABLE IF EXISTS Invite_Links CASCADE;
+DROP TABLE IF EXISTS Organization_Domains CASCADE;
+DROP TABLE IF EXISTS Domain_Joins CASCADE;
+DROP TABLE IF EXISTS Board_Shares CASCADE;
+DROP TABLE IF EXISTS Teams CASCADE;
+DROP TABLE IF EXISTS Team_Boards CASCADE;
+DROP TABLE IF EXISTS Provisioning_Steps CASCADE;
+DROP TABLE IF EXISTS Jobs CASCADE;
@@ -0,0 +1,729 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// memory holds every table for the in-memory store. Rows are stored by id and copied in and out,
+// so callers can't change them without going through a repository.
+type memory struct {
+	mu                   sync.Mutex
+	organizations        map[string]*models.Organization
+	boards               map[string]*models.Board
+	panels               map[string]*models.Panel
+	stacks               map[string]*models.Stack
+	cards                map[string]*models.Card
+	assignments          []assignment
+	favourites           []favourite
+	shares               map[string]*models.BoardShare
+	invitations          map[string]*models.Invitation
+	inviteLinks          map[string]*models.InviteLink
+	domains              []models.OrganizationDomain
+	domainJoins          []domainJoin
+	teams                map[string]*models.Team
+	teamBoards           []models.TeamBoard
+	personalAccessTokens map[string]*models.PersonalAccessToken
+	apiKeys              map[string]*models.APIKey
+	// jobs are kept in the order they were created
+	jobs []*models.Job
+	// provisioningSteps holds the result of each finished step by resource id and then step name
+	provisioningSteps map[string]map[string]string
+}
+
+type domainJoin struct {
+	organizationId string
+	userId         string
+}
+
+type assignment struct {
+	userId string
+	cardId string
+}
+
+type favourite struct {
+	userId  string
+	boardId string
+}
+
+// NewMemory returns an empty store that keeps everything in memory. It behaves like NewPostgres,
+// including deletes cascading to children, and is safe to use from multiple goroutines.
+func NewMemory() *Store {
+	m := &memory{
+		organizations: make(map[string]*models.Organization),
+		boards:        make(map[string]*models.Board),
+		panels:        make(map[string]*models.Panel),
+		stacks:        make(map[string]*models.Stack),
+		cards:         make(map[string]*models.Card),
+		shares:        make(map[string]*models.BoardShare),
+		invitations:   make(map[string]*models.Invitation),
+		inviteLinks:   make(map[string]*models.InviteLink),
+		teams:         make(map[string]*models.Team),
+
+		personalAccessTokens: make(map[string]*models.PersonalAccessToken),
+		apiKeys:              make(map[string]*models.APIKey),
+		provisioningSteps:    make(map[string]map[string]string),
+	}
+	return &Store{
+		Organizations: &memoryOrganizations{m},
+		Boards:        &memoryBoards{m},
+		Panels:        newMemoryPanels(m),
+		Stacks:        newMemoryStacks(m),
+		Cards:         newMemoryCards(m),
+		Assignments:   &memoryAssignments{m},
+		Favourites:    &memoryFavourites{m},
+		Shares:        &memoryShares{m},
+		Invitations:   &memoryInvitations{m},
+		InviteLinks:   &memoryInviteLinks{m},
+		Domains:       &memoryDomains{m},
+		Teams:         &memoryTeams{m},
+		Credentials:   &memoryCredentials{m},
+		Provisioning:  &memoryProvisioning{m},
+		Jobs:          &memoryJobs{m},
+	}
+}
+
+// timestamp formats t the way timestamps come back from Postgres
+func timestamp(t time.Time) *string {
+	formatted := t.UTC().Format(time.RFC3339Nano)
+	return &formatted
+}
+
+// deletedBefore reports whether deletedAt is set and earlier than cutoff
+func deletedBefore(deletedAt *string, cutoff time.Time) bool {
+	if deletedAt == nil {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, *deletedAt)
+	return err == nil && t.Before(cutoff)
+}
+
+// createdBefore reports whether createdAt is earlier than cutoff
+func createdBefore(createdAt string, cutoff time.Time) bool {
+	return deletedBefore(&createdAt, cutoff)
+}
+
+// expiredBy reports whether expiresAt is set and isn't after now
+func expiredBy(expiresAt *string, now time.Time) bool {
+	if expiresAt == nil {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, *expiresAt)
+	return err != nil || !t.After(now)
+}
+
+// claimable reports whether provisioning was never claimed or was last claimed before staleBefore
+func claimable(claimedAt *string, staleBefore time.Time) bool {
+	return claimedAt == nil || deletedBefore(claimedAt, staleBefore)
+}
+
+// sortByDeletedAt puts the most recently deleted rows first
+func sortByDeletedAt[T any](rows []T, deletedAt func(T) *string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, *deletedAt(rows[i]))
+		b, _ := time.Parse(time.RFC3339Nano, *deletedAt(rows[j]))
+		return a.After(b)
+	})
+}
+
+// sortByCreatedAt puts the oldest rows first
+func sortByCreatedAt[T any](rows []T, createdAt func(T) string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, createdAt(rows[i]))
+		b, _ := time.Parse(time.RFC3339Nano, createdAt(rows[j]))
+		return a.Before(b)
+	})
+}
+
+// deleteOrganization, deleteBoard, deletePanel and deleteStack remove the row along with
+// everything under it, like ON DELETE CASCADE does in Postgres
+func (m *memory) deleteOrganization(id string) {
+	delete(m.organizations, id)
+	for invitationId, invitation := range m.invitations {
+		if invitation.OrganizationId.String() == id {
+			delete(m.invitations, invitationId)
+		}
+	}
+	for linkId, link := range m.inviteLinks {
+		if link.OrganizationId.String() == id {
+			delete(m.inviteLinks, linkId)
+		}
+	}
+	domains := m.domains[:0]
+	for _, domain := range m.domains {
+		if domain.OrganizationId.String() != id {
+			domains = append(domains, domain)
+		}
+	}
+	m.domains = domains
+	domainJoins := m.domainJoins[:0]
+	for _, join := range m.domainJoins {
+		if join.organizationId != id {
+			domainJoins = append(domainJoins, join)
+		}
+	}
+	m.domainJoins = domainJoins
+	for keyId, key := range m.apiKeys {
+		if key.OrganizationId.String() == id {
+			delete(m.apiKeys, keyId)
+		}
+	}
+	for teamId, team := range m.teams {
+		if team.OrganizationId.String() == id {
+			m.deleteTeam(teamId)
+		}
+	}
+	for boardId, board := range m.boards {
+		if board.OrganizationId.String() == id {
+			m.deleteBoard(boardId)
+		}
+	}
+}
+
+func (m *memory) deleteBoard(id string) {
+	delete(m.boards, id)
+	m.teamBoards = removeTeamBoards(m.teamBoards, func(teamBoard models.TeamBoard) bool {
+		return teamBoard.BoardId.String() == id
+	})
+	for shareId, share := range m.shares {
+		if share.BoardId.String() == id {
+			delete(m.shares, shareId)
+		}
+	}
+	for panelId, panel := range m.panels {
+		if panel.BoardId.String() == id {
+			m.deletePanel(panelId)
+		}
+	}
+}
+
+func (m *memory) deleteTeam(id string) {
+	delete(m.teams, id)
+	m.teamBoards = removeTeamBoards(m.teamBoards, func(teamBoard models.TeamBoard) bool {
+		return teamBoard.TeamId.String() == id
+	})
+}
+
+func (m *memory) deletePanel(id string) {
+	delete(m.panels, id)
+	for stackId, stack := range m.stacks {
+		if stack.PanelId.String() == id {
+			m.deleteStack(stackId)
+		}
+	}
+}
+
+func (m *memory) deleteStack(id string) {
+	delete(m.stacks, id)
+	for cardId, card := range m.cards {
+		if card.StackId.String() == id {
+			m.deleteCard(cardId)
+		}
+	}
+}
+
+func (m *memory) deleteCard(id string) {
+	delete(m.cards, id)
+	assignments := m.assignments[:0]
+	for _, assignment := range m.assignments {
+		if assignment.cardId != id {
+			assignments = append(assignments, assignment)
+		}
+	}
+	m.assignments = assignments
+}
+
+type memoryOrganizations struct {
+	*memory
+}
+
+func (m *memoryOrganizations) Get(ctx context.Context, id string) (*models.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organization, ok := m.organizations[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	found := *organization
+	return &found, nil
+}
+
+func (m *memoryOrganizations) ListByIds(ctx context.Context, ids []string) ([]models.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organizations := make([]models.Organization, 0)
+	for _, id := range ids {
+		if organization, ok := m.organizations[id]; ok {
+			organizations = append(organizations, *organization)
+		}
+	}
+	return organizations, nil
+}
+
+func (m *memoryOrganizations) ListByOwner(ctx context.Context, ownerId string) ([]models.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organizations := make([]models.Organization, 0)
+	for _, organization := range m.organizations {
+		if organization.OwnerId == ownerId {
+			organizations = append(organizations, *organization)
+		}
+	}
+	return organizations, nil
+}
+
+func (m *memoryOrganizations) Create(ctx context.Context, organization models.Organization) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organization.CreatedAt = *timestamp(time.Now())
+	organization.ProvisioningStatus = provisioningStatusOrReady(organization.ProvisioningStatus)
+	m.organizations[organization.Id.String()] = &organization
+	return nil
+}
+
+func (m *memoryOrganizations) Update(ctx context.Context, organization models.Organization) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.organizations[organization.Id.String()]; ok {
+		existing.Name = organization.Name
+		existing.Description = organization.Description
+		existing.AiEnabled = organization.AiEnabled
+	}
+	return nil
+}
+
+func (m *memoryOrganizations) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteOrganization(id)
+	return nil
+}
+
+func (m *memoryOrganizations) DeleteByOwner(ctx context.Context, ownerId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, organization := range m.organizations {
+		if organization.OwnerId == ownerId {
+			m.deleteOrganization(id)
+		}
+	}
+	return nil
+}
+
+func (m *memoryOrganizations) SetProvisioningStatus(ctx context.Context, id string, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if organization, ok := m.organizations[id]; ok {
+		organization.ProvisioningStatus = status
+	}
+	return nil
+}
+
+func (m *memoryOrganizations) ClaimProvisioning(ctx context.Context, id string, status string, staleBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organization, ok := m.organizations[id]
+	if !ok || organization.ProvisioningStatus != status || !claimable(organization.ProvisioningClaimedAt, staleBefore) {
+		return false, nil
+	}
+	organization.ProvisioningClaimedAt = timestamp(time.Now())
+	return true, nil
+}
+
+func (m *memoryOrganizations) ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	organizations := make([]models.Organization, 0)
+	for _, organization := range m.organizations {
+		if organization.ProvisioningStatus == status && createdBefore(organization.CreatedAt, cutoff) {
+			organizations = append(organizations, *organization)
+		}
+	}
+	return organizations, nil
+}
+
+type memoryBoards struct {
+	*memory
+}
+
+func (m *memoryBoards) ClaimVersion(ctx context.Context, id string, version int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	board, ok := m.boards[id]
+	if !ok || board.Version != version || board.DeletedAt != nil {
+		return false, nil
+	}
+	board.Version++
+	return true, nil
+}
+
+func (m *memoryBoards) Get(ctx context.Context, id string) (*models.Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	board, ok := m.boards[id]
+	if !ok || board.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	found := *board
+	return &found, nil
+}
+
+func (m *memoryBoards) GetDeleted(ctx context.Context, organizationId string, id string) (*models.Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	board, ok := m.boards[id]
+	if !ok || board.OrganizationId.String() != organizationId || board.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	found := *board
+	return &found, nil
+}
+
+func (m *memoryBoards) list(include func(board *models.Board) bool) []models.Board {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	boards := make([]models.Board, 0)
+	for _, board := range m.boards {
+		if include(board) {
+			boards = append(boards, *board)
+		}
+	}
+	return boards
+}
+
+func (m *memoryBoards) ListByOrganization(ctx context.Context, organizationId string, includeArchived bool) ([]models.Board, error) {
+	return m.list(func(board *models.Board) bool {
+		return board.OrganizationId.String() == organizationId && board.DeletedAt == nil && (includeArchived || board.ArchivedAt == nil)
+	}), nil
+}
+
+func (m *memoryBoards) ListDeletedByOrganization(ctx context.Context, organizationId string) ([]models.Board, error) {
+	boards := m.list(func(board *models.Board) bool {
+		return board.OrganizationId.String() == organizationId && board.DeletedAt != nil
+	})
+	sortByDeletedAt(boards, func(board models.Board) *string { return board.DeletedAt })
+	return boards, nil
+}
+
+func (m *memoryBoards) ListByIds(ctx context.Context, ids []string, includeArchived bool) ([]models.Board, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return m.list(func(board *models.Board) bool {
+		return wanted[board.Id.String()] && board.DeletedAt == nil && (includeArchived || board.ArchivedAt == nil)
+	}), nil
+}
+
+func (m *memoryBoards) ListByOwner(ctx context.Context, ownerId string, includeArchived bool) ([]models.Board, error) {
+	return m.list(func(board *models.Board) bool {
+		return board.OwnerId == ownerId && board.DeletedAt == nil && (includeArchived || board.ArchivedAt == nil)
+	}), nil
+}
+
+func (m *memoryBoards) Create(ctx context.Context, board models.Board) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := timestamp(time.Now())
+	board.CreatedAt = *now
+	board.ModifiedAt = *now
+	board.Version = 1
+	board.ProvisioningStatus = provisioningStatusOrReady(board.ProvisioningStatus)
+	m.boards[board.Id.String()] = &board
+	return nil
+}
+
+func (m *memoryBoards) Update(ctx context.Context, board models.Board, modifiedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.boards[board.Id.String()]; ok {
+		existing.Title = board.Title
+		existing.Description = board.Description
+		existing.IsPrivate = board.IsPrivate
+		existing.OwnerId = board.OwnerId
+		existing.ModifiedAt = *timestamp(modifiedAt)
+	}
+	return nil
+}
+
+func (m *memoryBoards) Touch(ctx context.Context, id string, modifiedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok {
+		board.ModifiedAt = *timestamp(modifiedAt)
+	}
+	return nil
+}
+
+func (m *memoryBoards) IsArchived(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	board, ok := m.boards[id]
+	if !ok {
+		return false, sql.ErrNoRows
+	}
+	return board.ArchivedAt != nil, nil
+}
+
+func (m *memoryBoards) Archive(ctx context.Context, id string, archivedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok && board.ArchivedAt == nil && board.DeletedAt == nil {
+		board.ArchivedAt = timestamp(archivedAt)
+		board.Version++
+	}
+	return nil
+}
+
+func (m *memoryBoards) Unarchive(ctx context.Context, id string, modifiedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok && board.ArchivedAt != nil && board.DeletedAt == nil {
+		board.ArchivedAt = nil
+		board.ModifiedAt = *timestamp(modifiedAt)
+		board.Version++
+	}
+	return nil
+}
+
+func (m *memoryBoards) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok && board.DeletedAt == nil {
+		board.DeletedAt = timestamp(deletedAt)
+	}
+	return nil
+}
+
+func (m *memoryBoards) Restore(ctx context.Context, id string, modifiedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok {
+		board.DeletedAt = nil
+		board.ModifiedAt = *timestamp(modifiedAt)
+		board.Version++
+	}
+	return nil
+}
+
+func (m *memoryBoards) DeleteByOwner(ctx context.Context, ownerId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, board := range m.boards {
+		if board.OwnerId == ownerId {
+			m.deleteBoard(id)
+		}
+	}
+	return nil
+}
+
+func (m *memoryBoards) PurgeDeleted(ctx context.Context, cutoff time.Time) ([]models.Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, card := range m.cards {
+		if deletedBefore(card.DeletedAt, cutoff) {
+			m.deleteCard(id)
+		}
+	}
+	for id, stack := range m.stacks {
+		if deletedBefore(stack.DeletedAt, cutoff) {
+			m.deleteStack(id)
+		}
+	}
+	for id, panel := range m.panels {
+		if deletedBefore(panel.DeletedAt, cutoff) {
+			m.deletePanel(id)
+		}
+	}
+	boards := make([]models.Board, 0)
+	for id, board := range m.boards {
+		if deletedBefore(board.DeletedAt, cutoff) {
+			boards = append(boards, *board)
+			m.deleteBoard(id)
+		}
+	}
+	return boards, nil
+}
+
+func (m *memoryBoards) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteBoard(id)
+	return nil
+}
+
+func (m *memoryBoards) SetProvisioningStatus(ctx context.Context, id string, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if board, ok := m.boards[id]; ok {
+		board.ProvisioningStatus = status
+	}
+	return nil
+}
+
+func (m *memoryBoards) ClaimProvisioning(ctx context.Context, id string, status string, staleBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	board, ok := m.boards[id]
+	if !ok || board.ProvisioningStatus != status || !claimable(board.ProvisioningClaimedAt, staleBefore) {
+		return false, nil
+	}
+	board.ProvisioningClaimedAt = timestamp(time.Now())
+	return true, nil
+}
+
+func (m *memoryBoards) ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Board, error) {
+	return m.list(func(board *models.Board) bool {
+		return board.ProvisioningStatus == status && createdBefore(board.CreatedAt, cutoff)
+	}), nil
+}
+
+type memoryAssignments struct {
+	*memory
+}
+
+func (m *memoryAssignments) Assign(ctx context.Context, cardId string, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assignments = append(m.assignments, assignment{userId: userId, cardId: cardId})
+	return nil
+}
+
+func (m *memoryAssignments) Unassign(ctx context.Context, cardId string, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assignments := m.assignments[:0]
+	for _, assignment := range m.assignments {
+		if assignment.userId != userId || assignment.cardId != cardId {
+			assignments = append(assignments, assignment)
+		}
+	}
+	m.assignments = assignments
+	return nil
+}
+
+func (m *memoryAssignments) ListUserIds(ctx context.Context, cardId string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	userIds := make([]string, 0)
+	for _, assignment := range m.assignments {
+		if assignment.cardId == cardId {
+			userIds = append(userIds, assignment.userId)
+		}
+	}
+	return userIds, nil
+}
+
+func (m *memoryAssignments) ListCardIds(ctx context.Context, userId string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cardIds := make([]string, 0)
+	for _, assignment := range m.assignments {
+		if assignment.userId == userId {
+			cardIds = append(cardIds, assignment.cardId)
+		}
+	}
+	return cardIds, nil
+}
+
+func (m *memoryAssignments) ListDetailedByUser(ctx context.Context, userId string) ([]models.DetailedAssignedCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cards := make([]models.DetailedAssignedCard, 0)
+	for _, assignment := range m.assignments {
+		if assignment.userId != userId {
+			continue
+		}
+		card, ok := m.cards[assignment.cardId]
+		if !ok || card.DeletedAt != nil {
+			continue
+		}
+		stack, ok := m.stacks[card.StackId.String()]
+		if !ok || stack.DeletedAt != nil {
+			continue
+		}
+		panel, ok := m.panels[stack.PanelId.String()]
+		if !ok || panel.DeletedAt != nil {
+			continue
+		}
+		board, ok := m.boards[panel.BoardId.String()]
+		if !ok || board.DeletedAt != nil {
+			continue
+		}
+		if _, ok := m.organizations[board.OrganizationId.String()]; !ok {
+			continue
+		}
+		cards = append(cards, models.DetailedAssignedCard{
+			UserID:      userId,
+			ID:          card.Id,
+			Title:       card.Title,
+			Description: card.Description,
+			Position:    card.Position,
+			StackID:     stack.Id,
+			Points:      card.Points,
+			DeletedAt:   card.DeletedAt,
+			Version:     card.Version,
+			PanelID:     panel.Id,
+			BoardID:     board.Id,
+			OrgID:       board.OrganizationId,
+		})
+	}
+	return cards, nil
+}
+
+func (m *memoryAssignments) DeleteByUser(ctx context.Context, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assignments := m.assignments[:0]
+	for _, assignment := range m.assignments {
+		if assignment.userId != userId {
+			assignments = append(assignments, assignment)
+		}
+	}
+	m.assignments = assignments
+	return nil
+}
+
+type memoryFavourites struct {
+	*memory
+}
+
+func (m *memoryFavourites) ListBoards(ctx context.Context, userId string) ([]models.Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	boards := make([]models.Board, 0)
+	for _, favourite := range m.favourites {
+		if favourite.userId != userId {
+			continue
+		}
+		if board, ok := m.boards[favourite.boardId]; ok && board.DeletedAt == nil {
+			boards = append(boards, *board)
+		}
+	}
+	return boards, nil
+}
+
+func (m *memoryFavourites) Add(ctx context.Context, userId string, boardId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.favourites = append(m.favourites, favourite{userId: userId, boardId: boardId})
+	return nil
+}
+
+func (m *memoryFavourites) Remove(ctx context.Context, userId string, boardId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	favourites := m.favourites[:0]
+	for _, favourite := range m.favourites {
+		if favourite.userId != userId || favourite.boardId != boardId {
+			favourites = append(favourites, favourite)
+		}
+	}
+	m.favourites = favourites
+	return nil
+}
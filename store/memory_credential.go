@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryCredentials struct {
+	*memory
+}
+
+func (m *memoryCredentials) GetPersonalAccessToken(ctx context.Context, userId string, id string) (*models.PersonalAccessToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.personalAccessTokens[id]
+	if !ok || token.UserId != userId {
+		return nil, sql.ErrNoRows
+	}
+	found := *token
+	return &found, nil
+}
+
+func (m *memoryCredentials) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, token := range m.personalAccessTokens {
+		if token.TokenHash == tokenHash && !expiredBy(token.ExpiresAt, now) {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryCredentials) ListPersonalAccessTokens(ctx context.Context, userId string) ([]models.PersonalAccessToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tokens := make([]models.PersonalAccessToken, 0)
+	for _, token := range m.personalAccessTokens {
+		if token.UserId == userId {
+			tokens = append(tokens, *token)
+		}
+	}
+	sortByCreatedAt(tokens, func(token models.PersonalAccessToken) string { return token.CreatedAt })
+	return tokens, nil
+}
+
+func (m *memoryCredentials) CreatePersonalAccessToken(ctx context.Context, token models.PersonalAccessToken, expiresAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token.CreatedAt = *timestamp(time.Now())
+	if expiresAt != nil {
+		token.ExpiresAt = timestamp(*expiresAt)
+	}
+	m.personalAccessTokens[token.Id.String()] = &token
+	return nil
+}
+
+func (m *memoryCredentials) DeletePersonalAccessToken(ctx context.Context, userId string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.personalAccessTokens[id]
+	if !ok || token.UserId != userId {
+		return sql.ErrNoRows
+	}
+	delete(m.personalAccessTokens, id)
+	return nil
+}
+
+func (m *memoryCredentials) MarkPersonalAccessTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if token, ok := m.personalAccessTokens[id]; ok {
+		token.LastUsedAt = markedUsed(token.LastUsedAt, usedAt)
+	}
+	return nil
+}
+
+func (m *memoryCredentials) GetAPIKey(ctx context.Context, organizationId string, id string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.apiKeys[id]
+	if !ok || key.OrganizationId.String() != organizationId {
+		return nil, sql.ErrNoRows
+	}
+	found := *key
+	return &found, nil
+}
+
+func (m *memoryCredentials) GetAPIKeyByHash(ctx context.Context, tokenHash string, now time.Time) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.apiKeys {
+		if key.TokenHash == tokenHash && !expiredBy(key.ExpiresAt, now) {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryCredentials) ListAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]models.APIKey, 0)
+	for _, key := range m.apiKeys {
+		if key.OrganizationId.String() == organizationId {
+			keys = append(keys, *key)
+		}
+	}
+	sortByCreatedAt(keys, func(key models.APIKey) string { return key.CreatedAt })
+	return keys, nil
+}
+
+func (m *memoryCredentials) CreateAPIKey(ctx context.Context, key models.APIKey, expiresAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key.CreatedAt = *timestamp(time.Now())
+	if expiresAt != nil {
+		key.ExpiresAt = timestamp(*expiresAt)
+	}
+	m.apiKeys[key.Id.String()] = &key
+	return nil
+}
+
+func (m *memoryCredentials) DeleteAPIKey(ctx context.Context, organizationId string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.apiKeys[id]
+	if !ok || key.OrganizationId.String() != organizationId {
+		return sql.ErrNoRows
+	}
+	delete(m.apiKeys, id)
+	return nil
+}
+
+func (m *memoryCredentials) MarkAPIKeyUsed(ctx context.Context, id string, usedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if key, ok := m.apiKeys[id]; ok {
+		key.LastUsedAt = markedUsed(key.LastUsedAt, usedAt)
+	}
+	return nil
+}
+
+// markedUsed returns what last_used_at becomes when a credential last used at lastUsedAt is used
+// at usedAt
+func markedUsed(lastUsedAt *string, usedAt time.Time) *string {
+	if lastUsedAt != nil && !deletedBefore(lastUsedAt, usedAt.Add(-time.Minute)) {
+		return lastUsedAt
+	}
+	return timestamp(usedAt)
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryDomains struct {
+	*memory
+}
+
+func (m *memoryDomains) ListByOrganization(ctx context.Context, organizationId string) ([]models.OrganizationDomain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	domains := make([]models.OrganizationDomain, 0)
+	for _, domain := range m.domains {
+		if domain.OrganizationId.String() == organizationId {
+			domains = append(domains, domain)
+		}
+	}
+	sort.SliceStable(domains, func(i, j int) bool {
+		return domains[i].Domain < domains[j].Domain
+	})
+	return domains, nil
+}
+
+func (m *memoryDomains) ListByDomain(ctx context.Context, domain string) ([]models.OrganizationDomain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	domains := make([]models.OrganizationDomain, 0)
+	for _, allowed := range m.domains {
+		if allowed.Domain == domain {
+			domains = append(domains, allowed)
+		}
+	}
+	return domains, nil
+}
+
+func (m *memoryDomains) Add(ctx context.Context, domain models.OrganizationDomain) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, allowed := range m.domains {
+		if allowed.OrganizationId == domain.OrganizationId && allowed.Domain == domain.Domain {
+			m.domains[i].RoleId = domain.RoleId
+			return nil
+		}
+	}
+	domain.VerifiedAt = nil
+	domain.CreatedAt = *timestamp(time.Now())
+	m.domains = append(m.domains, domain)
+	return nil
+}
+
+func (m *memoryDomains) Remove(ctx context.Context, organizationId string, domain string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, allowed := range m.domains {
+		if allowed.OrganizationId.String() == organizationId && allowed.Domain == domain {
+			m.domains = append(m.domains[:i], m.domains[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *memoryDomains) Verify(ctx context.Context, organizationId string, domain string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, allowed := range m.domains {
+		if allowed.OrganizationId.String() == organizationId && allowed.Domain == domain {
+			if allowed.VerifiedAt == nil {
+				m.domains[i].VerifiedAt = timestamp(time.Now())
+			}
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *memoryDomains) HasJoined(ctx context.Context, organizationId string, userId string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, join := range m.domainJoins {
+		if join.organizationId == organizationId && join.userId == userId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryDomains) RecordJoin(ctx context.Context, organizationId string, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, join := range m.domainJoins {
+		if join.organizationId == organizationId && join.userId == userId {
+			return nil
+		}
+	}
+	m.domainJoins = append(m.domainJoins, domainJoin{organizationId, userId})
+	return nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryInvitations struct {
+	*memory
+}
+
+func (m *memoryInvitations) Get(ctx context.Context, organizationId string, id string) (*models.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invitation, ok := m.invitations[id]
+	if !ok || invitation.OrganizationId.String() != organizationId {
+		return nil, sql.ErrNoRows
+	}
+	found := *invitation
+	return &found, nil
+}
+
+func (m *memoryInvitations) GetPendingByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, invitation := range m.invitations {
+		if invitation.TokenHash != tokenHash || invitation.Status != models.InvitationPending {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339Nano, invitation.ExpiresAt)
+		if err != nil || !expiresAt.After(now) {
+			return nil, sql.ErrNoRows
+		}
+		found := *invitation
+		return &found, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryInvitations) ListPending(ctx context.Context, organizationId string) ([]models.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invitations := make([]models.Invitation, 0)
+	for _, invitation := range m.invitations {
+		if invitation.OrganizationId.String() == organizationId && invitation.Status == models.InvitationPending {
+			invitations = append(invitations, *invitation)
+		}
+	}
+	sort.SliceStable(invitations, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, invitations[i].CreatedAt)
+		b, _ := time.Parse(time.RFC3339Nano, invitations[j].CreatedAt)
+		return a.Before(b)
+	})
+	return invitations, nil
+}
+
+func (m *memoryInvitations) HasPending(ctx context.Context, organizationId string, email string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, invitation := range m.invitations {
+		if invitation.OrganizationId.String() == organizationId && strings.EqualFold(invitation.Email, email) && invitation.Status == models.InvitationPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryInvitations) Create(ctx context.Context, invitation models.Invitation, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invitation.Status = models.InvitationPending
+	invitation.CreatedAt = *timestamp(time.Now())
+	invitation.ExpiresAt = *timestamp(expiresAt)
+	m.invitations[invitation.Id.String()] = &invitation
+	return nil
+}
+
+func (m *memoryInvitations) Renew(ctx context.Context, id string, tokenHash string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invitation, ok := m.invitations[id]
+	if !ok || invitation.Status != models.InvitationPending {
+		return false, nil
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = *timestamp(expiresAt)
+	return true, nil
+}
+
+func (m *memoryInvitations) Respond(ctx context.Context, id string, status string, respondedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invitation, ok := m.invitations[id]
+	if !ok || invitation.Status != models.InvitationPending {
+		return false, nil
+	}
+	invitation.Status = status
+	invitation.RespondedAt = timestamp(respondedAt)
+	return true, nil
+}
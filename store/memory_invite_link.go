@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryInviteLinks struct {
+	*memory
+}
+
+func (m *memoryInviteLinks) Get(ctx context.Context, organizationId string, id string) (*models.InviteLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.inviteLinks[id]
+	if !ok || link.OrganizationId.String() != organizationId {
+		return nil, sql.ErrNoRows
+	}
+	found := *link
+	return &found, nil
+}
+
+func (m *memoryInviteLinks) GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.InviteLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, link := range m.inviteLinks {
+		if link.TokenHash != tokenHash {
+			continue
+		}
+		if link.ExpiresAt != nil {
+			expiresAt, err := time.Parse(time.RFC3339Nano, *link.ExpiresAt)
+			if err != nil || !expiresAt.After(now) {
+				return nil, sql.ErrNoRows
+			}
+		}
+		found := *link
+		return &found, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryInviteLinks) ListByOrganization(ctx context.Context, organizationId string) ([]models.InviteLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	links := make([]models.InviteLink, 0)
+	for _, link := range m.inviteLinks {
+		if link.OrganizationId.String() == organizationId {
+			links = append(links, *link)
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, links[i].CreatedAt)
+		b, _ := time.Parse(time.RFC3339Nano, links[j].CreatedAt)
+		return a.Before(b)
+	})
+	return links, nil
+}
+
+func (m *memoryInviteLinks) Create(ctx context.Context, link models.InviteLink, expiresAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link.Uses = 0
+	link.CreatedAt = *timestamp(time.Now())
+	if expiresAt != nil {
+		link.ExpiresAt = timestamp(*expiresAt)
+	}
+	m.inviteLinks[link.Id.String()] = &link
+	return nil
+}
+
+func (m *memoryInviteLinks) Use(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.inviteLinks[id]
+	if !ok || (link.MaxUses != nil && link.Uses >= *link.MaxUses) {
+		return false, nil
+	}
+	link.Uses++
+	return true, nil
+}
+
+func (m *memoryInviteLinks) Delete(ctx context.Context, organizationId string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.inviteLinks[id]
+	if !ok || link.OrganizationId.String() != organizationId {
+		return sql.ErrNoRows
+	}
+	delete(m.inviteLinks, id)
+	return nil
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryJobs struct {
+	*memory
+}
+
+func (m *memoryJobs) find(id string) *models.Job {
+	for _, job := range m.jobs {
+		if job.Id.String() == id {
+			return job
+		}
+	}
+	return nil
+}
+
+func (m *memoryJobs) Get(ctx context.Context, id string) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.find(id)
+	if job == nil {
+		return nil, sql.ErrNoRows
+	}
+	found := *job
+	return &found, nil
+}
+
+func (m *memoryJobs) List(ctx context.Context, status string, limit int) ([]models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]models.Job, 0)
+	for i := len(m.jobs) - 1; i >= 0 && len(jobs) < limit; i-- {
+		if status == "" || m.jobs[i].Status == status {
+			jobs = append(jobs, *m.jobs[i])
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memoryJobs) ListActive(ctx context.Context, jobType string) ([]models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]models.Job, 0)
+	for _, job := range m.jobs {
+		if job.Type == jobType && (job.Status == models.JobQueued || job.Status == models.JobRunning) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memoryJobs) Create(ctx context.Context, job models.Job, runAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.create(job, runAt)
+	return nil
+}
+
+func (m *memoryJobs) CreateOnce(ctx context.Context, job models.Job, runAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.jobs {
+		if existing.Id == job.Id {
+			return false, nil
+		}
+	}
+	m.create(job, runAt)
+	return true, nil
+}
+
+func (m *memoryJobs) create(job models.Job, runAt time.Time) {
+	job.Status = models.JobQueued
+	job.Attempts = 0
+	job.RunAt = *timestamp(runAt)
+	job.Progress = json.RawMessage("{}")
+	job.CreatedAt = *timestamp(time.Now())
+	m.jobs = append(m.jobs, &job)
+}
+
+func (m *memoryJobs) Claim(ctx context.Context, now time.Time, staleBefore time.Time) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var claimed *models.Job
+	var claimedRunAt time.Time
+	for _, job := range m.jobs {
+		runAt, _ := time.Parse(time.RFC3339Nano, job.RunAt)
+		due := job.Status == models.JobQueued && !runAt.After(now)
+		stale := job.Status == models.JobRunning && deletedBefore(job.LockedAt, staleBefore)
+		if (due || stale) && (claimed == nil || runAt.Before(claimedRunAt)) {
+			claimed, claimedRunAt = job, runAt
+		}
+	}
+	if claimed == nil {
+		return nil, sql.ErrNoRows
+	}
+	claimed.Status = models.JobRunning
+	claimed.Attempts++
+	claimed.LockedAt = timestamp(now)
+	found := *claimed
+	return &found, nil
+}
+
+// claimedBy returns the job if attempt is still the one that holds it
+func (m *memoryJobs) claimedBy(attempt models.Job) *models.Job {
+	job := m.find(attempt.Id.String())
+	if job == nil || job.Status != models.JobRunning || job.Attempts != attempt.Attempts || job.LockedAt == nil || attempt.LockedAt == nil || *job.LockedAt != *attempt.LockedAt {
+		return nil
+	}
+	return job
+}
+
+func (m *memoryJobs) Succeed(ctx context.Context, attempt models.Job, finishedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.claimedBy(attempt)
+	if job == nil {
+		return false, nil
+	}
+	job.Status = models.JobSucceeded
+	job.LockedAt = nil
+	job.FinishedAt = timestamp(finishedAt)
+	return true, nil
+}
+
+func (m *memoryJobs) Fail(ctx context.Context, attempt models.Job, lastError string, retryAt *time.Time, failedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.claimedBy(attempt)
+	if job == nil {
+		return false, nil
+	}
+	job.LockedAt = nil
+	job.LastError = &lastError
+	if retryAt == nil {
+		job.Status = models.JobDead
+		job.FinishedAt = timestamp(failedAt)
+		return true, nil
+	}
+	job.Status = models.JobQueued
+	job.RunAt = *timestamp(*retryAt)
+	return true, nil
+}
+
+func (m *memoryJobs) Retry(ctx context.Context, id string, runAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.find(id)
+	if job == nil || job.Status != models.JobDead {
+		return false, nil
+	}
+	job.Status = models.JobQueued
+	job.Attempts = 0
+	job.RunAt = *timestamp(runAt)
+	job.FinishedAt = nil
+	job.Progress = json.RawMessage("{}")
+	return true, nil
+}
+
+func (m *memoryJobs) SetProgress(ctx context.Context, id string, progress json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job := m.find(id); job != nil {
+		job.Progress = progress
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// positionFields points into a panel, stack or card at the fields memoryPositioned works with
+type positionFields struct {
+	parentId  string
+	position  *int
+	version   *int
+	deletedAt **string
+}
+
+// memoryPositioned implements Positioned for panels, stacks and cards on top of one of memory's
+// maps. table picks the map and fields finds the fields on a row.
+type memoryPositioned[T any] struct {
+	*memory
+	table  func(m *memory) map[string]*T
+	fields func(row *T) positionFields
+}
+
+func (m *memoryPositioned[T]) ClaimVersion(ctx context.Context, id string, version int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row, ok := m.table(m.memory)[id]
+	if !ok {
+		return false, nil
+	}
+	fields := m.fields(row)
+	if *fields.version != version || *fields.deletedAt != nil {
+		return false, nil
+	}
+	*fields.version++
+	return true, nil
+}
+
+func (m *memoryPositioned[T]) NextPosition(ctx context.Context, parentId string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nextPosition := 0
+	for _, row := range m.table(m.memory) {
+		fields := m.fields(row)
+		if fields.parentId == parentId && *fields.deletedAt == nil && *fields.position >= nextPosition {
+			nextPosition = *fields.position + 1
+		}
+	}
+	return nextPosition, nil
+}
+
+func (m *memoryPositioned[T]) ShiftPositions(ctx context.Context, parentId string, from int, to int, delta int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, row := range m.table(m.memory) {
+		fields := m.fields(row)
+		if fields.parentId == parentId && *fields.deletedAt == nil && *fields.position >= from && *fields.position <= to {
+			*fields.position += delta
+			*fields.version++
+		}
+	}
+	return nil
+}
+
+func (m *memoryPositioned[T]) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if row, ok := m.table(m.memory)[id]; ok {
+		*m.fields(row).deletedAt = timestamp(deletedAt)
+	}
+	return nil
+}
+
+func (m *memoryPositioned[T]) Restore(ctx context.Context, id string, position int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if row, ok := m.table(m.memory)[id]; ok {
+		fields := m.fields(row)
+		*fields.deletedAt = nil
+		*fields.position = position
+		*fields.version++
+	}
+	return nil
+}
+
+// get returns a copy of the row. It returns sql.ErrNoRows if there is no such row, if the row is
+// in the trash and deleted is false, or if it isn't and deleted is true.
+func (m *memoryPositioned[T]) get(id string, deleted bool) (*T, error) {
+	row, ok := m.table(m.memory)[id]
+	if !ok || (*m.fields(row).deletedAt != nil) != deleted {
+		return nil, sql.ErrNoRows
+	}
+	found := *row
+	return &found, nil
+}
+
+// list returns copies of the rows under parentId that aren't in the trash, ordered by position
+func (m *memoryPositioned[T]) list(parentId string) []T {
+	rows := make([]T, 0)
+	for _, row := range m.table(m.memory) {
+		fields := m.fields(row)
+		if fields.parentId == parentId && *fields.deletedAt == nil {
+			rows = append(rows, *row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return *m.fields(&rows[i]).position < *m.fields(&rows[j]).position
+	})
+	return rows
+}
+
+// listDeleted returns copies of the rows in the trash that include picks, most recently deleted
+// first
+func (m *memoryPositioned[T]) listDeleted(include func(row *T) bool) []T {
+	rows := make([]T, 0)
+	for _, row := range m.table(m.memory) {
+		if *m.fields(row).deletedAt != nil && include(row) {
+			rows = append(rows, *row)
+		}
+	}
+	sortByDeletedAt(rows, func(row T) *string { return *m.fields(&row).deletedAt })
+	return rows
+}
+
+// boardOfPanel and boardOfStack return the id of the board a panel or stack is on, whether or
+// not anything in between is in the trash
+func (m *memory) boardOfPanel(panelId string) string {
+	panel, ok := m.panels[panelId]
+	if !ok {
+		return ""
+	}
+	return panel.BoardId.String()
+}
+
+func (m *memory) boardOfStack(stackId string) string {
+	stack, ok := m.stacks[stackId]
+	if !ok {
+		return ""
+	}
+	return m.boardOfPanel(stack.PanelId.String())
+}
+
+type memoryPanels struct {
+	memoryPositioned[models.Panel]
+}
+
+func newMemoryPanels(m *memory) *memoryPanels {
+	return &memoryPanels{memoryPositioned[models.Panel]{
+		memory: m,
+		table:  func(m *memory) map[string]*models.Panel { return m.panels },
+		fields: func(panel *models.Panel) positionFields {
+			return positionFields{panel.BoardId.String(), &panel.Position, &panel.Version, &panel.DeletedAt}
+		},
+	}}
+}
+
+func (m *memoryPanels) Get(ctx context.Context, id string) (*models.Panel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(id, false)
+}
+
+func (m *memoryPanels) GetDeleted(ctx context.Context, boardId string, id string) (*models.Panel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	panel, err := m.get(id, true)
+	if err != nil || panel.BoardId.String() != boardId {
+		return nil, sql.ErrNoRows
+	}
+	return panel, nil
+}
+
+func (m *memoryPanels) ListByBoard(ctx context.Context, boardId string) ([]models.Panel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list(boardId), nil
+}
+
+func (m *memoryPanels) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Panel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.listDeleted(func(panel *models.Panel) bool {
+		return panel.BoardId.String() == boardId
+	}), nil
+}
+
+func (m *memoryPanels) Create(ctx context.Context, panel models.Panel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	panel.Version = 1
+	m.panels[panel.Id.String()] = &panel
+	return nil
+}
+
+func (m *memoryPanels) Update(ctx context.Context, panel models.Panel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.panels[panel.Id.String()]; ok {
+		existing.Title = panel.Title
+		existing.Position = panel.Position
+	}
+	return nil
+}
+
+type memoryStacks struct {
+	memoryPositioned[models.Stack]
+}
+
+func newMemoryStacks(m *memory) *memoryStacks {
+	return &memoryStacks{memoryPositioned[models.Stack]{
+		memory: m,
+		table:  func(m *memory) map[string]*models.Stack { return m.stacks },
+		fields: func(stack *models.Stack) positionFields {
+			return positionFields{stack.PanelId.String(), &stack.Position, &stack.Version, &stack.DeletedAt}
+		},
+	}}
+}
+
+func (m *memoryStacks) Get(ctx context.Context, id string) (*models.Stack, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(id, false)
+}
+
+func (m *memoryStacks) GetDeleted(ctx context.Context, boardId string, id string) (*models.Stack, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stack, err := m.get(id, true)
+	if err != nil || m.boardOfPanel(stack.PanelId.String()) != boardId {
+		return nil, sql.ErrNoRows
+	}
+	return stack, nil
+}
+
+func (m *memoryStacks) ListByPanel(ctx context.Context, panelId string) ([]models.Stack, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list(panelId), nil
+}
+
+func (m *memoryStacks) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Stack, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.listDeleted(func(stack *models.Stack) bool {
+		return m.boardOfPanel(stack.PanelId.String()) == boardId
+	}), nil
+}
+
+func (m *memoryStacks) IsInBoard(ctx context.Context, id string, boardId string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stack, ok := m.stacks[id]
+	if !ok || stack.DeletedAt != nil {
+		return false, nil
+	}
+	panel, ok := m.panels[stack.PanelId.String()]
+	if !ok || panel.DeletedAt != nil {
+		return false, nil
+	}
+	return panel.BoardId.String() == boardId, nil
+}
+
+func (m *memoryStacks) Create(ctx context.Context, stack models.Stack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stack.Version = 1
+	m.stacks[stack.Id.String()] = &stack
+	return nil
+}
+
+func (m *memoryStacks) Update(ctx context.Context, stack models.Stack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.stacks[stack.Id.String()]; ok {
+		existing.Title = stack.Title
+		existing.Position = stack.Position
+	}
+	return nil
+}
+
+type memoryCards struct {
+	memoryPositioned[models.Card]
+}
+
+func newMemoryCards(m *memory) *memoryCards {
+	return &memoryCards{memoryPositioned[models.Card]{
+		memory: m,
+		table:  func(m *memory) map[string]*models.Card { return m.cards },
+		fields: func(card *models.Card) positionFields {
+			return positionFields{card.StackId.String(), &card.Position, &card.Version, &card.DeletedAt}
+		},
+	}}
+}
+
+func (m *memoryCards) Get(ctx context.Context, id string) (*models.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(id, false)
+}
+
+func (m *memoryCards) GetDeleted(ctx context.Context, boardId string, id string) (*models.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	card, err := m.get(id, true)
+	if err != nil || m.boardOfStack(card.StackId.String()) != boardId {
+		return nil, sql.ErrNoRows
+	}
+	return card, nil
+}
+
+func (m *memoryCards) ListByStack(ctx context.Context, stackId string) ([]models.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list(stackId), nil
+}
+
+func (m *memoryCards) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.listDeleted(func(card *models.Card) bool {
+		return m.boardOfStack(card.StackId.String()) == boardId
+	}), nil
+}
+
+func (m *memoryCards) Create(ctx context.Context, card models.Card) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	card.Version = 1
+	m.cards[card.Id.String()] = &card
+	return nil
+}
+
+func (m *memoryCards) Update(ctx context.Context, card models.Card) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.cards[card.Id.String()]; ok {
+		existing.Title = card.Title
+		existing.Description = card.Description
+		existing.Points = card.Points
+		existing.Position = card.Position
+		existing.StackId = card.StackId
+	}
+	return nil
+}
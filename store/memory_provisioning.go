@@ -0,0 +1,41 @@
+package store
+
+import "context"
+
+type memoryProvisioning struct {
+	*memory
+}
+
+func (m *memoryProvisioning) CompletedSteps(ctx context.Context, resourceId string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	steps := make(map[string]string)
+	for step, result := range m.provisioningSteps[resourceId] {
+		steps[step] = result
+	}
+	return steps, nil
+}
+
+func (m *memoryProvisioning) CompleteStep(ctx context.Context, resourceId string, step string, result string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.provisioningSteps[resourceId] == nil {
+		m.provisioningSteps[resourceId] = make(map[string]string)
+	}
+	m.provisioningSteps[resourceId][step] = result
+	return nil
+}
+
+func (m *memoryProvisioning) UndoStep(ctx context.Context, resourceId string, step string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.provisioningSteps[resourceId], step)
+	return nil
+}
+
+func (m *memoryProvisioning) Clear(ctx context.Context, resourceId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.provisioningSteps, resourceId)
+	return nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryShares struct {
+	*memory
+}
+
+func (m *memoryShares) Get(ctx context.Context, boardId string, id string) (*models.BoardShare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	share, ok := m.shares[id]
+	if !ok || share.BoardId.String() != boardId {
+		return nil, sql.ErrNoRows
+	}
+	found := *share
+	return &found, nil
+}
+
+func (m *memoryShares) GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.BoardShare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, share := range m.shares {
+		if share.TokenHash != tokenHash {
+			continue
+		}
+		if share.RevokedAt != nil {
+			return nil, sql.ErrNoRows
+		}
+		if share.ExpiresAt != nil {
+			expiresAt, err := time.Parse(time.RFC3339Nano, *share.ExpiresAt)
+			if err != nil || !expiresAt.After(now) {
+				return nil, sql.ErrNoRows
+			}
+		}
+		found := *share
+		return &found, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryShares) ListByBoard(ctx context.Context, boardId string) ([]models.BoardShare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shares := make([]models.BoardShare, 0)
+	for _, share := range m.shares {
+		if share.BoardId.String() == boardId && share.RevokedAt == nil {
+			shares = append(shares, *share)
+		}
+	}
+	sort.SliceStable(shares, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, shares[i].CreatedAt)
+		b, _ := time.Parse(time.RFC3339Nano, shares[j].CreatedAt)
+		return a.Before(b)
+	})
+	return shares, nil
+}
+
+func (m *memoryShares) Create(ctx context.Context, share models.BoardShare, expiresAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	share.CreatedAt = *timestamp(time.Now())
+	if expiresAt != nil {
+		share.ExpiresAt = timestamp(*expiresAt)
+	}
+	m.shares[share.Id.String()] = &share
+	return nil
+}
+
+func (m *memoryShares) Revoke(ctx context.Context, boardId string, id string, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	share, ok := m.shares[id]
+	if !ok || share.BoardId.String() != boardId || share.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	share.RevokedAt = timestamp(revokedAt)
+	return nil
+}
+
+func (m *memoryShares) RecordAccess(ctx context.Context, id string, accessedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if share, ok := m.shares[id]; ok {
+		share.AccessCount++
+		share.LastAccessedAt = timestamp(accessedAt)
+	}
+	return nil
+}
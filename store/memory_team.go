@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type memoryTeams struct {
+	*memory
+}
+
+// removeTeamBoards returns teamBoards without the ones matching remove
+func removeTeamBoards(teamBoards []models.TeamBoard, remove func(models.TeamBoard) bool) []models.TeamBoard {
+	kept := teamBoards[:0]
+	for _, teamBoard := range teamBoards {
+		if !remove(teamBoard) {
+			kept = append(kept, teamBoard)
+		}
+	}
+	return kept
+}
+
+func (m *memoryTeams) Get(ctx context.Context, organizationId string, id string) (*models.Team, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	team, ok := m.teams[id]
+	if !ok || team.OrganizationId.String() != organizationId {
+		return nil, sql.ErrNoRows
+	}
+	found := *team
+	return &found, nil
+}
+
+func (m *memoryTeams) ListByOrganization(ctx context.Context, organizationId string) ([]models.Team, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	teams := make([]models.Team, 0)
+	for _, team := range m.teams {
+		if team.OrganizationId.String() == organizationId {
+			teams = append(teams, *team)
+		}
+	}
+	sort.SliceStable(teams, func(i, j int) bool {
+		return strings.ToLower(teams[i].Name) < strings.ToLower(teams[j].Name)
+	})
+	return teams, nil
+}
+
+func (m *memoryTeams) Create(ctx context.Context, team models.Team) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	team.CreatedAt = *timestamp(time.Now())
+	m.teams[team.Id.String()] = &team
+	return nil
+}
+
+func (m *memoryTeams) Update(ctx context.Context, team models.Team) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.teams[team.Id.String()]
+	if !ok || existing.OrganizationId != team.OrganizationId {
+		return sql.ErrNoRows
+	}
+	existing.Name = team.Name
+	existing.Description = team.Description
+	return nil
+}
+
+func (m *memoryTeams) Delete(ctx context.Context, organizationId string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	team, ok := m.teams[id]
+	if !ok || team.OrganizationId.String() != organizationId {
+		return sql.ErrNoRows
+	}
+	m.deleteTeam(id)
+	return nil
+}
+
+func (m *memoryTeams) ListBoards(ctx context.Context, teamId string) ([]models.TeamBoard, error) {
+	return m.listTeamBoards(func(teamBoard models.TeamBoard) bool {
+		return teamBoard.TeamId.String() == teamId
+	}), nil
+}
+
+func (m *memoryTeams) ListByBoard(ctx context.Context, boardId string) ([]models.TeamBoard, error) {
+	return m.listTeamBoards(func(teamBoard models.TeamBoard) bool {
+		return teamBoard.BoardId.String() == boardId
+	}), nil
+}
+
+func (m *memoryTeams) listTeamBoards(match func(models.TeamBoard) bool) []models.TeamBoard {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	teamBoards := make([]models.TeamBoard, 0)
+	for _, teamBoard := range m.teamBoards {
+		if match(teamBoard) {
+			teamBoards = append(teamBoards, teamBoard)
+		}
+	}
+	sort.SliceStable(teamBoards, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339Nano, teamBoards[i].CreatedAt)
+		b, _ := time.Parse(time.RFC3339Nano, teamBoards[j].CreatedAt)
+		return a.Before(b)
+	})
+	return teamBoards
+}
+
+func (m *memoryTeams) ListBoardIds(ctx context.Context, teamIds []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	boardIds := make([]string, 0)
+	found := make(map[string]bool)
+	for _, teamBoard := range m.teamBoards {
+		boardId := teamBoard.BoardId.String()
+		if found[boardId] {
+			continue
+		}
+		for _, teamId := range teamIds {
+			if teamBoard.TeamId.String() == teamId {
+				found[boardId] = true
+				boardIds = append(boardIds, boardId)
+				break
+			}
+		}
+	}
+	return boardIds, nil
+}
+
+func (m *memoryTeams) AddBoard(ctx context.Context, teamBoard models.TeamBoard) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.teamBoards {
+		if existing.TeamId == teamBoard.TeamId && existing.BoardId == teamBoard.BoardId {
+			m.teamBoards[i].Role = teamBoard.Role
+			return nil
+		}
+	}
+	teamBoard.CreatedAt = *timestamp(time.Now())
+	m.teamBoards = append(m.teamBoards, teamBoard)
+	return nil
+}
+
+func (m *memoryTeams) RemoveBoard(ctx context.Context, teamId string, boardId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	before := len(m.teamBoards)
+	m.teamBoards = removeTeamBoards(m.teamBoards, func(teamBoard models.TeamBoard) bool {
+		return teamBoard.TeamId.String() == teamId && teamBoard.BoardId.String() == boardId
+	})
+	if len(m.teamBoards) == before {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// NewPostgres returns a store backed by the tables in sql/SQL-Setup.sql
+func NewPostgres(db *db.DB) *Store {
+	return &Store{
+		Organizations: &postgresOrganizations{db: db},
+		Boards:        &postgresBoards{db: db},
+		Panels:        &postgresPanels{postgresPositioned{db: db, table: "Panels", parentColumn: "board_id"}},
+		Stacks:        &postgresStacks{postgresPositioned{db: db, table: "Stacks", parentColumn: "panel_id"}},
+		Cards:         &postgresCards{postgresPositioned{db: db, table: "Cards", parentColumn: "stack_id"}},
+		Assignments:   &postgresAssignments{db: db},
+		Favourites:    &postgresFavourites{db: db},
+		Shares:        &postgresShares{db: db},
+		Invitations:   &postgresInvitations{db: db},
+		InviteLinks:   &postgresInviteLinks{db: db},
+		Domains:       &postgresDomains{db: db},
+		Teams:         &postgresTeams{db: db},
+		Credentials:   &postgresCredentials{db: db},
+		Provisioning:  &postgresProvisioning{db: db},
+		Jobs:          &postgresJobs{db: db},
+	}
+}
+
+func claimVersion(ctx context.Context, db *db.DB, table string, id string, version int) (bool, error) {
+	result, err := db.DB.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET version=version+1 WHERE id=$1 AND version=$2 AND deleted_at IS NULL;
+	`, table), id, version)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// provisioningStatusOrReady lets rows be created without a provisioning_status, like everything
+// the tests and seed data make, and start out ready
+func provisioningStatusOrReady(status string) string {
+	if status == "" {
+		return models.ProvisioningReady
+	}
+	return status
+}
+
+// postgresPositioned implements Positioned for Panels, Stacks and Cards, which only differ in
+// which column points at their parent
+type postgresPositioned struct {
+	db           *db.DB
+	table        string
+	parentColumn string
+}
+
+func (p *postgresPositioned) ClaimVersion(ctx context.Context, id string, version int) (bool, error) {
+	return claimVersion(ctx, p.db, p.table, id, version)
+}
+
+func (p *postgresPositioned) NextPosition(ctx context.Context, parentId string) (int, error) {
+	var nextPosition int
+	err := p.db.DB.GetContext(ctx, &nextPosition, fmt.Sprintf(`
+		SELECT COALESCE(MAX(position)+1, 0) AS next_position FROM %s where %s=$1 AND deleted_at IS NULL;
+	`, p.table, p.parentColumn), parentId)
+	if err != nil {
+		return 0, err
+	}
+	return nextPosition, nil
+}
+
+func (p *postgresPositioned) ShiftPositions(ctx context.Context, parentId string, from int, to int, delta int) error {
+	_, err := p.db.DB.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET position=position+$1, version=version+1 WHERE %s=$2 AND position>=$3 AND position<=$4 AND deleted_at IS NULL;
+	`, p.table, p.parentColumn), delta, parentId, from, to)
+	return err
+}
+
+func (p *postgresPositioned) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET deleted_at=$1 WHERE id=$2;
+	`, p.table), deletedAt, id)
+	return err
+}
+
+func (p *postgresPositioned) Restore(ctx context.Context, id string, position int) error {
+	_, err := p.db.DB.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET deleted_at=NULL, position=$1, version=version+1 WHERE id=$2;
+	`, p.table), position, id)
+	return err
+}
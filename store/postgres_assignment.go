@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresAssignments struct {
+	db *db.DB
+}
+
+func (p *postgresAssignments) Assign(ctx context.Context, cardId string, userId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO assigned_cards (user_id, card_id) VALUES ($1, $2);
+	`, userId, cardId)
+	return err
+}
+
+func (p *postgresAssignments) Unassign(ctx context.Context, cardId string, userId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM assigned_cards WHERE user_id=$1 AND card_id=$2;
+	`, userId, cardId)
+	return err
+}
+
+func (p *postgresAssignments) ListUserIds(ctx context.Context, cardId string) ([]string, error) {
+	userIds := make([]string, 0)
+	err := p.db.DB.SelectContext(ctx, &userIds, `
+		SELECT user_id FROM assigned_cards WHERE card_id=$1;
+	`, cardId)
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+func (p *postgresAssignments) ListCardIds(ctx context.Context, userId string) ([]string, error) {
+	cardIds := make([]string, 0)
+	err := p.db.DB.SelectContext(ctx, &cardIds, `
+		SELECT card_id FROM assigned_cards WHERE user_id=$1;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	return cardIds, nil
+}
+
+func (p *postgresAssignments) ListDetailedByUser(ctx context.Context, userId string) ([]models.DetailedAssignedCard, error) {
+	cards := make([]models.DetailedAssignedCard, 0)
+	err := p.db.DB.SelectContext(ctx, &cards, `
+	SELECT ac.user_id, c.*, s.id as stack_id, p.id as panel_id, b.id as board_id, o.id as org_id
+	FROM Assigned_Cards ac
+	JOIN Cards c on ac.card_id = c.id
+	JOIN Stacks s ON c.stack_id = s.id
+	JOIN Panels p ON s.panel_id = p.id
+	JOIN Boards b ON p.board_id = b.id
+	JOIN organizations o ON b.organization_id = o.id
+	WHERE ac.user_id = $1
+		AND c.deleted_at IS NULL AND s.deleted_at IS NULL AND p.deleted_at IS NULL AND b.deleted_at IS NULL;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (p *postgresAssignments) DeleteByUser(ctx context.Context, userId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `DELETE FROM Assigned_Cards WHERE user_id=$1`, userId)
+	return err
+}
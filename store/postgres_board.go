@@ -0,0 +1,238 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresBoards struct {
+	db *db.DB
+}
+
+func (p *postgresBoards) ClaimVersion(ctx context.Context, id string, version int) (bool, error) {
+	return claimVersion(ctx, p.db, "Boards", id, version)
+}
+
+func (p *postgresBoards) Get(ctx context.Context, id string) (*models.Board, error) {
+	var board models.Board
+	err := p.db.DB.GetContext(ctx, &board, `
+		SELECT * FROM Boards WHERE id=$1 AND deleted_at IS NULL;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (p *postgresBoards) GetDeleted(ctx context.Context, organizationId string, id string) (*models.Board, error) {
+	var board models.Board
+	err := p.db.DB.GetContext(ctx, &board, `
+		SELECT * FROM Boards WHERE id=$1 AND organization_id=$2 AND deleted_at IS NOT NULL;
+	`, id, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (p *postgresBoards) ListByOrganization(ctx context.Context, organizationId string, includeArchived bool) ([]models.Board, error) {
+	boards := make([]models.Board, 0)
+	err := p.db.DB.SelectContext(ctx, &boards, `
+		SELECT * FROM Boards WHERE organization_id=$1 AND deleted_at IS NULL AND ($2 OR archived_at IS NULL);
+	`, organizationId, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+func (p *postgresBoards) ListDeletedByOrganization(ctx context.Context, organizationId string) ([]models.Board, error) {
+	boards := make([]models.Board, 0)
+	err := p.db.DB.SelectContext(ctx, &boards, `
+		SELECT * FROM Boards WHERE organization_id=$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+func (p *postgresBoards) ListByIds(ctx context.Context, ids []string, includeArchived bool) ([]models.Board, error) {
+	boards := make([]models.Board, 0)
+	if len(ids) == 0 {
+		return boards, nil
+	}
+	query, args, err := sqlx.In(`SELECT * FROM Boards WHERE id IN (?) AND deleted_at IS NULL AND (? OR archived_at IS NULL)`, ids, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	query = p.db.DB.Rebind(query)
+	err = p.db.DB.SelectContext(ctx, &boards, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+func (p *postgresBoards) ListByOwner(ctx context.Context, ownerId string, includeArchived bool) ([]models.Board, error) {
+	boards := make([]models.Board, 0)
+	err := p.db.DB.SelectContext(ctx, &boards, `
+		SELECT * FROM Boards WHERE owner_id=$1 AND deleted_at IS NULL AND ($2 OR archived_at IS NULL);
+	`, ownerId, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+func (p *postgresBoards) Create(ctx context.Context, board models.Board) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Boards (id, title, description, is_private, organization_id, owner_id, provisioning_status) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, board.Id, board.Title, board.Description, board.IsPrivate, board.OrganizationId, board.OwnerId, provisioningStatusOrReady(board.ProvisioningStatus))
+	return err
+}
+
+func (p *postgresBoards) Update(ctx context.Context, board models.Board, modifiedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET title=$1, description=$2, is_private=$3, owner_id=$4, modified_at=$5 WHERE id=$6;
+	`, board.Title, board.Description, board.IsPrivate, board.OwnerId, modifiedAt, board.Id)
+	return err
+}
+
+func (p *postgresBoards) Touch(ctx context.Context, id string, modifiedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET modified_at=$1 WHERE id=$2;
+	`, modifiedAt, id)
+	return err
+}
+
+func (p *postgresBoards) IsArchived(ctx context.Context, id string) (bool, error) {
+	var isArchived bool
+	err := p.db.DB.GetContext(ctx, &isArchived, `
+		SELECT archived_at IS NOT NULL FROM Boards WHERE id=$1;
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return isArchived, nil
+}
+
+func (p *postgresBoards) Archive(ctx context.Context, id string, archivedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET archived_at=$1, version=version+1 WHERE id=$2 AND archived_at IS NULL AND deleted_at IS NULL;
+	`, archivedAt, id)
+	return err
+}
+
+func (p *postgresBoards) Unarchive(ctx context.Context, id string, modifiedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET archived_at=NULL, modified_at=$1, version=version+1 WHERE id=$2 AND archived_at IS NOT NULL AND deleted_at IS NULL;
+	`, modifiedAt, id)
+	return err
+}
+
+func (p *postgresBoards) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL;
+	`, deletedAt, id)
+	return err
+}
+
+func (p *postgresBoards) Restore(ctx context.Context, id string, modifiedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET deleted_at=NULL, modified_at=$1, version=version+1 WHERE id=$2;
+	`, modifiedAt, id)
+	return err
+}
+
+func (p *postgresBoards) DeleteByOwner(ctx context.Context, ownerId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `DELETE FROM Boards WHERE owner_id=$1`, ownerId)
+	return err
+}
+
+func (p *postgresBoards) PurgeDeleted(ctx context.Context, cutoff time.Time) ([]models.Board, error) {
+	// Everything is purged in one transaction, so a board restored part way through is either
+	// purged whole or left alone with all its cards and assignments
+	tx, err := p.db.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	// Assigned_Cards doesn't cascade, so clear assignments for every card that is about to go,
+	// including the ones that only disappear because a parent is being purged
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM Assigned_Cards WHERE card_id IN (
+			SELECT c.id
+				FROM Cards c
+					JOIN Stacks s ON c.stack_id = s.id
+					JOIN Panels p ON s.panel_id = p.id
+					JOIN Boards b ON p.board_id = b.id
+				WHERE c.deleted_at < $1 OR s.deleted_at < $1 OR p.deleted_at < $1 OR b.deleted_at < $1
+		);
+	`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM Cards WHERE deleted_at < $1;`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM Stacks WHERE deleted_at < $1;`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM Panels WHERE deleted_at < $1;`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]models.Board, 0)
+	err = tx.SelectContext(ctx, &boards, `DELETE FROM Boards WHERE deleted_at < $1 RETURNING *;`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return boards, tx.Commit()
+}
+
+func (p *postgresBoards) Delete(ctx context.Context, id string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Boards WHERE id=$1;
+	`, id)
+	return err
+}
+
+func (p *postgresBoards) SetProvisioningStatus(ctx context.Context, id string, status string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET provisioning_status=$1 WHERE id=$2;
+	`, status, id)
+	return err
+}
+
+func (p *postgresBoards) ClaimProvisioning(ctx context.Context, id string, status string, staleBefore time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Boards SET provisioning_claimed_at=NOW()
+			WHERE id=$1 AND provisioning_status=$2 AND (provisioning_claimed_at IS NULL OR provisioning_claimed_at < $3);
+	`, id, status, staleBefore)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresBoards) ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Board, error) {
+	boards := make([]models.Board, 0)
+	err := p.db.DB.SelectContext(ctx, &boards, `
+		SELECT * FROM Boards WHERE provisioning_status=$1 AND created_at < $2;
+	`, status, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresCards struct {
+	postgresPositioned
+}
+
+func (p *postgresCards) Get(ctx context.Context, id string) (*models.Card, error) {
+	var card models.Card
+	err := p.db.DB.GetContext(ctx, &card, `
+		SELECT * FROM Cards WHERE id=$1 AND deleted_at IS NULL;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (p *postgresCards) GetDeleted(ctx context.Context, boardId string, id string) (*models.Card, error) {
+	var card models.Card
+	err := p.db.DB.GetContext(ctx, &card, `
+		SELECT c.*
+			FROM Cards c
+				JOIN Stacks s ON c.stack_id = s.id
+				JOIN Panels p ON s.panel_id = p.id
+			WHERE c.id = $1 AND p.board_id = $2 AND c.deleted_at IS NOT NULL;
+	`, id, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (p *postgresCards) ListByStack(ctx context.Context, stackId string) ([]models.Card, error) {
+	cards := make([]models.Card, 0)
+	err := p.db.DB.SelectContext(ctx, &cards, `
+		SELECT * FROM Cards WHERE stack_id=$1 AND deleted_at IS NULL ORDER BY position ASC;
+	`, stackId)
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (p *postgresCards) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Card, error) {
+	cards := make([]models.Card, 0)
+	err := p.db.DB.SelectContext(ctx, &cards, `
+		SELECT c.*
+			FROM Cards c
+				JOIN Stacks s ON c.stack_id = s.id
+				JOIN Panels p ON s.panel_id = p.id
+			WHERE p.board_id = $1 AND c.deleted_at IS NOT NULL
+			ORDER BY c.deleted_at DESC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (p *postgresCards) Create(ctx context.Context, card models.Card) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Cards (id, title, description, points, position, stack_id) VALUES ($1, $2, $3, $4, $5, $6);
+	`, card.Id, card.Title, card.Description, card.Points, card.Position, card.StackId)
+	return err
+}
+
+func (p *postgresCards) Update(ctx context.Context, card models.Card) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Cards SET title=$1, description=$2, points=$3, position=$4, stack_id=$5 WHERE id=$6;
+	`, card.Title, card.Description, card.Points, card.Position, card.StackId, card.Id)
+	return err
+}
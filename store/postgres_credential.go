@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresCredentials struct {
+	db *db.DB
+}
+
+func (p *postgresCredentials) GetPersonalAccessToken(ctx context.Context, userId string, id string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := p.db.DB.GetContext(ctx, &token, `
+		SELECT * FROM Personal_Access_Tokens WHERE id=$1 AND user_id=$2;
+	`, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (p *postgresCredentials) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := p.db.DB.GetContext(ctx, &token, `
+		SELECT * FROM Personal_Access_Tokens WHERE token_hash=$1 AND (expires_at IS NULL OR expires_at > $2);
+	`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (p *postgresCredentials) ListPersonalAccessTokens(ctx context.Context, userId string) ([]models.PersonalAccessToken, error) {
+	tokens := make([]models.PersonalAccessToken, 0)
+	err := p.db.DB.SelectContext(ctx, &tokens, `
+		SELECT * FROM Personal_Access_Tokens WHERE user_id=$1 ORDER BY created_at ASC;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (p *postgresCredentials) CreatePersonalAccessToken(ctx context.Context, token models.PersonalAccessToken, expiresAt *time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Personal_Access_Tokens (id, user_id, name, token_hash, permissions, expires_at) VALUES ($1, $2, $3, $4, $5, $6);
+	`, token.Id, token.UserId, token.Name, token.TokenHash, token.Permissions, expiresAt)
+	return err
+}
+
+func (p *postgresCredentials) DeletePersonalAccessToken(ctx context.Context, userId string, id string) error {
+	return p.delete(ctx, `
+		DELETE FROM Personal_Access_Tokens WHERE id=$1 AND user_id=$2;
+	`, id, userId)
+}
+
+func (p *postgresCredentials) MarkPersonalAccessTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return p.markUsed(ctx, "Personal_Access_Tokens", id, usedAt)
+}
+
+func (p *postgresCredentials) GetAPIKey(ctx context.Context, organizationId string, id string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := p.db.DB.GetContext(ctx, &key, `
+		SELECT * FROM API_Keys WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (p *postgresCredentials) GetAPIKeyByHash(ctx context.Context, tokenHash string, now time.Time) (*models.APIKey, error) {
+	var key models.APIKey
+	err := p.db.DB.GetContext(ctx, &key, `
+		SELECT * FROM API_Keys WHERE token_hash=$1 AND (expires_at IS NULL OR expires_at > $2);
+	`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (p *postgresCredentials) ListAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	err := p.db.DB.SelectContext(ctx, &keys, `
+		SELECT * FROM API_Keys WHERE organization_id=$1 ORDER BY created_at ASC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (p *postgresCredentials) CreateAPIKey(ctx context.Context, key models.APIKey, expiresAt *time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO API_Keys (id, organization_id, created_by, name, token_hash, permissions, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, key.Id, key.OrganizationId, key.CreatedBy, key.Name, key.TokenHash, key.Permissions, expiresAt)
+	return err
+}
+
+func (p *postgresCredentials) DeleteAPIKey(ctx context.Context, organizationId string, id string) error {
+	return p.delete(ctx, `
+		DELETE FROM API_Keys WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+}
+
+func (p *postgresCredentials) MarkAPIKeyUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return p.markUsed(ctx, "API_Keys", id, usedAt)
+}
+
+// delete runs query and returns sql.ErrNoRows if it didn't delete anything
+func (p *postgresCredentials) delete(ctx context.Context, query string, args ...any) error {
+	result, err := p.db.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresCredentials) markUsed(ctx context.Context, table string, id string, usedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET last_used_at=$1 WHERE id=$2 AND (last_used_at IS NULL OR last_used_at < $3);
+	`, table), usedAt, id, usedAt.Add(-time.Minute))
+	return err
+}
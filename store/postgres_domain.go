@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresDomains struct {
+	db *db.DB
+}
+
+func (p *postgresDomains) ListByOrganization(ctx context.Context, organizationId string) ([]models.OrganizationDomain, error) {
+	domains := make([]models.OrganizationDomain, 0)
+	err := p.db.DB.SelectContext(ctx, &domains, `
+		SELECT * FROM Organization_Domains WHERE organization_id=$1 ORDER BY domain ASC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+func (p *postgresDomains) ListByDomain(ctx context.Context, domain string) ([]models.OrganizationDomain, error) {
+	domains := make([]models.OrganizationDomain, 0)
+	err := p.db.DB.SelectContext(ctx, &domains, `
+		SELECT * FROM Organization_Domains WHERE domain=$1 ORDER BY created_at ASC;
+	`, domain)
+	if err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+func (p *postgresDomains) Add(ctx context.Context, domain models.OrganizationDomain) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Organization_Domains (organization_id, domain, role_id, verification_record, created_by) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (organization_id, domain) DO UPDATE SET role_id=EXCLUDED.role_id;
+	`, domain.OrganizationId, domain.Domain, domain.RoleId, domain.VerificationRecord, domain.CreatedBy)
+	return err
+}
+
+func (p *postgresDomains) Verify(ctx context.Context, organizationId string, domain string) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Organization_Domains SET verified_at=COALESCE(verified_at, NOW()) WHERE organization_id=$1 AND domain=$2;
+	`, organizationId, domain)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresDomains) Remove(ctx context.Context, organizationId string, domain string) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Organization_Domains WHERE organization_id=$1 AND domain=$2;
+	`, organizationId, domain)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresDomains) HasJoined(ctx context.Context, organizationId string, userId string) (bool, error) {
+	var hasJoined bool
+	err := p.db.DB.GetContext(ctx, &hasJoined, `
+		SELECT EXISTS(SELECT 1 FROM Domain_Joins WHERE organization_id=$1 AND user_id=$2);
+	`, organizationId, userId)
+	if err != nil {
+		return false, err
+	}
+	return hasJoined, nil
+}
+
+func (p *postgresDomains) RecordJoin(ctx context.Context, organizationId string, userId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Domain_Joins (organization_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+	`, organizationId, userId)
+	return err
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresFavourites struct {
+	db *db.DB
+}
+
+func (p *postgresFavourites) ListBoards(ctx context.Context, userId string) ([]models.Board, error) {
+	favouriteBoards := make([]models.Board, 0)
+	//  This Join will return board objects that are favorited by the user with the given userId
+	err := p.db.DB.SelectContext(ctx, &favouriteBoards, `
+		SELECT b.*
+		FROM favorite_boards AS fb
+		JOIN boards AS b ON fb.board_id = b.id
+		WHERE fb.user_id = $1 AND b.deleted_at IS NULL;
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	return favouriteBoards, nil
+}
+
+func (p *postgresFavourites) Add(ctx context.Context, userId string, boardId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO favorite_boards (user_id, board_id) VALUES ($1, $2);
+	`, userId, boardId)
+	return err
+}
+
+func (p *postgresFavourites) Remove(ctx context.Context, userId string, boardId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM favorite_boards WHERE user_id=$1 AND board_id=$2;
+	`, userId, boardId)
+	return err
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresInvitations struct {
+	db *db.DB
+}
+
+func (p *postgresInvitations) Get(ctx context.Context, organizationId string, id string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := p.db.DB.GetContext(ctx, &invitation, `
+		SELECT * FROM Invitations WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (p *postgresInvitations) GetPendingByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := p.db.DB.GetContext(ctx, &invitation, `
+		SELECT * FROM Invitations WHERE token_hash=$1 AND status='pending' AND expires_at > $2;
+	`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (p *postgresInvitations) ListPending(ctx context.Context, organizationId string) ([]models.Invitation, error) {
+	invitations := make([]models.Invitation, 0)
+	err := p.db.DB.SelectContext(ctx, &invitations, `
+		SELECT * FROM Invitations WHERE organization_id=$1 AND status='pending' ORDER BY created_at ASC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (p *postgresInvitations) HasPending(ctx context.Context, organizationId string, email string) (bool, error) {
+	var hasPending bool
+	err := p.db.DB.GetContext(ctx, &hasPending, `
+		SELECT EXISTS(SELECT 1 FROM Invitations WHERE organization_id=$1 AND LOWER(email)=LOWER($2) AND status='pending');
+	`, organizationId, email)
+	if err != nil {
+		return false, err
+	}
+	return hasPending, nil
+}
+
+func (p *postgresInvitations) Create(ctx context.Context, invitation models.Invitation, expiresAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Invitations (id, organization_id, email, role_id, invited_by, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, invitation.Id, invitation.OrganizationId, invitation.Email, invitation.RoleId, invitation.InvitedBy, invitation.TokenHash, expiresAt)
+	return err
+}
+
+func (p *postgresInvitations) Renew(ctx context.Context, id string, tokenHash string, expiresAt time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Invitations SET token_hash=$1, expires_at=$2 WHERE id=$3 AND status='pending';
+	`, tokenHash, expiresAt, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresInvitations) Respond(ctx context.Context, id string, status string, respondedAt time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Invitations SET status=$1, responded_at=$2 WHERE id=$3 AND status='pending';
+	`, status, respondedAt, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
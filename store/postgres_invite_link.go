@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresInviteLinks struct {
+	db *db.DB
+}
+
+func (p *postgresInviteLinks) Get(ctx context.Context, organizationId string, id string) (*models.InviteLink, error) {
+	var link models.InviteLink
+	err := p.db.DB.GetContext(ctx, &link, `
+		SELECT * FROM Invite_Links WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (p *postgresInviteLinks) GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.InviteLink, error) {
+	var link models.InviteLink
+	err := p.db.DB.GetContext(ctx, &link, `
+		SELECT * FROM Invite_Links WHERE token_hash=$1 AND (expires_at IS NULL OR expires_at > $2);
+	`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (p *postgresInviteLinks) ListByOrganization(ctx context.Context, organizationId string) ([]models.InviteLink, error) {
+	links := make([]models.InviteLink, 0)
+	err := p.db.DB.SelectContext(ctx, &links, `
+		SELECT * FROM Invite_Links WHERE organization_id=$1 ORDER BY created_at ASC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (p *postgresInviteLinks) Create(ctx context.Context, link models.InviteLink, expiresAt *time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Invite_Links (id, organization_id, role_id, created_by, token_hash, max_uses, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, link.Id, link.OrganizationId, link.RoleId, link.CreatedBy, link.TokenHash, link.MaxUses, expiresAt)
+	return err
+}
+
+func (p *postgresInviteLinks) Use(ctx context.Context, id string) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Invite_Links SET uses=uses+1 WHERE id=$1 AND (max_uses IS NULL OR uses < max_uses);
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresInviteLinks) Delete(ctx context.Context, organizationId string, id string) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Invite_Links WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresJobs struct {
+	db *db.DB
+}
+
+func (p *postgresJobs) Get(ctx context.Context, id string) (*models.Job, error) {
+	var job models.Job
+	err := p.db.DB.GetContext(ctx, &job, `
+		SELECT * FROM Jobs WHERE id=$1;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (p *postgresJobs) List(ctx context.Context, status string, limit int) ([]models.Job, error) {
+	jobs := make([]models.Job, 0)
+	err := p.db.DB.SelectContext(ctx, &jobs, `
+		SELECT * FROM Jobs WHERE ($1='' OR status=$1) ORDER BY created_at DESC LIMIT $2;
+	`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (p *postgresJobs) ListActive(ctx context.Context, jobType string) ([]models.Job, error) {
+	jobs := make([]models.Job, 0)
+	err := p.db.DB.SelectContext(ctx, &jobs, `
+		SELECT * FROM Jobs WHERE type=$1 AND status IN ('queued', 'running');
+	`, jobType)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (p *postgresJobs) Create(ctx context.Context, job models.Job, runAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Jobs (id, type, payload, max_attempts, run_at) VALUES ($1, $2, $3, $4, $5);
+	`, job.Id, job.Type, string(job.Payload), job.MaxAttempts, runAt)
+	return err
+}
+
+func (p *postgresJobs) CreateOnce(ctx context.Context, job models.Job, runAt time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Jobs (id, type, payload, max_attempts, run_at) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO NOTHING;
+	`, job.Id, job.Type, string(job.Payload), job.MaxAttempts, runAt)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresJobs) Claim(ctx context.Context, now time.Time, staleBefore time.Time) (*models.Job, error) {
+	// SKIP LOCKED lets every worker claim a different job at the same time instead of waiting on
+	// each other's row locks
+	var job models.Job
+	err := p.db.DB.GetContext(ctx, &job, `
+		UPDATE Jobs SET status='running', attempts=attempts+1, locked_at=$1
+			WHERE id = (
+				SELECT id FROM Jobs
+					WHERE (status='queued' AND run_at <= $1) OR (status='running' AND locked_at < $2)
+					ORDER BY run_at
+					LIMIT 1
+					FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *;
+	`, now, staleBefore)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (p *postgresJobs) Succeed(ctx context.Context, job models.Job, finishedAt time.Time) (bool, error) {
+	// The attempt and lock only match while this attempt still holds the job
+	return p.finish(ctx, `
+		UPDATE Jobs SET status='succeeded', locked_at=NULL, finished_at=$1 WHERE id=$2 AND attempts=$3 AND locked_at=$4;
+	`, finishedAt, job.Id, job.Attempts, job.LockedAt)
+}
+
+func (p *postgresJobs) Fail(ctx context.Context, job models.Job, lastError string, retryAt *time.Time, failedAt time.Time) (bool, error) {
+	if retryAt == nil {
+		return p.finish(ctx, `
+			UPDATE Jobs SET status='dead', locked_at=NULL, last_error=$1, finished_at=$2 WHERE id=$3 AND attempts=$4 AND locked_at=$5;
+		`, lastError, failedAt, job.Id, job.Attempts, job.LockedAt)
+	}
+	return p.finish(ctx, `
+		UPDATE Jobs SET status='queued', locked_at=NULL, last_error=$1, run_at=$2 WHERE id=$3 AND attempts=$4 AND locked_at=$5;
+	`, lastError, *retryAt, job.Id, job.Attempts, job.LockedAt)
+}
+
+// finish runs a query recording how an attempt went and reports whether it still held the job
+func (p *postgresJobs) finish(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresJobs) Retry(ctx context.Context, id string, runAt time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Jobs SET status='queued', attempts=0, run_at=$1, finished_at=NULL, progress='{}' WHERE id=$2 AND status='dead';
+	`, runAt, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresJobs) SetProgress(ctx context.Context, id string, progress json.RawMessage) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Jobs SET progress=$1 WHERE id=$2;
+	`, string(progress), id)
+	return err
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresOrganizations struct {
+	db *db.DB
+}
+
+func (p *postgresOrganizations) Get(ctx context.Context, id string) (*models.Organization, error) {
+	var organization models.Organization
+	err := p.db.DB.GetContext(ctx, &organization, `
+		SELECT * FROM Organizations WHERE id=$1;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (p *postgresOrganizations) ListByIds(ctx context.Context, ids []string) ([]models.Organization, error) {
+	organizations := make([]models.Organization, 0)
+	if len(ids) == 0 {
+		return organizations, nil
+	}
+	query, args, err := sqlx.In(`SELECT * FROM Organizations WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	query = p.db.DB.Rebind(query)
+	err = p.db.DB.SelectContext(ctx, &organizations, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (p *postgresOrganizations) ListByOwner(ctx context.Context, ownerId string) ([]models.Organization, error) {
+	organizations := make([]models.Organization, 0)
+	err := p.db.DB.SelectContext(ctx, &organizations, `
+		SELECT * FROM Organizations WHERE owner_id=$1;
+	`, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (p *postgresOrganizations) Create(ctx context.Context, organization models.Organization) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Organizations (id, owner_id, name, description, ai_enabled, provisioning_status) VALUES ($1, $2, $3, $4, $5, $6);
+	`, organization.Id, organization.OwnerId, organization.Name, organization.Description, organization.AiEnabled, provisioningStatusOrReady(organization.ProvisioningStatus))
+	return err
+}
+
+func (p *postgresOrganizations) Update(ctx context.Context, organization models.Organization) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Organizations SET name=$1, description=$2, ai_enabled=$3 WHERE id=$4;
+	`, organization.Name, organization.Description, organization.AiEnabled, organization.Id)
+	return err
+}
+
+func (p *postgresOrganizations) Delete(ctx context.Context, id string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Organizations WHERE id=$1;
+	`, id)
+	return err
+}
+
+func (p *postgresOrganizations) DeleteByOwner(ctx context.Context, ownerId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `DELETE FROM Organizations WHERE owner_id=$1`, ownerId)
+	return err
+}
+
+func (p *postgresOrganizations) SetProvisioningStatus(ctx context.Context, id string, status string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Organizations SET provisioning_status=$1 WHERE id=$2;
+	`, status, id)
+	return err
+}
+
+func (p *postgresOrganizations) ClaimProvisioning(ctx context.Context, id string, status string, staleBefore time.Time) (bool, error) {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Organizations SET provisioning_claimed_at=NOW()
+			WHERE id=$1 AND provisioning_status=$2 AND (provisioning_claimed_at IS NULL OR provisioning_claimed_at < $3);
+	`, id, status, staleBefore)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *postgresOrganizations) ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Organization, error) {
+	organizations := make([]models.Organization, 0)
+	err := p.db.DB.SelectContext(ctx, &organizations, `
+		SELECT * FROM Organizations WHERE provisioning_status=$1 AND created_at < $2;
+	`, status, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
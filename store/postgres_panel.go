@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresPanels struct {
+	postgresPositioned
+}
+
+func (p *postgresPanels) Get(ctx context.Context, id string) (*models.Panel, error) {
+	var panel models.Panel
+	err := p.db.DB.GetContext(ctx, &panel, `
+		SELECT * FROM Panels WHERE id=$1 AND deleted_at IS NULL;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &panel, nil
+}
+
+func (p *postgresPanels) GetDeleted(ctx context.Context, boardId string, id string) (*models.Panel, error) {
+	var panel models.Panel
+	err := p.db.DB.GetContext(ctx, &panel, `
+		SELECT * FROM Panels WHERE id=$1 AND board_id=$2 AND deleted_at IS NOT NULL;
+	`, id, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &panel, nil
+}
+
+func (p *postgresPanels) ListByBoard(ctx context.Context, boardId string) ([]models.Panel, error) {
+	panels := make([]models.Panel, 0)
+	err := p.db.DB.SelectContext(ctx, &panels, `
+		SELECT * FROM Panels WHERE board_id=$1 AND deleted_at IS NULL ORDER BY position ASC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return panels, nil
+}
+
+func (p *postgresPanels) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Panel, error) {
+	panels := make([]models.Panel, 0)
+	err := p.db.DB.SelectContext(ctx, &panels, `
+		SELECT * FROM Panels WHERE board_id=$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return panels, nil
+}
+
+func (p *postgresPanels) Create(ctx context.Context, panel models.Panel) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Panels (id, title, position, board_id) VALUES ($1, $2, $3, $4);
+	`, panel.Id, panel.Title, panel.Position, panel.BoardId)
+	return err
+}
+
+func (p *postgresPanels) Update(ctx context.Context, panel models.Panel) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Panels SET title=$1, position=$2 WHERE id=$3;
+	`, panel.Title, panel.Position, panel.Id)
+	return err
+}
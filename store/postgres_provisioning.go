@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+)
+
+type postgresProvisioning struct {
+	db *db.DB
+}
+
+func (p *postgresProvisioning) CompletedSteps(ctx context.Context, resourceId string) (map[string]string, error) {
+	var rows []struct {
+		Step   string `db:"step"`
+		Result string `db:"result"`
+	}
+	err := p.db.DB.SelectContext(ctx, &rows, `
+		SELECT step, result FROM Provisioning_Steps WHERE resource_id=$1;
+	`, resourceId)
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[string]string, len(rows))
+	for _, row := range rows {
+		steps[row.Step] = row.Result
+	}
+	return steps, nil
+}
+
+func (p *postgresProvisioning) CompleteStep(ctx context.Context, resourceId string, step string, result string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Provisioning_Steps (resource_id, step, result) VALUES ($1, $2, $3)
+			ON CONFLICT (resource_id, step) DO UPDATE SET result=EXCLUDED.result, completed_at=NOW();
+	`, resourceId, step, result)
+	return err
+}
+
+func (p *postgresProvisioning) UndoStep(ctx context.Context, resourceId string, step string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Provisioning_Steps WHERE resource_id=$1 AND step=$2;
+	`, resourceId, step)
+	return err
+}
+
+func (p *postgresProvisioning) Clear(ctx context.Context, resourceId string) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Provisioning_Steps WHERE resource_id=$1;
+	`, resourceId)
+	return err
+}
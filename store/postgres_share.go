@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresShares struct {
+	db *db.DB
+}
+
+func (p *postgresShares) Get(ctx context.Context, boardId string, id string) (*models.BoardShare, error) {
+	var share models.BoardShare
+	err := p.db.DB.GetContext(ctx, &share, `
+		SELECT * FROM Board_Shares WHERE id=$1 AND board_id=$2;
+	`, id, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (p *postgresShares) GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.BoardShare, error) {
+	var share models.BoardShare
+	err := p.db.DB.GetContext(ctx, &share, `
+		SELECT * FROM Board_Shares WHERE token_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2);
+	`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (p *postgresShares) ListByBoard(ctx context.Context, boardId string) ([]models.BoardShare, error) {
+	shares := make([]models.BoardShare, 0)
+	err := p.db.DB.SelectContext(ctx, &shares, `
+		SELECT * FROM Board_Shares WHERE board_id=$1 AND revoked_at IS NULL ORDER BY created_at ASC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (p *postgresShares) Create(ctx context.Context, share models.BoardShare, expiresAt *time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Board_Shares (id, board_id, created_by, token_hash, password_hash, expires_at) VALUES ($1, $2, $3, $4, $5, $6);
+	`, share.Id, share.BoardId, share.CreatedBy, share.TokenHash, share.PasswordHash, expiresAt)
+	return err
+}
+
+func (p *postgresShares) Revoke(ctx context.Context, boardId string, id string, revokedAt time.Time) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Board_Shares SET revoked_at=$1 WHERE id=$2 AND board_id=$3 AND revoked_at IS NULL;
+	`, revokedAt, id, boardId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresShares) RecordAccess(ctx context.Context, id string, accessedAt time.Time) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Board_Shares SET access_count=access_count+1, last_accessed_at=$1 WHERE id=$2;
+	`, accessedAt, id)
+	return err
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+type postgresStacks struct {
+	postgresPositioned
+}
+
+func (p *postgresStacks) Get(ctx context.Context, id string) (*models.Stack, error) {
+	var stack models.Stack
+	err := p.db.DB.GetContext(ctx, &stack, `
+		SELECT * FROM Stacks WHERE id=$1 AND deleted_at IS NULL;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
+
+func (p *postgresStacks) GetDeleted(ctx context.Context, boardId string, id string) (*models.Stack, error) {
+	var stack models.Stack
+	err := p.db.DB.GetContext(ctx, &stack, `
+		SELECT s.*
+			FROM Stacks s
+				JOIN Panels p ON s.panel_id = p.id
+			WHERE s.id = $1 AND p.board_id = $2 AND s.deleted_at IS NOT NULL;
+	`, id, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
+
+func (p *postgresStacks) ListByPanel(ctx context.Context, panelId string) ([]models.Stack, error) {
+	stacks := make([]models.Stack, 0)
+	err := p.db.DB.SelectContext(ctx, &stacks, `
+		SELECT * FROM Stacks WHERE panel_id=$1 AND deleted_at IS NULL ORDER BY position ASC;
+	`, panelId)
+	if err != nil {
+		return nil, err
+	}
+	return stacks, nil
+}
+
+func (p *postgresStacks) ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Stack, error) {
+	stacks := make([]models.Stack, 0)
+	err := p.db.DB.SelectContext(ctx, &stacks, `
+		SELECT s.*
+			FROM Stacks s
+				JOIN Panels p ON s.panel_id = p.id
+			WHERE p.board_id = $1 AND s.deleted_at IS NOT NULL
+			ORDER BY s.deleted_at DESC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return stacks, nil
+}
+
+func (p *postgresStacks) IsInBoard(ctx context.Context, id string, boardId string) (bool, error) {
+	var isStackInBoard bool
+	err := p.db.DB.GetContext(ctx, &isStackInBoard, `
+		SELECT EXISTS(
+			SELECT 1
+				FROM Stacks s
+					JOIN panels p on s.panel_id = p.id
+					JOIN boards b on p.board_id = b.id
+				WHERE s.id = $1 AND b.id = $2 AND s.deleted_at IS NULL AND p.deleted_at IS NULL
+		);
+	`, id, boardId)
+	if err != nil {
+		return false, err
+	}
+	return isStackInBoard, nil
+}
+
+func (p *postgresStacks) Create(ctx context.Context, stack models.Stack) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Stacks (id, title, position, panel_id) VALUES ($1, $2, $3, $4);
+	`, stack.Id, stack.Title, stack.Position, stack.PanelId)
+	return err
+}
+
+func (p *postgresStacks) Update(ctx context.Context, stack models.Stack) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Stacks SET title=$1, position=$2 WHERE id=$3;
+	`, stack.Title, stack.Position, stack.Id)
+	return err
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Sync-Space-49/syncspace-server/db"
+	"github.com/Sync-Space-49/syncspace-server/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresTeams struct {
+	db *db.DB
+}
+
+func (p *postgresTeams) Get(ctx context.Context, organizationId string, id string) (*models.Team, error) {
+	var team models.Team
+	err := p.db.DB.GetContext(ctx, &team, `
+		SELECT * FROM Teams WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (p *postgresTeams) ListByOrganization(ctx context.Context, organizationId string) ([]models.Team, error) {
+	teams := make([]models.Team, 0)
+	err := p.db.DB.SelectContext(ctx, &teams, `
+		SELECT * FROM Teams WHERE organization_id=$1 ORDER BY LOWER(name) ASC;
+	`, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (p *postgresTeams) Create(ctx context.Context, team models.Team) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Teams (id, organization_id, name, description, role_id, created_by) VALUES ($1, $2, $3, $4, $5, $6);
+	`, team.Id, team.OrganizationId, team.Name, team.Description, team.RoleId, team.CreatedBy)
+	return err
+}
+
+func (p *postgresTeams) Update(ctx context.Context, team models.Team) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		UPDATE Teams SET name=$1, description=$2 WHERE id=$3 AND organization_id=$4;
+	`, team.Name, team.Description, team.Id, team.OrganizationId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresTeams) Delete(ctx context.Context, organizationId string, id string) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Teams WHERE id=$1 AND organization_id=$2;
+	`, id, organizationId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *postgresTeams) ListBoards(ctx context.Context, teamId string) ([]models.TeamBoard, error) {
+	teamBoards := make([]models.TeamBoard, 0)
+	err := p.db.DB.SelectContext(ctx, &teamBoards, `
+		SELECT * FROM Team_Boards WHERE team_id=$1 ORDER BY created_at ASC;
+	`, teamId)
+	if err != nil {
+		return nil, err
+	}
+	return teamBoards, nil
+}
+
+func (p *postgresTeams) ListByBoard(ctx context.Context, boardId string) ([]models.TeamBoard, error) {
+	teamBoards := make([]models.TeamBoard, 0)
+	err := p.db.DB.SelectContext(ctx, &teamBoards, `
+		SELECT * FROM Team_Boards WHERE board_id=$1 ORDER BY created_at ASC;
+	`, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return teamBoards, nil
+}
+
+func (p *postgresTeams) ListBoardIds(ctx context.Context, teamIds []string) ([]string, error) {
+	boardIds := make([]string, 0)
+	if len(teamIds) == 0 {
+		return boardIds, nil
+	}
+	query, args, err := sqlx.In(`SELECT DISTINCT board_id FROM Team_Boards WHERE team_id IN (?)`, teamIds)
+	if err != nil {
+		return nil, err
+	}
+	query = p.db.DB.Rebind(query)
+	err = p.db.DB.SelectContext(ctx, &boardIds, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return boardIds, nil
+}
+
+func (p *postgresTeams) AddBoard(ctx context.Context, teamBoard models.TeamBoard) error {
+	_, err := p.db.DB.ExecContext(ctx, `
+		INSERT INTO Team_Boards (team_id, board_id, role, added_by) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (team_id, board_id) DO UPDATE SET role=EXCLUDED.role;
+	`, teamBoard.TeamId, teamBoard.BoardId, teamBoard.Role, teamBoard.AddedBy)
+	return err
+}
+
+func (p *postgresTeams) RemoveBoard(ctx context.Context, teamId string, boardId string) error {
+	result, err := p.db.DB.ExecContext(ctx, `
+		DELETE FROM Team_Boards WHERE team_id=$1 AND board_id=$2;
+	`, teamId, boardId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
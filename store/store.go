@@ -0,0 +1,306 @@
+// Package store holds the repositories the controllers read and write through. NewPostgres is what
+// the server runs on; NewMemory keeps everything in maps so controller logic can be unit tested
+// without a database.
+//
+// Getters return sql.ErrNoRows when nothing matches, so controllers.NotFound works the same no
+// matter which implementation is behind them.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/Sync-Space-49/syncspace-server/models"
+)
+
+// End can be passed as the upper bound to ShiftPositions to shift everything after from
+const End = math.MaxInt16
+
+type Store struct {
+	Organizations OrganizationRepository
+	Boards        BoardRepository
+	Panels        PanelRepository
+	Stacks        StackRepository
+	Cards         CardRepository
+	Assignments   AssignmentRepository
+	Favourites    FavouriteRepository
+	Shares        BoardShareRepository
+	Invitations   InvitationRepository
+	InviteLinks   InviteLinkRepository
+	Domains       DomainRepository
+	Teams         TeamRepository
+	Credentials   CredentialRepository
+	Provisioning  ProvisioningRepository
+	Jobs          JobRepository
+}
+
+type OrganizationRepository interface {
+	Provisioned
+	Get(ctx context.Context, id string) (*models.Organization, error)
+	ListByIds(ctx context.Context, ids []string) ([]models.Organization, error)
+	ListByOwner(ctx context.Context, ownerId string) ([]models.Organization, error)
+	// Create inserts the organization with its provisioning_status, or ready if it has none
+	Create(ctx context.Context, organization models.Organization) error
+	// Update saves the organization's name, description and ai_enabled
+	Update(ctx context.Context, organization models.Organization) error
+	DeleteByOwner(ctx context.Context, ownerId string) error
+	// ListByProvisioningStatus lists the organizations with status that were created before cutoff
+	ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Organization, error)
+}
+
+// Provisioned is implemented by organizations and boards, which have Auth0 roles and permissions
+// set up after they are created
+type Provisioned interface {
+	SetProvisioningStatus(ctx context.Context, id string, status string) error
+	// ClaimProvisioning takes over provisioning the row to resume or undo it, as long as it still
+	// has status and nobody else claimed it since staleBefore. It returns false otherwise, so only
+	// one caller works on it at a time.
+	ClaimProvisioning(ctx context.Context, id string, status string, staleBefore time.Time) (bool, error)
+	// Delete permanently deletes the row. Boards are only deleted like this when nothing is on them
+	// yet, like when their provisioning failed; otherwise they go through the trash.
+	Delete(ctx context.Context, id string) error
+}
+
+// Versioned is implemented by anything clients send a version for when changing it
+type Versioned interface {
+	// ClaimVersion bumps the version if it still matches the one given and the row isn't in the
+	// trash. It returns false if it didn't.
+	ClaimVersion(ctx context.Context, id string, version int) (bool, error)
+}
+
+// Positioned is what panels, stacks and cards have in common: they are ordered by position under
+// a parent, a board, panel and stack respectively, and are soft deleted into the trash
+type Positioned interface {
+	Versioned
+	// NextPosition returns the position after the last row under parentId that isn't in the trash,
+	// or 0 if there are none
+	NextPosition(ctx context.Context, parentId string) (int, error)
+	// ShiftPositions adds delta to the position of every row under parentId that isn't in the trash
+	// and has a position from from to to inclusive, bumping their versions
+	ShiftPositions(ctx context.Context, parentId string, from int, to int, delta int) error
+	// SoftDelete moves the row to the trash. It keeps its position so it can be restored there.
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+	// Restore takes the row out of the trash at position
+	Restore(ctx context.Context, id string, position int) error
+}
+
+type BoardRepository interface {
+	Versioned
+	Provisioned
+	Get(ctx context.Context, id string) (*models.Board, error)
+	GetDeleted(ctx context.Context, organizationId string, id string) (*models.Board, error)
+	ListByOrganization(ctx context.Context, organizationId string, includeArchived bool) ([]models.Board, error)
+	ListDeletedByOrganization(ctx context.Context, organizationId string) ([]models.Board, error)
+	ListByIds(ctx context.Context, ids []string, includeArchived bool) ([]models.Board, error)
+	ListByOwner(ctx context.Context, ownerId string, includeArchived bool) ([]models.Board, error)
+	// Create inserts the board with its provisioning_status, or ready if it has none
+	Create(ctx context.Context, board models.Board) error
+	// Update saves the board's title, description, is_private and owner_id
+	Update(ctx context.Context, board models.Board, modifiedAt time.Time) error
+	// Touch sets the board's modified_at
+	Touch(ctx context.Context, id string, modifiedAt time.Time) error
+	IsArchived(ctx context.Context, id string) (bool, error)
+	Archive(ctx context.Context, id string, archivedAt time.Time) error
+	Unarchive(ctx context.Context, id string, modifiedAt time.Time) error
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+	Restore(ctx context.Context, id string, modifiedAt time.Time) error
+	DeleteByOwner(ctx context.Context, ownerId string) error
+	// PurgeDeleted permanently deletes every board, panel, stack and card that was put in the trash
+	// before cutoff, along with everything under them and their card assignments. It returns the
+	// boards it deleted, which leaves out any restored in the meantime.
+	PurgeDeleted(ctx context.Context, cutoff time.Time) ([]models.Board, error)
+	// ListByProvisioningStatus lists the boards with status that were created before cutoff, in the
+	// trash or not
+	ListByProvisioningStatus(ctx context.Context, status string, cutoff time.Time) ([]models.Board, error)
+}
+
+type PanelRepository interface {
+	Positioned
+	Get(ctx context.Context, id string) (*models.Panel, error)
+	GetDeleted(ctx context.Context, boardId string, id string) (*models.Panel, error)
+	ListByBoard(ctx context.Context, boardId string) ([]models.Panel, error)
+	ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Panel, error)
+	Create(ctx context.Context, panel models.Panel) error
+	// Update saves the panel's title and position
+	Update(ctx context.Context, panel models.Panel) error
+}
+
+type StackRepository interface {
+	Positioned
+	Get(ctx context.Context, id string) (*models.Stack, error)
+	GetDeleted(ctx context.Context, boardId string, id string) (*models.Stack, error)
+	ListByPanel(ctx context.Context, panelId string) ([]models.Stack, error)
+	ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Stack, error)
+	// IsInBoard reports whether the stack and its panel are on the board and not in the trash
+	IsInBoard(ctx context.Context, id string, boardId string) (bool, error)
+	Create(ctx context.Context, stack models.Stack) error
+	// Update saves the stack's title and position
+	Update(ctx context.Context, stack models.Stack) error
+}
+
+type CardRepository interface {
+	Positioned
+	Get(ctx context.Context, id string) (*models.Card, error)
+	GetDeleted(ctx context.Context, boardId string, id string) (*models.Card, error)
+	ListByStack(ctx context.Context, stackId string) ([]models.Card, error)
+	ListDeletedByBoard(ctx context.Context, boardId string) ([]models.Card, error)
+	Create(ctx context.Context, card models.Card) error
+	// Update saves the card's title, description, points, position and stack_id
+	Update(ctx context.Context, card models.Card) error
+}
+
+type AssignmentRepository interface {
+	Assign(ctx context.Context, cardId string, userId string) error
+	Unassign(ctx context.Context, cardId string, userId string) error
+	ListUserIds(ctx context.Context, cardId string) ([]string, error)
+	ListCardIds(ctx context.Context, userId string) ([]string, error)
+	// ListDetailedByUser lists the cards assigned to the user that aren't in the trash, along with
+	// where they are
+	ListDetailedByUser(ctx context.Context, userId string) ([]models.DetailedAssignedCard, error)
+	DeleteByUser(ctx context.Context, userId string) error
+}
+
+type FavouriteRepository interface {
+	// ListBoards lists the user's favourite boards that aren't in the trash
+	ListBoards(ctx context.Context, userId string) ([]models.Board, error)
+	Add(ctx context.Context, userId string, boardId string) error
+	Remove(ctx context.Context, userId string, boardId string) error
+}
+
+type BoardShareRepository interface {
+	Get(ctx context.Context, boardId string, id string) (*models.BoardShare, error)
+	// GetByTokenHash returns the share with tokenHash if it hasn't been revoked or expired by now
+	GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.BoardShare, error)
+	// ListByBoard lists the board's shares that haven't been revoked, oldest first
+	ListByBoard(ctx context.Context, boardId string) ([]models.BoardShare, error)
+	Create(ctx context.Context, share models.BoardShare, expiresAt *time.Time) error
+	Revoke(ctx context.Context, boardId string, id string, revokedAt time.Time) error
+	RecordAccess(ctx context.Context, id string, accessedAt time.Time) error
+}
+
+type InvitationRepository interface {
+	Get(ctx context.Context, organizationId string, id string) (*models.Invitation, error)
+	// GetPendingByTokenHash returns the pending invitation with tokenHash if it expires after now
+	GetPendingByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.Invitation, error)
+	// ListPending lists the organization's pending invitations, expired or not, oldest first
+	ListPending(ctx context.Context, organizationId string) ([]models.Invitation, error)
+	HasPending(ctx context.Context, organizationId string, email string) (bool, error)
+	Create(ctx context.Context, invitation models.Invitation, expiresAt time.Time) error
+	// Renew gives a pending invitation a new token and expiry. It returns false if the invitation
+	// isn't pending.
+	Renew(ctx context.Context, id string, tokenHash string, expiresAt time.Time) (bool, error)
+	// Respond moves a pending invitation to status. It returns false if the invitation isn't
+	// pending, so an invitation can only be answered once.
+	Respond(ctx context.Context, id string, status string, respondedAt time.Time) (bool, error)
+}
+
+type InviteLinkRepository interface {
+	Get(ctx context.Context, organizationId string, id string) (*models.InviteLink, error)
+	// GetByTokenHash returns the link with tokenHash if it hasn't expired by now. It might have run
+	// out of uses, which Use checks.
+	GetByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.InviteLink, error)
+	ListByOrganization(ctx context.Context, organizationId string) ([]models.InviteLink, error)
+	Create(ctx context.Context, link models.InviteLink, expiresAt *time.Time) error
+	// Use counts a join through the link. It returns false if the link has already been used
+	// max_uses times.
+	Use(ctx context.Context, id string) (bool, error)
+	Delete(ctx context.Context, organizationId string, id string) error
+}
+
+type DomainRepository interface {
+	ListByOrganization(ctx context.Context, organizationId string) ([]models.OrganizationDomain, error)
+	// ListByDomain lists every organization that allows domain
+	ListByDomain(ctx context.Context, domain string) ([]models.OrganizationDomain, error)
+	// Add allows a domain, or changes the role of a domain that is already allowed without changing
+	// its verification
+	Add(ctx context.Context, domain models.OrganizationDomain) error
+	Remove(ctx context.Context, organizationId string, domain string) error
+	Verify(ctx context.Context, organizationId string, domain string) error
+	HasJoined(ctx context.Context, organizationId string, userId string) (bool, error)
+	RecordJoin(ctx context.Context, organizationId string, userId string) error
+}
+
+type TeamRepository interface {
+	Get(ctx context.Context, organizationId string, id string) (*models.Team, error)
+	// ListByOrganization lists the organization's teams by name
+	ListByOrganization(ctx context.Context, organizationId string) ([]models.Team, error)
+	Create(ctx context.Context, team models.Team) error
+	// Update saves the team's name and description
+	Update(ctx context.Context, team models.Team) error
+	Delete(ctx context.Context, organizationId string, id string) error
+	ListBoards(ctx context.Context, teamId string) ([]models.TeamBoard, error)
+	ListByBoard(ctx context.Context, boardId string) ([]models.TeamBoard, error)
+	// ListBoardIds lists the ids of every board any of the teams is on
+	ListBoardIds(ctx context.Context, teamIds []string) ([]string, error)
+	// AddBoard puts the team on the board, or changes its role if it is already on it
+	AddBoard(ctx context.Context, teamBoard models.TeamBoard) error
+	RemoveBoard(ctx context.Context, teamId string, boardId string) error
+}
+
+// CredentialRepository holds personal access tokens and API keys. Only their hashes are stored, so
+// they are looked up by the hash of what was sent.
+type CredentialRepository interface {
+	GetPersonalAccessToken(ctx context.Context, userId string, id string) (*models.PersonalAccessToken, error)
+	// GetPersonalAccessTokenByHash returns the token with tokenHash if it hasn't expired by now
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error)
+	// ListPersonalAccessTokens lists the user's tokens, expired or not, oldest first
+	ListPersonalAccessTokens(ctx context.Context, userId string) ([]models.PersonalAccessToken, error)
+	CreatePersonalAccessToken(ctx context.Context, token models.PersonalAccessToken, expiresAt *time.Time) error
+	DeletePersonalAccessToken(ctx context.Context, userId string, id string) error
+	// MarkPersonalAccessTokenUsed sets when the token was last used, unless that was less than a
+	// minute before usedAt, so busy scripts don't write on every request
+	MarkPersonalAccessTokenUsed(ctx context.Context, id string, usedAt time.Time) error
+	GetAPIKey(ctx context.Context, organizationId string, id string) (*models.APIKey, error)
+	// GetAPIKeyByHash returns the key with tokenHash if it hasn't expired by now
+	GetAPIKeyByHash(ctx context.Context, tokenHash string, now time.Time) (*models.APIKey, error)
+	// ListAPIKeys lists the organization's keys, expired or not, oldest first
+	ListAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error)
+	CreateAPIKey(ctx context.Context, key models.APIKey, expiresAt *time.Time) error
+	DeleteAPIKey(ctx context.Context, organizationId string, id string) error
+	// MarkAPIKeyUsed is MarkPersonalAccessTokenUsed for API keys
+	MarkAPIKeyUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// ProvisioningRepository records which steps of provisioning an organization or board have
+// finished, so it can be resumed or undone after the server restarts
+type ProvisioningRepository interface {
+	// CompletedSteps returns the result of every step of the resource's provisioning that has
+	// finished, by step name
+	CompletedSteps(ctx context.Context, resourceId string) (map[string]string, error)
+	// CompleteStep records the step as finished, replacing its result if it already was
+	CompleteStep(ctx context.Context, resourceId string, step string, result string) error
+	// UndoStep forgets a step once it has been undone
+	UndoStep(ctx context.Context, resourceId string, step string) error
+	// Clear forgets every step of the resource's provisioning once it no longer needs resuming
+	Clear(ctx context.Context, resourceId string) error
+}
+
+type JobRepository interface {
+	Get(ctx context.Context, id string) (*models.Job, error)
+	// List lists up to limit jobs with status, or with any status if it is empty, newest first
+	List(ctx context.Context, status string, limit int) ([]models.Job, error)
+	// ListActive lists the queued and running jobs of jobType
+	ListActive(ctx context.Context, jobType string) ([]models.Job, error)
+	Create(ctx context.Context, job models.Job, runAt time.Time) error
+	// CreateOnce creates the job unless one with its id exists already, in which case it returns
+	// false
+	CreateOnce(ctx context.Context, job models.Job, runAt time.Time) (bool, error)
+	// Claim marks the queued job that has been due the longest by now as running, counts the
+	// attempt and returns it. Running jobs locked before staleBefore are claimed again, since the
+	// worker running them must have stopped. Each job is only claimed by one caller at a time, and
+	// it returns sql.ErrNoRows when there's nothing to claim.
+	Claim(ctx context.Context, now time.Time, staleBefore time.Time) (*models.Job, error)
+	// Succeed and Fail record how the attempt of job that was claimed went. They return false if the
+	// job has been claimed again since, like after the attempt ran for too long, in which case the
+	// attempt's result is dropped.
+	Succeed(ctx context.Context, job models.Job, finishedAt time.Time) (bool, error)
+	// Fail records lastError and queues the job again at retryAt, or moves it to dead if retryAt
+	// is nil
+	Fail(ctx context.Context, job models.Job, lastError string, retryAt *time.Time, failedAt time.Time) (bool, error)
+	// Retry queues a dead job again at runAt with its attempts and progress reset. It returns false
+	// if the job isn't dead.
+	Retry(ctx context.Context, id string, runAt time.Time) (bool, error)
+	SetProgress(ctx context.Context, id string, progress json.RawMessage) error
+}